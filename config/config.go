@@ -15,6 +15,8 @@ type (
 		PG      PG
 		Metrics Metrics
 		Swagger Swagger
+		Auth    Auth
+		PII     PII
 	}
 
 	// App -.
@@ -47,6 +49,29 @@ type (
 	Swagger struct {
 		Enabled bool `env:"SWAGGER_ENABLED" envDefault:"false"`
 	}
+
+	// Auth configures how API requests are authenticated. JWTs are accepted when signed with HS256
+	// and JWTSecret or with RS256 and the PEM encoded JWTPublicKey. APIKeys maps names to keys, e.g. importer:s3cr3t.
+	// Admins lists the subjects with the admin role on every group, who grant roles to everyone else.
+	Auth struct {
+		Enabled      bool              `env:"AUTH_ENABLED"        envDefault:"true"`
+		JWTSecret    string            `env:"AUTH_JWT_SECRET"`
+		JWTPublicKey string            `env:"AUTH_JWT_PUBLIC_KEY"`
+		JWTIssuer    string            `env:"AUTH_JWT_ISSUER"`
+		JWTAudience  string            `env:"AUTH_JWT_AUDIENCE"`
+		APIKeys      map[string]string `env:"AUTH_API_KEYS"`
+		Admins       []string          `env:"AUTH_ADMINS"`
+	}
+
+	// PII configures how much of the personal data of students callers see. Detail, List and Export
+	// map roles to the view on single students, lists and exports, e.g. write:masked,admin:full.
+	// Roles that are not listed see nothing, ContactRole is needed for the contact details of a student.
+	PII struct {
+		Detail      map[string]string `env:"PII_DETAIL"       envDefault:"write:masked,admin:masked"`
+		List        map[string]string `env:"PII_LIST"         envDefault:"admin:masked"`
+		Export      map[string]string `env:"PII_EXPORT"`
+		ContactRole string            `env:"PII_CONTACT_ROLE" envDefault:"write"`
+	}
 )
 
 // NewConfig returns app config.
@@ -9,9 +9,14 @@ import (
 
 	"github.com/evrone/go-clean-template/config"
 	v1 "github.com/evrone/go-clean-template/internal/controller/http"
+	"github.com/evrone/go-clean-template/internal/entity"
 	"github.com/evrone/go-clean-template/internal/repo/persistent"
 	"github.com/evrone/go-clean-template/internal/repo/webapi"
+	"github.com/evrone/go-clean-template/internal/usecase"
+	"github.com/evrone/go-clean-template/internal/usecase/access"
+	"github.com/evrone/go-clean-template/internal/usecase/audit"
 	"github.com/evrone/go-clean-template/internal/usecase/group"
+	"github.com/evrone/go-clean-template/internal/usecase/privacy"
 	"github.com/evrone/go-clean-template/internal/usecase/student"
 	"github.com/evrone/go-clean-template/internal/usecase/translation"
 	"github.com/evrone/go-clean-template/pkg/httpserver"
@@ -33,6 +38,8 @@ func Run(cfg *config.Config) {
 	translationRepo := persistent.New(pg)
 	studentRepo := persistent.NewStudentRepo(pg)
 	groupRepo := persistent.NewGroupRepo(pg)
+	auditRepo := persistent.NewAuditRepo(pg)
+	grantRepo := persistent.NewGrantRepo(pg)
 	translationWebAPI := webapi.New()
 
 	// Use case
@@ -41,17 +48,50 @@ func Run(cfg *config.Config) {
 		translationWebAPI,
 	)
 
-	studentUseCase := student.New(
-		studentRepo,
+	// Every change to students and groups is recorded in the audit log
+	var (
+		studentUseCase usecase.Student = audit.NewStudent(
+			student.New(studentRepo, groupRepo, pg),
+			auditRepo,
+			pg,
+		)
+		groupUseCase usecase.Group = audit.NewGroup(
+			group.New(groupRepo, pg),
+			auditRepo,
+			pg,
+		)
+		auditUseCase usecase.Audit = audit.New(auditRepo)
 	)
 
-	groupUseCase := group.New(
-		groupRepo,
-	)
+	// Authenticated callers only reach the groups they have been granted a role on
+	if cfg.Auth.Enabled {
+		studentUseCase = access.NewStudent(studentUseCase, grantRepo, groupRepo, pg, cfg.Auth.Admins)
+		groupUseCase = access.NewGroup(groupUseCase, grantRepo, groupRepo, pg, cfg.Auth.Admins)
+		auditUseCase = access.NewAudit(auditUseCase, grantRepo, cfg.Auth.Admins)
+	}
+
+	accessUseCase := access.New(grantRepo, groupRepo, cfg.Auth.Admins)
+
+	// Personal data of students is shown as far as the role of the caller on their group allows
+	var roles privacy.Roles
+	if cfg.Auth.Enabled {
+		roles = access.NewRoles(grantRepo, groupRepo, cfg.Auth.Admins)
+	}
+
+	privacyUseCase := privacy.New(studentUseCase, roles, entity.PIIPolicy{
+		Detail:      cfg.PII.Detail,
+		List:        cfg.PII.List,
+		Export:      cfg.PII.Export,
+		ContactRole: cfg.PII.ContactRole,
+	})
 
 	// HTTP Server
 	httpServer := httpserver.New(httpserver.Port(cfg.HTTP.Port), httpserver.Prefork(cfg.HTTP.UsePreforkMode))
-	v1.NewRouter(httpServer.App, cfg, l, translationUseCase, studentUseCase, groupUseCase)
+	err = v1.NewRouter(httpServer.App, cfg, l, translationUseCase, studentUseCase, groupUseCase, auditUseCase, accessUseCase,
+		privacyUseCase)
+	if err != nil {
+		l.Fatal(fmt.Errorf("app - Run - v1.NewRouter: %w", err))
+	}
 
 	// Start servers
 	httpServer.Start()
@@ -0,0 +1,159 @@
+package middleware
+
+import (
+	"crypto/rsa"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/evrone/go-clean-template/config"
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	_bearerPrefix = "Bearer "
+	_headerAPIKey = "X-API-Key"
+)
+
+var (
+	errNoCredentials = errors.New("missing credentials, send a bearer token in Authorization or an API key in X-API-Key")
+	errInvalidAPIKey = errors.New("invalid API key")
+	errNoSubject     = errors.New("token has no subject")
+	errNoJWT         = errors.New("bearer tokens are not accepted, no JWT key is configured")
+)
+
+type authenticator struct {
+	jwt       bool
+	secret    []byte
+	publicKey *rsa.PublicKey
+	parser    *jwt.Parser
+	apiKeys   map[string]string
+}
+
+// Auth authenticates requests with a JWT bearer token or a static API key and passes the principal
+// to use cases in the user context. Requests without valid credentials are rejected with 401.
+// Routes registered before the middleware, like probes and metrics, stay open.
+func Auth(cfg config.Auth) (func(c *fiber.Ctx) error, error) {
+	if !cfg.Enabled {
+		return func(ctx *fiber.Ctx) error { return ctx.Next() }, nil
+	}
+
+	a := &authenticator{apiKeys: cfg.APIKeys}
+
+	var methods []string
+
+	if cfg.JWTSecret != "" {
+		a.secret = []byte(cfg.JWTSecret)
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+
+	if cfg.JWTPublicKey != "" {
+		key, err := jwt.ParseRSAPublicKeyFromPEM([]byte(cfg.JWTPublicKey))
+		if err != nil {
+			return nil, fmt.Errorf("middleware - Auth - jwt.ParseRSAPublicKeyFromPEM: %w", err)
+		}
+
+		a.publicKey = key
+		methods = append(methods, jwt.SigningMethodRS256.Alg())
+	}
+
+	options := []jwt.ParserOption{jwt.WithValidMethods(methods), jwt.WithExpirationRequired()}
+
+	if cfg.JWTIssuer != "" {
+		options = append(options, jwt.WithIssuer(cfg.JWTIssuer))
+	}
+
+	if cfg.JWTAudience != "" {
+		options = append(options, jwt.WithAudience(cfg.JWTAudience))
+	}
+
+	a.jwt = len(methods) > 0
+	a.parser = jwt.NewParser(options...)
+
+	return a.handle, nil
+}
+
+func (a *authenticator) handle(ctx *fiber.Ctx) error {
+	var (
+		principal entity.Principal
+		err       error
+	)
+
+	switch {
+	case strings.HasPrefix(ctx.Get(fiber.HeaderAuthorization), _bearerPrefix):
+		principal, err = a.verifyToken(strings.TrimPrefix(ctx.Get(fiber.HeaderAuthorization), _bearerPrefix))
+	case ctx.Get(_headerAPIKey) != "":
+		principal, err = a.verifyAPIKey(ctx.Get(_headerAPIKey))
+	default:
+		err = errNoCredentials
+	}
+
+	if err != nil {
+		return unauthorized(ctx, err)
+	}
+
+	ctx.SetUserContext(entity.WithPrincipal(ctx.UserContext(), principal))
+
+	return ctx.Next()
+}
+
+// verifyToken checks the signature and claims of a JWT.
+func (a *authenticator) verifyToken(raw string) (entity.Principal, error) {
+	if !a.jwt {
+		return entity.Principal{}, errNoJWT
+	}
+
+	var claims jwt.RegisteredClaims
+
+	_, err := a.parser.ParseWithClaims(raw, &claims, func(t *jwt.Token) (any, error) {
+		if t.Method.Alg() == jwt.SigningMethodRS256.Alg() {
+			return a.publicKey, nil
+		}
+
+		return a.secret, nil
+	})
+	if err != nil {
+		return entity.Principal{}, err
+	}
+
+	if claims.Subject == "" {
+		return entity.Principal{}, errNoSubject
+	}
+
+	return entity.Principal{Subject: claims.Subject, Method: entity.AuthJWT}, nil
+}
+
+// verifyAPIKey looks up the name of an API key, comparing every configured key in constant time.
+func (a *authenticator) verifyAPIKey(key string) (entity.Principal, error) {
+	var subject string
+
+	for name, k := range a.apiKeys {
+		if subtle.ConstantTimeCompare([]byte(k), []byte(key)) == 1 {
+			subject = name
+		}
+	}
+
+	if subject == "" {
+		return entity.Principal{}, errInvalidAPIKey
+	}
+
+	return entity.Principal{Subject: subject, Method: entity.AuthAPIKey}, nil
+}
+
+// unauthorized rejects a request with a problem details response like the ones of the API.
+func unauthorized(ctx *fiber.Ctx, err error) error {
+	ctx.Set(fiber.HeaderWWWAuthenticate, `Bearer realm="api"`)
+
+	return ctx.Status(http.StatusUnauthorized).JSON(fiber.Map{
+		"type":     "about:blank",
+		"title":    http.StatusText(http.StatusUnauthorized),
+		"status":   http.StatusUnauthorized,
+		"detail":   err.Error(),
+		"instance": ctx.Path(),
+		"code":     "unauthorized",
+	}, "application/problem+json")
+}
@@ -0,0 +1,247 @@
+package middleware_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/evrone/go-clean-template/config"
+	"github.com/evrone/go-clean-template/internal/controller/http/middleware"
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	_secret = "test-secret"
+	_issuer = "https://auth.example.com"
+)
+
+type authTest struct {
+	name      string
+	header    string
+	value     string
+	status    int
+	principal string
+}
+
+// authApp serves the principal of authenticated requests.
+func authApp(t *testing.T, cfg config.Auth) *fiber.App {
+	t.Helper()
+
+	auth, err := middleware.Auth(cfg)
+	require.NoError(t, err)
+
+	app := fiber.New()
+	app.Use(auth)
+	app.Get("/", func(ctx *fiber.Ctx) error {
+		p, _ := entity.PrincipalFrom(ctx.UserContext())
+
+		return ctx.SendString(p.Method + ":" + p.Subject)
+	})
+
+	return app
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, key any, claims jwt.RegisteredClaims) string {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	require.NoError(t, err)
+
+	return "Bearer " + token
+}
+
+func claims(subject string, expires time.Duration) jwt.RegisteredClaims {
+	return jwt.RegisteredClaims{
+		Subject:   subject,
+		Issuer:    _issuer,
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(expires)),
+	}
+}
+
+func runAuthTests(t *testing.T, app *fiber.App, tests []authTest) {
+	t.Helper()
+
+	for _, tc := range tests {
+		localTc := tc
+
+		t.Run(localTc.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+			if localTc.header != "" {
+				req.Header.Set(localTc.header, localTc.value)
+			}
+
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+
+			defer resp.Body.Close()
+
+			require.Equal(t, localTc.status, resp.StatusCode)
+
+			if localTc.status != http.StatusOK {
+				require.Equal(t, "application/problem+json", resp.Header.Get(fiber.HeaderContentType))
+				require.NotEmpty(t, resp.Header.Get(fiber.HeaderWWWAuthenticate))
+
+				return
+			}
+
+			body := make([]byte, len(localTc.principal))
+			_, _ = resp.Body.Read(body)
+			require.Equal(t, localTc.principal, string(body))
+		})
+	}
+}
+
+func TestAuthHS256(t *testing.T) {
+	t.Parallel()
+
+	app := authApp(t, config.Auth{
+		Enabled:   true,
+		JWTSecret: _secret,
+		JWTIssuer: _issuer,
+		APIKeys:   map[string]string{"importer": "importer-key"},
+	})
+
+	runAuthTests(t, app, []authTest{
+		{
+			name:      "valid token",
+			header:    fiber.HeaderAuthorization,
+			value:     sign(t, jwt.SigningMethodHS256, []byte(_secret), claims("admin", time.Hour)),
+			status:    http.StatusOK,
+			principal: "jwt:admin",
+		},
+		{
+			name:   "wrong secret",
+			header: fiber.HeaderAuthorization,
+			value:  sign(t, jwt.SigningMethodHS256, []byte("other-secret"), claims("admin", time.Hour)),
+			status: http.StatusUnauthorized,
+		},
+		{
+			name:   "expired token",
+			header: fiber.HeaderAuthorization,
+			value:  sign(t, jwt.SigningMethodHS256, []byte(_secret), claims("admin", -time.Minute)),
+			status: http.StatusUnauthorized,
+		},
+		{
+			name:   "no expiry",
+			header: fiber.HeaderAuthorization,
+			value:  sign(t, jwt.SigningMethodHS256, []byte(_secret), jwt.RegisteredClaims{Subject: "admin", Issuer: _issuer}),
+			status: http.StatusUnauthorized,
+		},
+		{
+			name:   "wrong issuer",
+			header: fiber.HeaderAuthorization,
+			value: sign(t, jwt.SigningMethodHS256, []byte(_secret), jwt.RegisteredClaims{
+				Subject:   "admin",
+				Issuer:    "https://evil.example.com",
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			}),
+			status: http.StatusUnauthorized,
+		},
+		{
+			name:   "no subject",
+			header: fiber.HeaderAuthorization,
+			value:  sign(t, jwt.SigningMethodHS256, []byte(_secret), claims("", time.Hour)),
+			status: http.StatusUnauthorized,
+		},
+		{
+			name:   "unsigned token",
+			header: fiber.HeaderAuthorization,
+			value:  sign(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, claims("admin", time.Hour)),
+			status: http.StatusUnauthorized,
+		},
+		{
+			name:   "malformed token",
+			header: fiber.HeaderAuthorization,
+			value:  "Bearer not-a-token",
+			status: http.StatusUnauthorized,
+		},
+		{
+			name:      "valid api key",
+			header:    "X-API-Key",
+			value:     "importer-key",
+			status:    http.StatusOK,
+			principal: "api_key:importer",
+		},
+		{
+			name:   "invalid api key",
+			header: "X-API-Key",
+			value:  "guessed-key",
+			status: http.StatusUnauthorized,
+		},
+		{
+			name:   "basic auth",
+			header: fiber.HeaderAuthorization,
+			value:  "Basic YWRtaW46YWRtaW4=",
+			status: http.StatusUnauthorized,
+		},
+		{
+			name:   "no credentials",
+			status: http.StatusUnauthorized,
+		},
+	})
+}
+
+func TestAuthRS256(t *testing.T) {
+	t.Parallel()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+
+	app := authApp(t, config.Auth{
+		Enabled:      true,
+		JWTPublicKey: string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})),
+	})
+
+	runAuthTests(t, app, []authTest{
+		{
+			name:      "valid token",
+			header:    fiber.HeaderAuthorization,
+			value:     sign(t, jwt.SigningMethodRS256, key, claims("teacher", time.Hour)),
+			status:    http.StatusOK,
+			principal: "jwt:teacher",
+		},
+		{
+			name:   "HS256 token signed with the public key",
+			header: fiber.HeaderAuthorization,
+			value:  sign(t, jwt.SigningMethodHS256, der, claims("teacher", time.Hour)),
+			status: http.StatusUnauthorized,
+		},
+		{
+			name:   "no api keys configured",
+			header: "X-API-Key",
+			value:  "importer-key",
+			status: http.StatusUnauthorized,
+		},
+	})
+}
+
+func TestAuthInvalidPublicKey(t *testing.T) {
+	t.Parallel()
+
+	_, err := middleware.Auth(config.Auth{Enabled: true, JWTPublicKey: "not a key"})
+	require.Error(t, err)
+}
+
+func TestAuthDisabled(t *testing.T) {
+	t.Parallel()
+
+	runAuthTests(t, authApp(t, config.Auth{}), []authTest{
+		{
+			name:   "no credentials",
+			status: http.StatusOK,
+		},
+	})
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// _maxRequestIDLength limits request IDs taken from clients to the size stored in the audit log.
+const _maxRequestIDLength = 64
+
+// RequestID takes the request ID from the X-Request-ID header or generates one, returns it in the response
+// and passes it to use cases in the user context, so that the changes made by a request can be traced.
+func RequestID() func(c *fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		id := ctx.Get(fiber.HeaderXRequestID)
+		if id == "" || len(id) > _maxRequestIDLength {
+			id = utils.UUIDv4()
+		}
+
+		ctx.Set(fiber.HeaderXRequestID, id)
+		ctx.SetUserContext(entity.WithRequestID(ctx.UserContext(), id))
+
+		return ctx.Next()
+	}
+}
@@ -2,6 +2,7 @@
 package http
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/ansrivas/fiberprometheus/v2"
@@ -15,17 +16,32 @@ import (
 	"github.com/gofiber/swagger"
 )
 
-// NewRouter -.
+// NewRouter -. Every route registered after the auth middleware requires credentials.
 // Swagger spec:
 // @title       Educational Institution API
 // @description RESTful API for managing students and academic groups
 // @version     1.0
 // @host        localhost:8080
 // @BasePath    /
-func NewRouter(app *fiber.App, cfg *config.Config, l logger.Interface, t usecase.Translation, s usecase.Student, g usecase.Group) {
+// @securityDefinitions.apikey BearerAuth
+// @in                         header
+// @name                       Authorization
+// @description                JWT signed with HS256 or RS256, sent as "Bearer <token>"
+// @securityDefinitions.apikey ApiKeyAuth
+// @in                         header
+// @name                       X-API-Key
+func NewRouter(app *fiber.App, cfg *config.Config, l logger.Interface, t usecase.Translation, s usecase.Student, g usecase.Group,
+	a usecase.Audit, ac usecase.Access, p usecase.Privacy,
+) error {
+	auth, err := middleware.Auth(cfg.Auth)
+	if err != nil {
+		return fmt.Errorf("http - NewRouter - middleware.Auth: %w", err)
+	}
+
 	// Options
 	app.Use(middleware.Logger(l))
 	app.Use(middleware.Recovery(l))
+	app.Use(middleware.RequestID())
 
 	// Prometheus metrics
 	if cfg.Metrics.Enabled {
@@ -42,6 +58,9 @@ func NewRouter(app *fiber.App, cfg *config.Config, l logger.Interface, t usecase
 	// K8s probe
 	app.Get("/healthz", func(ctx *fiber.Ctx) error { return ctx.SendStatus(http.StatusOK) })
 
+	// Authentication, the routes above stay open
+	app.Use(auth)
+
 	// Legacy routes (if needed)
 	apiV1Group := app.Group("/v1")
 	{
@@ -49,6 +68,10 @@ func NewRouter(app *fiber.App, cfg *config.Config, l logger.Interface, t usecase
 	}
 
 	// Educational institution API routes
-	v1.NewStudentRoutes(app, s, l)
+	v1.NewStudentRoutes(app, s, p, l)
 	v1.NewGroupRoutes(app, g, l)
+	v1.NewAuditRoutes(app, a, l)
+	v1.NewGrantRoutes(app, ac, l)
+
+	return nil
 }
@@ -0,0 +1,106 @@
+package v1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/usecase"
+	"github.com/evrone/go-clean-template/pkg/logger"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type auditRoutes struct {
+	a usecase.Audit
+	l logger.Interface
+	v *validator.Validate
+}
+
+func NewAuditRoutes(router fiber.Router, a usecase.Audit, l logger.Interface) {
+	r := &auditRoutes{a, l, newValidator()}
+
+	router.Get("/audit", r.getAudit)
+}
+
+type auditQuery struct {
+	Limit     int       `query:"limit"      validate:"omitempty,min=1,max=500"`
+	Cursor    string    `query:"cursor"`
+	Sort      string    `query:"sort"       validate:"omitempty,oneof=id -id"`
+	Entity    string    `query:"entity"     validate:"omitempty,oneof=student group"`
+	ID        int       `query:"id"         validate:"omitempty,min=1"`
+	Actor     string    `query:"actor"`
+	Action    string    `query:"action"     validate:"omitempty,oneof=create update delete restore move merge"`
+	RequestID string    `query:"request_id"`
+	Since     time.Time `query:"since"`
+	Until     time.Time `query:"until"`
+}
+
+type auditListResponse struct {
+	Items      []entity.AuditEntry `json:"items"`
+	NextCursor string              `json:"next_cursor,omitempty"`
+	Total      int                 `json:"total"`
+}
+
+// @Summary     Get audit log
+// @Description Retrieve a page of recorded changes to students and groups, newest first by default
+// @ID          get-audit
+// @Tags  	    audit
+// @Accept      json
+// @Produce     json
+// @Param       entity     query string false "Only changes to this kind of entity" Enums(student, group)
+// @Param       id         query int    false "Only changes to the entity with this ID"
+// @Param       actor      query string false "Only changes made by this actor"
+// @Param       action     query string false "Only this kind of change" Enums(create, update, delete, restore, move, merge)
+// @Param       request_id query string false "Only changes made by this request"
+// @Param       since      query string false "Only changes made at or after this time (RFC 3339)"
+// @Param       until      query string false "Only changes made before this time (RFC 3339)"
+// @Param       limit      query int    false "Page size (1-500, default 50)"
+// @Param       cursor     query string false "Cursor returned as next_cursor by the previous page"
+// @Param       sort       query string false "Sort order (default -id)" Enums(id, -id)
+// @Success     200 {object} auditListResponse
+// @Failure     400 {object} problem
+// @Failure     403 {object} problem
+// @Failure     500 {object} problem
+// @Router      /audit [get]
+func (r *auditRoutes) getAudit(ctx *fiber.Ctx) error {
+	var request auditQuery
+	if err := ctx.QueryParser(&request); err != nil {
+		r.l.Error(err, "http - v1 - getAudit")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid query parameters")
+	}
+
+	if err := r.v.Struct(request); err != nil {
+		return handleError(ctx, r.l, validationError(err), "http - v1 - getAudit - validation")
+	}
+
+	if request.Sort == "" {
+		request.Sort = "-id"
+	}
+
+	page, err := newPageRequest(request.Limit, request.Sort, request.Cursor)
+	if err != nil {
+		return handleError(ctx, r.l, err, "http - v1 - getAudit - newPageRequest")
+	}
+
+	filter := entity.AuditFilter{
+		Entity:    request.Entity,
+		EntityID:  optionalID(request.ID),
+		Actor:     request.Actor,
+		Action:    request.Action,
+		RequestID: request.RequestID,
+		Since:     optionalTime(request.Since),
+		Until:     optionalTime(request.Until),
+	}
+
+	entries, err := r.a.GetAudit(ctx.UserContext(), filter, page)
+	if err != nil {
+		return handleError(ctx, r.l, err, "http - v1 - getAudit - r.a.GetAudit")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(auditListResponse{
+		Items:      entries.Items,
+		NextCursor: encodeCursor(entries.NextCursor),
+		Total:      entries.Total,
+	})
+}
@@ -0,0 +1,46 @@
+package v1
+
+import (
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/gofiber/fiber/v2"
+)
+
+// _batchBestEffort is the batch mode that skips failed operations, batches are atomic by default.
+const _batchBestEffort = "best_effort"
+
+type batchResult[T any] struct {
+	Index  int      `json:"index"           example:"0"`
+	Op     string   `json:"op"              example:"update"`
+	Status string   `json:"status"          example:"applied"`
+	Item   *T       `json:"item,omitempty"`
+	Error  *problem `json:"error,omitempty"`
+}
+
+type batchResponse[T any] struct {
+	Atomic  bool             `json:"atomic"`
+	Total   int              `json:"total"   example:"30"`
+	Applied int              `json:"applied" example:"29"`
+	Failed  int              `json:"failed"  example:"1"`
+	Results []batchResult[T] `json:"results"`
+}
+
+// newBatchResponse describes the outcome of a batch, failed operations carry the problem details of their error.
+func newBatchResponse[T any](ctx *fiber.Ctx, report entity.BatchReport[T]) batchResponse[T] {
+	results := make([]batchResult[T], len(report.Results))
+	for i, res := range report.Results {
+		results[i] = batchResult[T]{Index: i, Op: res.Op, Status: res.Status, Item: res.Item}
+
+		if res.Err != nil {
+			p := problemOf(ctx, res.Err)
+			results[i].Error = &p
+		}
+	}
+
+	return batchResponse[T]{
+		Atomic:  report.Atomic,
+		Total:   report.Total,
+		Applied: report.Applied,
+		Failed:  report.Failed,
+		Results: results,
+	}
+}
@@ -1,13 +1,86 @@
 package v1
 
 import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/pkg/logger"
 	"github.com/gofiber/fiber/v2"
 )
 
-type response struct {
-	Error string `json:"error" example:"message"`
+// _problemContentType is the media type of RFC 7807 problem details.
+const _problemContentType = "application/problem+json"
+
+// problem is an RFC 7807 problem details object extended with a machine-readable
+// error code and the list of rejected fields.
+type problem struct {
+	Type          string              `json:"type"                     example:"about:blank"`
+	Title         string              `json:"title"                    example:"Bad Request"`
+	Status        int                 `json:"status"                   example:"400"`
+	Detail        string              `json:"detail,omitempty"         example:"validation failed"`
+	Instance      string              `json:"instance,omitempty"       example:"/students"`
+	Code          string              `json:"code"                     example:"validation_failed"`
+	InvalidParams []entity.FieldError `json:"invalid_params,omitempty"`
+}
+
+func newProblem(ctx *fiber.Ctx, status int, code, detail string, fields []entity.FieldError) problem {
+	return problem{
+		Type:          "about:blank",
+		Title:         http.StatusText(status),
+		Status:        status,
+		Detail:        detail,
+		Instance:      ctx.Path(),
+		Code:          code,
+		InvalidParams: fields,
+	}
+}
+
+func writeProblem(ctx *fiber.Ctx, p problem) error {
+	return ctx.Status(p.Status).JSON(p, _problemContentType)
 }
 
 func errorResponse(ctx *fiber.Ctx, code int, msg string) error {
-	return ctx.Status(code).JSON(response{msg})
+	return writeProblem(ctx, newProblem(ctx, code, strings.ReplaceAll(strings.ToLower(http.StatusText(code)), " ", "_"), msg, nil))
+}
+
+// statusOf returns the HTTP status reported for a domain error kind.
+func statusOf(err *entity.Error) int {
+	switch {
+	case errors.Is(err, errIfMatchRequired):
+		return http.StatusPreconditionRequired
+	case errors.Is(err.Kind, entity.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err.Kind, entity.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err.Kind, entity.ErrValidation):
+		return http.StatusBadRequest
+	case errors.Is(err.Kind, entity.ErrForeignKey):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err.Kind, entity.ErrPrecondition):
+		return http.StatusPreconditionFailed
+	case errors.Is(err.Kind, entity.ErrForbidden):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// problemOf describes err as problem details. Domain errors keep their code and field details,
+// anything else is reported as an internal error.
+func problemOf(ctx *fiber.Ctx, err error) problem {
+	var domainErr *entity.Error
+	if !errors.As(err, &domainErr) {
+		return newProblem(ctx, http.StatusInternalServerError, "internal_server_error", "internal server error", nil)
+	}
+
+	return newProblem(ctx, statusOf(domainErr), domainErr.Code, domainErr.Message, domainErr.Fields)
+}
+
+// handleError logs err under op and writes the problem details for it.
+func handleError(ctx *fiber.Ctx, l logger.Interface, err error, op string) error {
+	l.Error(err, op)
+
+	return writeProblem(ctx, problemOf(ctx, err))
 }
@@ -0,0 +1,103 @@
+package v1_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorStatus(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+		fields []entity.FieldError
+	}{
+		{
+			name:   "not found",
+			err:    entity.NewError(entity.ErrNotFound, "student_not_found", "student not found"),
+			status: http.StatusNotFound,
+			code:   "student_not_found",
+		},
+		{
+			name: "conflict",
+			err: entity.NewError(entity.ErrConflict, "student_already_exists", "student already exists",
+				entity.FieldError{Field: "email", Message: "is already taken"}),
+			status: http.StatusConflict,
+			code:   "student_already_exists",
+			fields: []entity.FieldError{{Field: "email", Message: "is already taken"}},
+		},
+		{
+			name:   "validation",
+			err:    entity.ErrInvalidCursor,
+			status: http.StatusBadRequest,
+			code:   entity.ErrInvalidCursor.Code,
+			fields: entity.ErrInvalidCursor.Fields,
+		},
+		{
+			name:   "foreign key",
+			err:    entity.NewError(entity.ErrForeignKey, "foreign_key_violation", "referenced entity does not exist"),
+			status: http.StatusUnprocessableEntity,
+			code:   "foreign_key_violation",
+		},
+		{
+			name:   "precondition",
+			err:    entity.ErrVersionMismatch,
+			status: http.StatusPreconditionFailed,
+			code:   entity.ErrVersionMismatch.Code,
+		},
+		{
+			name:   "forbidden",
+			err:    entity.ErrAccessDenied,
+			status: http.StatusForbidden,
+			code:   entity.ErrAccessDenied.Code,
+		},
+		{
+			name:   "wrapped domain error",
+			err:    fmt.Errorf("StudentUseCase - GetStudentByID - uc.repo.GetStudentByID: %w", entity.ErrGroupNotFound),
+			status: http.StatusUnprocessableEntity,
+			code:   entity.ErrGroupNotFound.Code,
+			fields: entity.ErrGroupNotFound.Fields,
+		},
+		{
+			name:   "unknown kind",
+			err:    entity.NewError(errors.New("unknown"), "unknown", "unknown"),
+			status: http.StatusInternalServerError,
+			code:   "unknown",
+		},
+		{
+			name:   "other error",
+			err:    errors.New("connection reset"),
+			status: http.StatusInternalServerError,
+			code:   "internal_server_error",
+		},
+	}
+
+	for _, tc := range tests {
+		localTc := tc
+
+		t.Run(localTc.name, func(t *testing.T) {
+			t.Parallel()
+
+			app := studentApp(studentStub{
+				get: func(int) (entity.Student, error) { return entity.Student{}, localTc.err },
+			})
+
+			resp, p := send(t, app, httptest.NewRequest(http.MethodGet, "/students/1", http.NoBody))
+
+			require.Equal(t, localTc.status, resp.StatusCode)
+			require.Equal(t, localTc.status, p.Status)
+			require.Equal(t, localTc.code, p.Code)
+			require.Equal(t, localTc.fields, p.InvalidParams)
+			require.Equal(t, "/students/1", p.Instance)
+		})
+	}
+}
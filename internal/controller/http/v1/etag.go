@@ -0,0 +1,43 @@
+package v1
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/gofiber/fiber/v2"
+)
+
+// errIfMatchRequired is returned when a write request does not say which version it modifies.
+var errIfMatchRequired = entity.NewError(entity.ErrPrecondition, "if_match_required",
+	"If-Match header with the ETag of the resource is required")
+
+// setETag exposes the row version as the entity tag of the response.
+func setETag(ctx *fiber.Ctx, version int) {
+	ctx.Set(fiber.HeaderETag, strconv.Quote(strconv.Itoa(version)))
+}
+
+// ifMatch returns the version required by the If-Match header. "*" matches any version
+// and yields 0, a tag that cannot belong to any version never matches.
+func ifMatch(ctx *fiber.Ctx) (int, error) {
+	header := strings.TrimSpace(ctx.Get(fiber.HeaderIfMatch))
+
+	switch header {
+	case "":
+		return 0, errIfMatchRequired
+	case "*":
+		return 0, nil
+	}
+
+	tag, err := strconv.Unquote(strings.TrimPrefix(header, "W/"))
+	if err != nil {
+		return 0, entity.ErrVersionMismatch.Wrap(err)
+	}
+
+	version, err := strconv.Atoi(tag)
+	if err != nil || version <= 0 {
+		return 0, entity.ErrVersionMismatch.Wrap(err)
+	}
+
+	return version, nil
+}
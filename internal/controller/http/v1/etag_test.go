@@ -0,0 +1,137 @@
+package v1_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// _version is the current version of the students served by versionedStudents.
+const _version = 2
+
+// versionedStudents serves students at _version, deleting them only at that version or at any with 0.
+func versionedStudents() studentStub {
+	return studentStub{
+		get: func(id int) (entity.Student, error) {
+			return entity.Student{ID: id, Version: _version}, nil
+		},
+		delete: func(_, version int) error {
+			if version != 0 && version != _version {
+				return entity.ErrVersionMismatch
+			}
+
+			return nil
+		},
+	}
+}
+
+func TestStudentETag(t *testing.T) {
+	t.Parallel()
+
+	resp, _ := send(t, studentApp(versionedStudents()), httptest.NewRequest(http.MethodGet, "/students/1", http.NoBody))
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, `"2"`, resp.Header.Get(fiber.HeaderETag))
+}
+
+func TestUpdateStudentETag(t *testing.T) {
+	t.Parallel()
+
+	// The stub has no get, the ETag must come from the stored student returned by the update
+	app := studentApp(studentStub{
+		update: func(s entity.Student) (entity.Student, error) {
+			if s.Version != _version {
+				return entity.Student{}, entity.ErrVersionMismatch
+			}
+
+			s.Version++
+
+			return s, nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodPut, "/students/1",
+		strings.NewReader(`{"name":"Ann","email":"ann@example.com","group_id":1}`))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	req.Header.Set(fiber.HeaderIfMatch, `"2"`)
+
+	resp, _ := send(t, app, req)
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, `"3"`, resp.Header.Get(fiber.HeaderETag))
+}
+
+func TestDeleteStudentIfMatch(t *testing.T) {
+	t.Parallel()
+
+	app := studentApp(versionedStudents())
+
+	tests := []struct {
+		name    string
+		ifMatch string
+		status  int
+		code    string
+	}{
+		{
+			name:   "missing",
+			status: http.StatusPreconditionRequired,
+			code:   "if_match_required",
+		},
+		{
+			name:    "current version",
+			ifMatch: `"2"`,
+			status:  http.StatusNoContent,
+		},
+		{
+			name:    "weak tag of the current version",
+			ifMatch: `W/"2"`,
+			status:  http.StatusNoContent,
+		},
+		{
+			name:    "any version",
+			ifMatch: "*",
+			status:  http.StatusNoContent,
+		},
+		{
+			name:    "stale version",
+			ifMatch: `"1"`,
+			status:  http.StatusPreconditionFailed,
+			code:    entity.ErrVersionMismatch.Code,
+		},
+		{
+			name:    "unquoted tag",
+			ifMatch: "2",
+			status:  http.StatusPreconditionFailed,
+			code:    entity.ErrVersionMismatch.Code,
+		},
+		{
+			name:    "tag of no version",
+			ifMatch: `"abc"`,
+			status:  http.StatusPreconditionFailed,
+			code:    entity.ErrVersionMismatch.Code,
+		},
+	}
+
+	for _, tc := range tests {
+		localTc := tc
+
+		t.Run(localTc.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodDelete, "/students/1", http.NoBody)
+			if localTc.ifMatch != "" {
+				req.Header.Set(fiber.HeaderIfMatch, localTc.ifMatch)
+			}
+
+			resp, p := send(t, app, req)
+
+			require.Equal(t, localTc.status, resp.StatusCode)
+			require.Equal(t, localTc.code, p.Code)
+		})
+	}
+}
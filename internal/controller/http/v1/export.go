@@ -0,0 +1,202 @@
+package v1
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/xuri/excelize/v2"
+)
+
+// Export formats and their media types.
+const (
+	_formatJSONL = "jsonl"
+
+	_mimeCSV   = "text/csv"
+	_mimeXLSX  = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	_mimeJSONL = "application/x-ndjson"
+)
+
+// _exportFormats maps the media types an export can be negotiated for to the export formats.
+var _exportFormats = map[string]string{ //nolint:gochecknoglobals // lookup table
+	_mimeCSV:   _formatCSV,
+	_mimeXLSX:  _formatXLSX,
+	_mimeJSONL: _formatJSONL,
+}
+
+// _exportMimes maps the export formats to their media types.
+var _exportMimes = map[string]string{ //nolint:gochecknoglobals // lookup table
+	_formatCSV:   _mimeCSV,
+	_formatXLSX:  _mimeXLSX,
+	_formatJSONL: _mimeJSONL,
+}
+
+type exportQuery struct {
+	Format string `query:"format" validate:"omitempty,oneof=csv xlsx jsonl"`
+}
+
+// exportFormat returns the requested export format, taken from the Accept header unless
+// given explicitly. It is empty when none of the accepted media types can be produced.
+func exportFormat(ctx *fiber.Ctx, format string) string {
+	if format != "" {
+		return format
+	}
+
+	return _exportFormats[ctx.Accepts(_mimeCSV, _mimeXLSX, _mimeJSONL)]
+}
+
+// exportWriter writes the items of an export one at a time. Tabular formats write
+// the cells of an item, JSON Lines writes the item itself.
+type exportWriter interface {
+	Write(item any, cells []string) error
+	Close() error
+}
+
+// newExportWriter returns a writer for the format that starts tabular exports with the header.
+func newExportWriter(w io.Writer, format string, header []string) (exportWriter, error) {
+	switch format {
+	case _formatXLSX:
+		return newXLSXWriter(w, header)
+	case _formatJSONL:
+		return &jsonlWriter{enc: json.NewEncoder(w)}, nil
+	default:
+		c := &csvWriter{w: csv.NewWriter(w)}
+
+		return c, c.w.Write(header)
+	}
+}
+
+// streamExport sets the headers of a file download and streams the export produced by export
+// once the handler returns. The export opens the writer through open, with a header that may depend
+// on the exported items. Errors after the first byte cannot change the status anymore,
+// they are passed to onError and cut the file short, so the caller must be authorized beforehand.
+func streamExport(ctx *fiber.Ctx, format, name string,
+	export func(open func(header []string) (exportWriter, error)) error, onError func(error),
+) error {
+	ctx.Set(fiber.HeaderContentType, _exportMimes[format])
+	ctx.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s.%s"`, name, format))
+
+	ctx.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		var out exportWriter
+
+		err := export(func(header []string) (exportWriter, error) {
+			var err error
+			out, err = newExportWriter(w, format, header)
+
+			return out, err
+		})
+
+		if err == nil && out != nil {
+			err = out.Close()
+		}
+
+		if err == nil {
+			err = w.Flush()
+		}
+
+		if err != nil {
+			onError(err)
+		}
+	})
+
+	return nil
+}
+
+// csvWriter writes an export as comma separated values.
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvWriter) Write(_ any, cells []string) error {
+	return c.w.Write(cells)
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+
+	return c.w.Error()
+}
+
+// jsonlWriter writes an export as JSON Lines, one item per line.
+type jsonlWriter struct {
+	enc *json.Encoder
+}
+
+func (j *jsonlWriter) Write(item any, _ []string) error {
+	return j.enc.Encode(item)
+}
+
+func (j *jsonlWriter) Close() error {
+	return nil
+}
+
+// xlsxWriter writes an export as the first sheet of a workbook. The workbook is a zip archive,
+// so rows are buffered by excelize, spilling to a temporary file, until it is written on Close.
+type xlsxWriter struct {
+	w     io.Writer
+	book  *excelize.File
+	sheet *excelize.StreamWriter
+	row   int
+}
+
+func newXLSXWriter(w io.Writer, header []string) (*xlsxWriter, error) {
+	book := excelize.NewFile()
+
+	sheet, err := book.NewStreamWriter(book.GetSheetName(0))
+	if err != nil {
+		return nil, fmt.Errorf("book.NewStreamWriter: %w", err)
+	}
+
+	x := &xlsxWriter{w: w, book: book, sheet: sheet}
+
+	return x, x.Write(nil, header)
+}
+
+func (x *xlsxWriter) Write(_ any, cells []string) error {
+	x.row++
+
+	values := make([]any, len(cells))
+	for i, c := range cells {
+		values[i] = c
+	}
+
+	cell, err := excelize.CoordinatesToCellName(1, x.row)
+	if err != nil {
+		return fmt.Errorf("excelize.CoordinatesToCellName: %w", err)
+	}
+
+	return x.sheet.SetRow(cell, values)
+}
+
+func (x *xlsxWriter) Close() error {
+	defer x.book.Close()
+
+	if err := x.sheet.Flush(); err != nil {
+		return fmt.Errorf("x.sheet.Flush: %w", err)
+	}
+
+	return x.book.Write(x.w)
+}
+
+// formatTime formats an optional timestamp for tabular exports.
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+
+	return t.Format(time.RFC3339)
+}
+
+// formatID formats an optional ID for tabular exports.
+func formatID(id *int) string {
+	if id == nil {
+		return ""
+	}
+
+	return strconv.Itoa(*id)
+}
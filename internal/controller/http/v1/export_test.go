@@ -0,0 +1,264 @@
+package v1_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "github.com/evrone/go-clean-template/internal/controller/http/v1"
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/usecase"
+	"github.com/evrone/go-clean-template/pkg/logger"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+// groupStub implements the group use case calls made by a test, any other call panics.
+type groupStub struct {
+	usecase.Group
+	export entity.Export[entity.GroupPath]
+	denied error
+}
+
+func (g groupStub) ExportGroups(context.Context, entity.GroupFilter) (entity.Export[entity.GroupPath], error) {
+	if g.denied != nil {
+		return nil, g.denied
+	}
+
+	return g.export, nil
+}
+
+func groupApp(g usecase.Group) *fiber.App {
+	app := fiber.New()
+	v1.NewGroupRoutes(app, g, logger.New("error"))
+
+	return app
+}
+
+// exportGroups yields the groups in order.
+func exportGroups(groups ...entity.GroupPath) entity.Export[entity.GroupPath] {
+	return func(yield func(entity.GroupPath) error) error {
+		for _, g := range groups {
+			if err := yield(g); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+// download runs the export request and returns the response with its whole body.
+func download(t *testing.T, app *fiber.App, req *http.Request) (*http.Response, []byte) {
+	t.Helper()
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	return resp, body
+}
+
+func TestExportGroups(t *testing.T) {
+	t.Parallel()
+
+	parent := 1
+	school := entity.GroupPath{Group: entity.Group{ID: 1, Name: "School"}, Path: []string{"School"}, Levels: 2}
+	class := entity.GroupPath{Group: entity.Group{ID: 2, Name: "Class A", ParentID: &parent}, Path: []string{"School", "Class A"}, Levels: 2}
+	tree := exportGroups(school, class)
+
+	tests := []struct {
+		name        string
+		export      entity.Export[entity.GroupPath]
+		denied      error
+		query       string
+		accept      string
+		status      int
+		contentType string
+		disposition string
+		body        string
+	}{
+		{
+			name:        "csv",
+			export:      tree,
+			query:       "?format=csv",
+			status:      http.StatusOK,
+			contentType: "text/csv",
+			disposition: `attachment; filename="groups.csv"`,
+			body: "id,parent_id,name,depth,path,level_1,level_2\n" +
+				"1,,School,0,School,School\n" +
+				"2,1,Class A,1,School / Class A,School,Class A\n",
+		},
+		{
+			name:        "csv negotiated",
+			export:      tree,
+			accept:      "text/csv",
+			status:      http.StatusOK,
+			contentType: "text/csv",
+			disposition: `attachment; filename="groups.csv"`,
+			body: "id,parent_id,name,depth,path,level_1,level_2\n" +
+				"1,,School,0,School,School\n" +
+				"2,1,Class A,1,School / Class A,School,Class A\n",
+		},
+		{
+			name:        "no groups",
+			export:      exportGroups(),
+			query:       "?format=csv",
+			status:      http.StatusOK,
+			contentType: "text/csv",
+			disposition: `attachment; filename="groups.csv"`,
+			body:        "id,parent_id,name,depth,path\n",
+		},
+		{
+			name: "failure after the first group",
+			export: func(yield func(entity.GroupPath) error) error {
+				if err := yield(school); err != nil {
+					return err
+				}
+
+				return errors.New("connection reset")
+			},
+			query:       "?format=csv",
+			status:      http.StatusOK,
+			contentType: "text/csv",
+			disposition: `attachment; filename="groups.csv"`,
+			body: "id,parent_id,name,depth,path,level_1,level_2\n" +
+				"1,,School,0,School,School\n",
+		},
+		{
+			name:   "denied",
+			denied: entity.ErrAccessDenied,
+			query:  "?format=csv",
+			status: http.StatusForbidden,
+		},
+		{
+			name:   "unsupported media type",
+			export: tree,
+			accept: "application/pdf",
+			status: http.StatusNotAcceptable,
+		},
+		{
+			name:   "unknown format",
+			export: tree,
+			query:  "?format=pdf",
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		localTc := tc
+
+		t.Run(localTc.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/groups/export"+localTc.query, http.NoBody)
+			if localTc.accept != "" {
+				req.Header.Set(fiber.HeaderAccept, localTc.accept)
+			}
+
+			resp, body := download(t, groupApp(groupStub{export: localTc.export, denied: localTc.denied}), req)
+
+			require.Equal(t, localTc.status, resp.StatusCode)
+
+			if localTc.status != http.StatusOK {
+				require.Empty(t, resp.Header.Get(fiber.HeaderContentDisposition))
+
+				return
+			}
+
+			require.Equal(t, localTc.contentType, resp.Header.Get(fiber.HeaderContentType))
+			require.Equal(t, localTc.disposition, resp.Header.Get(fiber.HeaderContentDisposition))
+			require.Equal(t, localTc.body, string(body))
+		})
+	}
+}
+
+func TestExportGroupsJSONL(t *testing.T) {
+	t.Parallel()
+
+	app := groupApp(groupStub{export: exportGroups(
+		entity.GroupPath{Group: entity.Group{ID: 1, Name: "School"}, Path: []string{"School"}, Levels: 1},
+	)})
+
+	resp, body := download(t, app, httptest.NewRequest(http.MethodGet, "/groups/export?format=jsonl", http.NoBody))
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "application/x-ndjson", resp.Header.Get(fiber.HeaderContentType))
+
+	lines := strings.Split(strings.TrimSuffix(string(body), "\n"), "\n")
+	require.Len(t, lines, 1)
+
+	var g struct {
+		ID   int      `json:"id"`
+		Name string   `json:"name"`
+		Path []string `json:"path"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &g))
+	require.Equal(t, 1, g.ID)
+	require.Equal(t, "School", g.Name)
+	require.Equal(t, []string{"School"}, g.Path)
+}
+
+func TestExportStudentsXLSX(t *testing.T) {
+	t.Parallel()
+
+	created := time.Date(2025, 9, 1, 8, 0, 0, 0, time.UTC)
+
+	app := studentApp(studentStub{
+		export: func(yield func(entity.Student) error) error {
+			return yield(entity.Student{
+				ID:        7,
+				Name:      "Ann",
+				Email:     "ann@example.com",
+				GroupID:   1,
+				Status:    "active",
+				CreatedAt: created,
+				UpdatedAt: created,
+			})
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/students/export", http.NoBody)
+	req.Header.Set(fiber.HeaderAccept, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+
+	resp, body := download(t, app, req)
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, `attachment; filename="students.xlsx"`, resp.Header.Get(fiber.HeaderContentDisposition))
+
+	book, err := excelize.OpenReader(bytes.NewReader(body))
+	require.NoError(t, err)
+
+	defer book.Close()
+
+	rows, err := book.GetRows(book.GetSheetName(0))
+	require.NoError(t, err)
+	require.Equal(t, [][]string{
+		{"id", "name", "email", "phone", "group_id", "enrollment_number", "status", "created_at", "updated_at", "deleted_at"},
+		{"7", "Ann", "ann@example.com", "", "1", "", "active", "2025-09-01T08:00:00Z", "2025-09-01T08:00:00Z"},
+	}, rows)
+}
+
+func TestExportStudentsDenied(t *testing.T) {
+	t.Parallel()
+
+	app := studentApp(studentStub{denied: entity.ErrAccessDenied})
+
+	resp, p := send(t, app, httptest.NewRequest(http.MethodGet, "/students/export?format=csv&include_deleted=true", http.NoBody))
+
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+	require.Equal(t, entity.ErrAccessDenied.Code, p.Code)
+	require.Empty(t, resp.Header.Get(fiber.HeaderContentDisposition))
+}
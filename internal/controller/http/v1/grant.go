@@ -0,0 +1,135 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/usecase"
+	"github.com/evrone/go-clean-template/pkg/logger"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type grantRoutes struct {
+	a usecase.Access
+	l logger.Interface
+	v *validator.Validate
+}
+
+func NewGrantRoutes(router fiber.Router, a usecase.Access, l logger.Interface) {
+	r := &grantRoutes{a, l, newValidator()}
+
+	router.Get("/grants", r.getGrants)
+	router.Post("/grants", r.createGrant)
+	router.Delete("/grants/:id", r.deleteGrant)
+}
+
+type grantsQuery struct {
+	Subject string `query:"subject"`
+}
+
+type grantListResponse struct {
+	Items []entity.Grant `json:"items"`
+}
+
+// @Summary     Get grants
+// @Description Retrieve the grants on the groups the caller administers
+// @ID          get-grants
+// @Tags  	    grants
+// @Accept      json
+// @Produce     json
+// @Param       subject query string false "Only the grants of this subject"
+// @Success     200 {object} grantListResponse
+// @Failure     400 {object} problem
+// @Failure     403 {object} problem
+// @Failure     500 {object} problem
+// @Router      /grants [get]
+func (r *grantRoutes) getGrants(ctx *fiber.Ctx) error {
+	var request grantsQuery
+	if err := ctx.QueryParser(&request); err != nil {
+		r.l.Error(err, "http - v1 - getGrants")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid query parameters")
+	}
+
+	grants, err := r.a.GetGrants(ctx.UserContext(), request.Subject)
+	if err != nil {
+		return handleError(ctx, r.l, err, "http - v1 - getGrants - r.a.GetGrants")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(grantListResponse{Items: grants})
+}
+
+type createGrantRequest struct {
+	Subject string `json:"subject"  validate:"required,max=255" example:"secretary-physics"`
+	GroupID *int   `json:"group_id" validate:"omitempty,min=1"  example:"3"`
+	Role    string `json:"role"     validate:"required,oneof=read write admin" example:"write"`
+}
+
+// @Summary     Create a grant
+// @Description Give a subject a role on a group and all its descendants, or on every group without group_id.
+// @Description Needs the admin role on the group.
+// @ID          create-grant
+// @Tags  	    grants
+// @Accept      json
+// @Produce     json
+// @Param       request body createGrantRequest true "Grant data"
+// @Success     201 {object} entity.Grant
+// @Failure     400 {object} problem
+// @Failure     403 {object} problem
+// @Failure     409 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /grants [post]
+func (r *grantRoutes) createGrant(ctx *fiber.Ctx) error {
+	var request createGrantRequest
+
+	if err := ctx.BodyParser(&request); err != nil {
+		r.l.Error(err, "http - v1 - createGrant")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid request body")
+	}
+
+	if err := r.v.Struct(request); err != nil {
+		return handleError(ctx, r.l, validationError(err), "http - v1 - createGrant - validation")
+	}
+
+	grant := entity.Grant{
+		Subject: request.Subject,
+		GroupID: request.GroupID,
+		Role:    request.Role,
+	}
+
+	created, err := r.a.CreateGrant(ctx.UserContext(), grant)
+	if err != nil {
+		return handleError(ctx, r.l, err, "http - v1 - createGrant - r.a.CreateGrant")
+	}
+
+	return ctx.Status(http.StatusCreated).JSON(created)
+}
+
+// @Summary     Delete grant
+// @Description Take a role away, needs the admin role on the group of the grant
+// @ID          delete-grant
+// @Tags  	    grants
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Grant ID"
+// @Success     204 "No Content"
+// @Failure     400 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     500 {object} problem
+// @Router      /grants/{id} [delete]
+func (r *grantRoutes) deleteGrant(ctx *fiber.Ctx) error {
+	id, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - deleteGrant")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid id parameter")
+	}
+
+	if err = r.a.DeleteGrant(ctx.UserContext(), id); err != nil {
+		return handleError(ctx, r.l, err, "http - v1 - deleteGrant - r.a.DeleteGrant")
+	}
+
+	return ctx.SendStatus(http.StatusNoContent)
+}
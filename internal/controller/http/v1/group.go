@@ -2,7 +2,9 @@ package v1
 
 import (
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/evrone/go-clean-template/internal/entity"
 	"github.com/evrone/go-clean-template/internal/usecase"
@@ -18,14 +20,23 @@ type groupRoutes struct {
 }
 
 func NewGroupRoutes(router fiber.Router, g usecase.Group, l logger.Interface) {
-	r := &groupRoutes{g, l, validator.New(validator.WithRequiredStructEnabled())}
+	r := &groupRoutes{g, l, newValidator()}
 
 	// Register routes
 	router.Post("/groups", r.createGroup)
 	router.Get("/groups", r.getGroups)
+	router.Get("/groups/export", r.exportGroups)
+	router.Get("/groups/stats", r.getGroupStats)
 	router.Get("/groups/:id", r.getGroupByID)
+	router.Get("/groups/:id/ancestors", r.getGroupAncestors)
+	router.Get("/groups/:id/stats", r.getGroupStatsByID)
 	router.Put("/groups/:id", r.updateGroup)
+	router.Patch("/groups/:id", r.patchGroup)
 	router.Delete("/groups/:id", r.deleteGroup)
+	router.Post("/groups/:id/restore", r.restoreGroup)
+	router.Post("/groups/:id/move", r.moveGroup)
+	router.Post("/groups/:id/merge-into/:target", r.mergeGroup)
+	router.Post("/groups/batch", r.batchGroups)
 }
 
 type createGroupRequest struct {
@@ -41,8 +52,11 @@ type createGroupRequest struct {
 // @Produce     json
 // @Param       request body createGroupRequest true "Group data"
 // @Success     201 {object} entity.Group
-// @Failure     400 {object} response
-// @Failure     500 {object} response
+// @Header      201 {string} ETag "Version of the created group"
+// @Failure     400 {object} problem
+// @Failure     403 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
 // @Router      /groups [post]
 func (r *groupRoutes) createGroup(ctx *fiber.Ctx) error {
 	var request createGroupRequest
@@ -53,8 +67,7 @@ func (r *groupRoutes) createGroup(ctx *fiber.Ctx) error {
 	}
 
 	if err := r.v.Struct(request); err != nil {
-		r.l.Error(err, "http - v1 - createGroup - validation")
-		return errorResponse(ctx, http.StatusBadRequest, "validation failed")
+		return handleError(ctx, r.l, validationError(err), "http - v1 - createGroup - validation")
 	}
 
 	group := entity.Group{
@@ -64,61 +77,129 @@ func (r *groupRoutes) createGroup(ctx *fiber.Ctx) error {
 
 	createdGroup, err := r.g.CreateGroup(ctx.UserContext(), group)
 	if err != nil {
-		r.l.Error(err, "http - v1 - createGroup - r.g.CreateGroup")
-		return errorResponse(ctx, http.StatusInternalServerError, "failed to create group")
+		return handleError(ctx, r.l, err, "http - v1 - createGroup - r.g.CreateGroup")
 	}
 
+	setETag(ctx, createdGroup.Version)
+
 	return ctx.Status(http.StatusCreated).JSON(createdGroup)
 }
 
+type listGroupsQuery struct {
+	Limit          int    `query:"limit"           validate:"omitempty,min=1,max=500"`
+	Cursor         string `query:"cursor"`
+	Sort           string `query:"sort"            validate:"omitempty,oneof=id -id name -name"`
+	ParentID       int    `query:"parent_id"       validate:"omitempty,min=1"`
+	MaxDepth       *int   `query:"max_depth"       validate:"omitempty,min=0"`
+	IncludeDeleted bool   `query:"include_deleted"`
+}
+
+type groupListResponse struct {
+	Items      []entity.Group `json:"items"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+	Total      int            `json:"total"`
+}
+
 // @Summary     Get all groups
-// @Description Retrieve a list of all academic groups with their subgroups
+// @Description Retrieve a page of top-level academic groups with their subgroups.
+// @Description With query, an array of entity.GroupHit ranked by relevance is returned instead,
+// @Description matching group names in Cyrillic and Latin spelling.
 // @ID          get-groups
 // @Tags  	    groups
 // @Accept      json
 // @Produce     json
-// @Param       query query string false "Search query"
-// @Success     200 {array} entity.Group
-// @Failure     500 {object} response
+// @Param       query           query string false "Search query"
+// @Param       mode            query string false "Search mode (default prefix)" Enums(exact, prefix, fuzzy)
+// @Param       limit           query int    false "Page size (1-500, default 50)"
+// @Param       cursor          query string false "Cursor returned as next_cursor by the previous page"
+// @Param       sort            query string false "Sort field, prefix with - for descending" Enums(id, -id, name, -name)
+// @Param       parent_id       query int    false "List the children of this group instead of the root groups"
+// @Param       max_depth       query int    false "Levels of subgroups to load under each group (default: all)"
+// @Param       include_deleted query bool   false "Also list soft-deleted groups, admins of every group only"
+// @Success     200 {object} groupListResponse
+// @Failure     400 {object} problem
+// @Failure     403 {object} problem
+// @Failure     500 {object} problem
 // @Router      /groups [get]
 func (r *groupRoutes) getGroups(ctx *fiber.Ctx) error {
 	// Check if there's a search query
-	query := ctx.Query("query")
-	if query != "" {
-		return r.searchGroups(ctx, query)
+	if ctx.Query("query") != "" {
+		return r.searchGroups(ctx)
+	}
+
+	var request listGroupsQuery
+	if err := ctx.QueryParser(&request); err != nil {
+		r.l.Error(err, "http - v1 - getGroups")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid query parameters")
 	}
 
-	groups, err := r.g.GetGroups(ctx.UserContext())
+	if err := r.v.Struct(request); err != nil {
+		return handleError(ctx, r.l, validationError(err), "http - v1 - getGroups - validation")
+	}
+
+	page, err := newPageRequest(request.Limit, request.Sort, request.Cursor)
 	if err != nil {
-		r.l.Error(err, "http - v1 - getGroups - r.g.GetGroups")
-		return errorResponse(ctx, http.StatusInternalServerError, "failed to get groups")
+		return handleError(ctx, r.l, err, "http - v1 - getGroups - newPageRequest")
 	}
 
-	return ctx.Status(http.StatusOK).JSON(groups)
+	filter := entity.GroupFilter{
+		ParentID:       optionalID(request.ParentID),
+		MaxDepth:       depthLimit(request.MaxDepth),
+		IncludeDeleted: request.IncludeDeleted,
+	}
+
+	groups, err := r.g.GetGroups(ctx.UserContext(), filter, page)
+	if err != nil {
+		return handleError(ctx, r.l, err, "http - v1 - getGroups - r.g.GetGroups")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(groupListResponse{
+		Items:      groups.Items,
+		NextCursor: encodeCursor(groups.NextCursor),
+		Total:      groups.Total,
+	})
 }
 
 // Search groups based on query
-func (r *groupRoutes) searchGroups(ctx *fiber.Ctx, query string) error {
-	groups, err := r.g.SearchGroups(ctx.UserContext(), query)
+func (r *groupRoutes) searchGroups(ctx *fiber.Ctx) error {
+	var request searchQuery
+	if err := ctx.QueryParser(&request); err != nil {
+		r.l.Error(err, "http - v1 - searchGroups")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid query parameters")
+	}
+
+	if err := r.v.Struct(request); err != nil {
+		return handleError(ctx, r.l, validationError(err), "http - v1 - searchGroups - validation")
+	}
+
+	groups, err := r.g.SearchGroups(ctx.UserContext(), newSearchQuery(request))
 	if err != nil {
-		r.l.Error(err, "http - v1 - searchGroups - r.g.SearchGroups")
-		return errorResponse(ctx, http.StatusInternalServerError, "failed to search groups")
+		return handleError(ctx, r.l, err, "http - v1 - searchGroups - r.g.SearchGroups")
 	}
 
 	return ctx.Status(http.StatusOK).JSON(groups)
 }
 
+type getGroupQuery struct {
+	Include  []string `query:"include"   validate:"dive,oneof=subgroups path"`
+	MaxDepth *int     `query:"max_depth" validate:"omitempty,min=0"`
+}
+
 // @Summary     Get group by ID
-// @Description Retrieve a specific academic group by ID
+// @Description Retrieve a specific academic group by ID, optionally with its subgroups and its path from the root group
 // @ID          get-group-by-id
 // @Tags  	    groups
 // @Accept      json
 // @Produce     json
-// @Param       id path int true "Group ID"
+// @Param       id        path  int    true  "Group ID"
+// @Param       include   query []string false "subgroups to load the subtree, path to load the path from the root" Enums(subgroups, path) collectionFormat(csv)
+// @Param       max_depth query int    false "Levels of subgroups to load (default: all)"
 // @Success     200 {object} entity.Group
-// @Failure     400 {object} response
-// @Failure     404 {object} response
-// @Failure     500 {object} response
+// @Header      200 {string} ETag "Version of the group, send it back in If-Match"
+// @Failure     400 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     500 {object} problem
 // @Router      /groups/{id} [get]
 func (r *groupRoutes) getGroupByID(ctx *fiber.Ctx) error {
 	id, err := strconv.Atoi(ctx.Params("id"))
@@ -127,15 +208,121 @@ func (r *groupRoutes) getGroupByID(ctx *fiber.Ctx) error {
 		return errorResponse(ctx, http.StatusBadRequest, "invalid id parameter")
 	}
 
-	group, err := r.g.GetGroupByID(ctx.UserContext(), id)
+	var request getGroupQuery
+	if err := ctx.QueryParser(&request); err != nil {
+		r.l.Error(err, "http - v1 - getGroupByID")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid query parameters")
+	}
+
+	// Accept include=subgroups,path as well as repeated include parameters
+	if len(request.Include) > 0 {
+		request.Include = strings.Split(strings.Join(request.Include, ","), ",")
+	}
+
+	if err := r.v.Struct(request); err != nil {
+		return handleError(ctx, r.l, validationError(err), "http - v1 - getGroupByID - validation")
+	}
+
+	var group entity.Group
+	if slices.Contains(request.Include, "subgroups") {
+		group, err = r.g.GetGroupWithSubgroups(ctx.UserContext(), id, depthLimit(request.MaxDepth))
+	} else {
+		group, err = r.g.GetGroupByID(ctx.UserContext(), id)
+	}
+
 	if err != nil {
-		r.l.Error(err, "http - v1 - getGroupByID - r.g.GetGroupByID")
-		return errorResponse(ctx, http.StatusNotFound, "group not found")
+		return handleError(ctx, r.l, err, "http - v1 - getGroupByID - r.g.GetGroupByID")
+	}
+
+	if slices.Contains(request.Include, "path") {
+		group.Path, err = r.g.GetGroupPath(ctx.UserContext(), id)
+		if err != nil {
+			return handleError(ctx, r.l, err, "http - v1 - getGroupByID - r.g.GetGroupPath")
+		}
 	}
 
+	setETag(ctx, group.Version)
+
 	return ctx.Status(http.StatusOK).JSON(group)
 }
 
+// @Summary     Get group ancestors
+// @Description Retrieve the ancestors of a group from the root group down to its parent, e.g. for breadcrumbs
+// @ID          get-group-ancestors
+// @Tags  	    groups
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Group ID"
+// @Success     200 {array} entity.GroupRef
+// @Failure     400 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     500 {object} problem
+// @Router      /groups/{id}/ancestors [get]
+func (r *groupRoutes) getGroupAncestors(ctx *fiber.Ctx) error {
+	id, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - getGroupAncestors")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid id parameter")
+	}
+
+	path, err := r.g.GetGroupPath(ctx.UserContext(), id)
+	if err != nil {
+		return handleError(ctx, r.l, err, "http - v1 - getGroupAncestors - r.g.GetGroupPath")
+	}
+
+	// The path ends with the group itself
+	return ctx.Status(http.StatusOK).JSON(path[:len(path)-1])
+}
+
+// @Summary     Get group statistics
+// @Description Retrieve student and subgroup counts, depth and leaf and empty flags of every group, parents before
+// @Description their subgroups, together with totals over the whole hierarchy
+// @ID          get-group-stats
+// @Tags  	    groups
+// @Accept      json
+// @Produce     json
+// @Success     200 {object} entity.GroupStatsSummary
+// @Failure     403 {object} problem
+// @Failure     500 {object} problem
+// @Router      /groups/stats [get]
+func (r *groupRoutes) getGroupStats(ctx *fiber.Ctx) error {
+	stats, err := r.g.GetGroupStats(ctx.UserContext())
+	if err != nil {
+		return handleError(ctx, r.l, err, "http - v1 - getGroupStats - r.g.GetGroupStats")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(stats)
+}
+
+// @Summary     Get statistics of a group
+// @Description Retrieve student and subgroup counts, depth and leaf and empty flags of a group
+// @ID          get-group-stats-by-id
+// @Tags  	    groups
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Group ID"
+// @Success     200 {object} entity.GroupStats
+// @Failure     400 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     500 {object} problem
+// @Router      /groups/{id}/stats [get]
+func (r *groupRoutes) getGroupStatsByID(ctx *fiber.Ctx) error {
+	id, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - getGroupStatsByID")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid id parameter")
+	}
+
+	stats, err := r.g.GetGroupStatsByID(ctx.UserContext(), id)
+	if err != nil {
+		return handleError(ctx, r.l, err, "http - v1 - getGroupStatsByID - r.g.GetGroupStatsByID")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(stats)
+}
+
 type updateGroupRequest struct {
 	Name     string `json:"name" validate:"required"`
 	ParentID *int   `json:"parent_id"`
@@ -148,11 +335,18 @@ type updateGroupRequest struct {
 // @Accept      json
 // @Produce     json
 // @Param       id path int true "Group ID"
+// @Param       If-Match header string true "ETag of the group being replaced, * to skip the check"
 // @Param       request body updateGroupRequest true "Updated group data"
 // @Success     200 {object} entity.Group
-// @Failure     400 {object} response
-// @Failure     404 {object} response
-// @Failure     500 {object} response
+// @Header      200 {string} ETag "New version of the group"
+// @Failure     400 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     409 {object} problem
+// @Failure     412 {object} problem
+// @Failure     422 {object} problem
+// @Failure     428 {object} problem
+// @Failure     500 {object} problem
 // @Router      /groups/{id} [put]
 func (r *groupRoutes) updateGroup(ctx *fiber.Ctx) error {
 	id, err := strconv.Atoi(ctx.Params("id"))
@@ -161,6 +355,11 @@ func (r *groupRoutes) updateGroup(ctx *fiber.Ctx) error {
 		return errorResponse(ctx, http.StatusBadRequest, "invalid id parameter")
 	}
 
+	version, err := ifMatch(ctx)
+	if err != nil {
+		return handleError(ctx, r.l, err, "http - v1 - updateGroup - ifMatch")
+	}
+
 	var request updateGroupRequest
 	if err := ctx.BodyParser(&request); err != nil {
 		r.l.Error(err, "http - v1 - updateGroup")
@@ -168,51 +367,125 @@ func (r *groupRoutes) updateGroup(ctx *fiber.Ctx) error {
 	}
 
 	if err := r.v.Struct(request); err != nil {
-		r.l.Error(err, "http - v1 - updateGroup - validation")
-		return errorResponse(ctx, http.StatusBadRequest, "validation failed")
-	}
-
-	// First check if group exists
-	_, err = r.g.GetGroupByID(ctx.UserContext(), id)
-	if err != nil {
-		r.l.Error(err, "http - v1 - updateGroup - r.g.GetGroupByID")
-		return errorResponse(ctx, http.StatusNotFound, "group not found")
+		return handleError(ctx, r.l, validationError(err), "http - v1 - updateGroup - validation")
 	}
 
 	group := entity.Group{
 		ID:       id,
 		Name:     request.Name,
 		ParentID: request.ParentID,
+		Version:  version,
 	}
 
-	err = r.g.UpdateGroup(ctx.UserContext(), group)
+	updatedGroup, err := r.g.UpdateGroup(ctx.UserContext(), group)
 	if err != nil {
-		r.l.Error(err, "http - v1 - updateGroup - r.g.UpdateGroup")
-		return errorResponse(ctx, http.StatusInternalServerError, "failed to update group")
+		return handleError(ctx, r.l, err, "http - v1 - updateGroup - r.g.UpdateGroup")
 	}
 
-	// Get the updated group to return in response
-	updatedGroup, err := r.g.GetGroupByID(ctx.UserContext(), id)
+	setETag(ctx, updatedGroup.Version)
+
+	return ctx.Status(http.StatusOK).JSON(updatedGroup)
+}
+
+type patchGroupRequest struct {
+	Name     *string `json:"name"      validate:"omitempty,min=1"`
+	ParentID *int    `json:"parent_id" validate:"omitempty,min=1"`
+}
+
+// @Summary     Patch group
+// @Description Change some of a group's fields using a JSON Merge Patch (RFC 7396), a null parent_id makes the group a root
+// @ID          patch-group
+// @Tags  	    groups
+// @Accept      application/merge-patch+json
+// @Produce     json
+// @Param       id path int true "Group ID"
+// @Param       If-Match header string true "ETag of the group being changed, * to skip the check"
+// @Param       request body patchGroupRequest true "Fields to change"
+// @Success     200 {object} entity.Group
+// @Header      200 {string} ETag "New version of the group"
+// @Failure     400 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     409 {object} problem
+// @Failure     412 {object} problem
+// @Failure     415 {object} problem
+// @Failure     422 {object} problem
+// @Failure     428 {object} problem
+// @Failure     500 {object} problem
+// @Router      /groups/{id} [patch]
+func (r *groupRoutes) patchGroup(ctx *fiber.Ctx) error {
+	id, err := strconv.Atoi(ctx.Params("id"))
 	if err != nil {
-		r.l.Error(err, "http - v1 - updateGroup - r.g.GetGroupByID")
-		return errorResponse(ctx, http.StatusInternalServerError, "group updated but failed to retrieve updated data")
+		r.l.Error(err, "http - v1 - patchGroup")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid id parameter")
 	}
 
-	return ctx.Status(http.StatusOK).JSON(updatedGroup)
+	version, err := ifMatch(ctx)
+	if err != nil {
+		return handleError(ctx, r.l, err, "http - v1 - patchGroup - ifMatch")
+	}
+
+	var request patchGroupRequest
+	patch, err := parseMergePatch(ctx, &request)
+	if err != nil {
+		r.l.Error(err, "http - v1 - patchGroup")
+		return patchBodyError(ctx, err)
+	}
+
+	if err := patch.nonNullable("name"); err != nil {
+		return handleError(ctx, r.l, err, "http - v1 - patchGroup - validation")
+	}
+
+	if err := r.v.Struct(request); err != nil {
+		return handleError(ctx, r.l, validationError(err), "http - v1 - patchGroup - validation")
+	}
+
+	group, err := r.g.PatchGroup(ctx.UserContext(), id, entity.GroupPatch{
+		Name:      request.Name,
+		ParentID:  request.ParentID,
+		SetParent: patch.has("parent_id"),
+		Version:   version,
+	})
+	if err != nil {
+		return handleError(ctx, r.l, err, "http - v1 - patchGroup - r.g.PatchGroup")
+	}
+
+	setETag(ctx, group.Version)
+
+	return ctx.Status(http.StatusOK).JSON(group)
+}
+
+type deleteGroupQuery struct {
+	Mode   string `query:"mode"   validate:"omitempty,oneof=restrict cascade reassign"`
+	Target int    `query:"target" validate:"required_if=Mode reassign,omitempty,min=1"`
+}
+
+type deleteGroupResponse struct {
+	Mode      string `json:"mode"      example:"cascade"`
+	Students  int    `json:"students"  example:"120"`
+	Subgroups int    `json:"subgroups" example:"4"`
 }
 
 // @Summary     Delete group
-// @Description Remove an academic group from the system
+// @Description Remove an academic group from the system. In restrict mode, the default, groups with subgroups
+// @Description or students are refused, cascade mode deletes them too and reassign mode moves them to the target group
 // @ID          delete-group
 // @Tags  	    groups
 // @Accept      json
 // @Produce     json
 // @Param       id path int true "Group ID"
+// @Param       mode query string false "What happens to subgroups and students" Enums(restrict, cascade, reassign)
+// @Param       target query int false "Group receiving the subgroups and students in reassign mode"
+// @Param       If-Match header string true "ETag of the group being removed, * to skip the check"
+// @Success     200 {object} deleteGroupResponse
 // @Success     204 "No Content"
-// @Failure     400 {object} response
-// @Failure     404 {object} response
-// @Failure     409 {object} response
-// @Failure     500 {object} response
+// @Failure     400 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     409 {object} problem
+// @Failure     412 {object} problem
+// @Failure     428 {object} problem
+// @Failure     500 {object} problem
 // @Router      /groups/{id} [delete]
 func (r *groupRoutes) deleteGroup(ctx *fiber.Ctx) error {
 	id, err := strconv.Atoi(ctx.Params("id"))
@@ -221,21 +494,319 @@ func (r *groupRoutes) deleteGroup(ctx *fiber.Ctx) error {
 		return errorResponse(ctx, http.StatusBadRequest, "invalid id parameter")
 	}
 
-	// First check if group exists
-	_, err = r.g.GetGroupByID(ctx.UserContext(), id)
+	version, err := ifMatch(ctx)
 	if err != nil {
-		r.l.Error(err, "http - v1 - deleteGroup - r.g.GetGroupByID")
-		return errorResponse(ctx, http.StatusNotFound, "group not found")
+		return handleError(ctx, r.l, err, "http - v1 - deleteGroup - ifMatch")
 	}
 
-	err = r.g.DeleteGroup(ctx.UserContext(), id)
+	var request deleteGroupQuery
+	if err = ctx.QueryParser(&request); err != nil {
+		r.l.Error(err, "http - v1 - deleteGroup")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid query parameters")
+	}
+
+	if err = r.v.Struct(request); err != nil {
+		return handleError(ctx, r.l, validationError(err), "http - v1 - deleteGroup - validation")
+	}
+
+	if request.Mode == "" {
+		request.Mode = entity.DeleteRestrict
+	}
+
+	change, err := r.g.DeleteGroup(ctx.UserContext(), id, version, entity.GroupDeletion{
+		Mode:   request.Mode,
+		Target: request.Target,
+	})
 	if err != nil {
-		if err.Error() == "cannot delete group with subgroups" {
-			return errorResponse(ctx, http.StatusConflict, "cannot delete group with subgroups")
+		return handleError(ctx, r.l, err, "http - v1 - deleteGroup - r.g.DeleteGroup")
+	}
+
+	if request.Mode == entity.DeleteRestrict {
+		return ctx.SendStatus(http.StatusNoContent)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(deleteGroupResponse{
+		Mode:      request.Mode,
+		Students:  change.Students,
+		Subgroups: change.Subgroups,
+	})
+}
+
+// @Summary     Restore group
+// @Description Undo the deletion of an academic group, the parent group must not be deleted
+// @ID          restore-group
+// @Tags  	    groups
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Group ID"
+// @Success     200 {object} entity.Group
+// @Header      200 {string} ETag "New version of the group"
+// @Failure     400 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /groups/{id}/restore [post]
+func (r *groupRoutes) restoreGroup(ctx *fiber.Ctx) error {
+	id, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - restoreGroup")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid id parameter")
+	}
+
+	group, err := r.g.RestoreGroup(ctx.UserContext(), id)
+	if err != nil {
+		return handleError(ctx, r.l, err, "http - v1 - restoreGroup - r.g.RestoreGroup")
+	}
+
+	setETag(ctx, group.Version)
+
+	return ctx.Status(http.StatusOK).JSON(group)
+}
+
+type moveGroupRequest struct {
+	ParentID *int `json:"parent_id" validate:"omitempty,min=1" example:"2"`
+}
+
+// @Summary     Move group
+// @Description Move a group with all its subgroups and students under a new parent, a missing or null parent_id
+// @Description makes it a root group. The response counts the subgroups and students that moved along.
+// @ID          move-group
+// @Tags  	    groups
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Group ID"
+// @Param       If-Match header string true "ETag of the group being moved, * to skip the check"
+// @Param       request body moveGroupRequest true "New parent"
+// @Success     200 {object} entity.GroupChange
+// @Header      200 {string} ETag "New version of the group"
+// @Failure     400 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     409 {object} problem
+// @Failure     412 {object} problem
+// @Failure     422 {object} problem
+// @Failure     428 {object} problem
+// @Failure     500 {object} problem
+// @Router      /groups/{id}/move [post]
+func (r *groupRoutes) moveGroup(ctx *fiber.Ctx) error {
+	id, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - moveGroup")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid id parameter")
+	}
+
+	version, err := ifMatch(ctx)
+	if err != nil {
+		return handleError(ctx, r.l, err, "http - v1 - moveGroup - ifMatch")
+	}
+
+	var request moveGroupRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		r.l.Error(err, "http - v1 - moveGroup")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid request body")
+	}
+
+	if err := r.v.Struct(request); err != nil {
+		return handleError(ctx, r.l, validationError(err), "http - v1 - moveGroup - validation")
+	}
+
+	change, err := r.g.MoveGroup(ctx.UserContext(), id, request.ParentID, version)
+	if err != nil {
+		return handleError(ctx, r.l, err, "http - v1 - moveGroup - r.g.MoveGroup")
+	}
+
+	setETag(ctx, change.Group.Version)
+
+	return ctx.Status(http.StatusOK).JSON(change)
+}
+
+// @Summary     Merge group
+// @Description Move the students and subgroups of a group into the target group and delete the group.
+// @Description The response holds the target group and counts the students and subgroups that were moved.
+// @ID          merge-group
+// @Tags  	    groups
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "ID of the group being merged"
+// @Param       target path int true "ID of the group to merge into"
+// @Param       If-Match header string true "ETag of the group being merged, * to skip the check"
+// @Success     200 {object} entity.GroupChange
+// @Header      200 {string} ETag "Version of the target group"
+// @Failure     400 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     409 {object} problem
+// @Failure     412 {object} problem
+// @Failure     428 {object} problem
+// @Failure     500 {object} problem
+// @Router      /groups/{id}/merge-into/{target} [post]
+func (r *groupRoutes) mergeGroup(ctx *fiber.Ctx) error {
+	id, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - mergeGroup")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid id parameter")
+	}
+
+	target, err := strconv.Atoi(ctx.Params("target"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - mergeGroup")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid target parameter")
+	}
+
+	version, err := ifMatch(ctx)
+	if err != nil {
+		return handleError(ctx, r.l, err, "http - v1 - mergeGroup - ifMatch")
+	}
+
+	change, err := r.g.MergeGroup(ctx.UserContext(), id, target, version)
+	if err != nil {
+		return handleError(ctx, r.l, err, "http - v1 - mergeGroup - r.g.MergeGroup")
+	}
+
+	setETag(ctx, change.Group.Version)
+
+	return ctx.Status(http.StatusOK).JSON(change)
+}
+
+type groupOperation struct {
+	Op       string `json:"op"        validate:"required,oneof=create update delete" example:"update"`
+	ID       int    `json:"id"        validate:"required_unless=Op create"           example:"1"`
+	Version  int    `json:"version"   validate:"required_unless=Op create"           example:"3"`
+	Name     string `json:"name"      validate:"required_unless=Op delete"           example:"Computer Science"`
+	ParentID *int   `json:"parent_id" validate:"omitempty,min=1"                     example:"2"`
+}
+
+type groupBatchRequest struct {
+	Mode       string           `json:"mode"       validate:"omitempty,oneof=atomic best_effort" example:"atomic"`
+	Operations []groupOperation `json:"operations" validate:"required,min=1,max=1000,dive"`
+}
+
+// @Summary     Create, update and delete groups in bulk
+// @Description Apply a list of operations in order in a single transaction and report the outcome of each,
+// @Description every operation sees the groups as left by the operations before it.
+// @Description In atomic mode, the default, nothing is stored unless every operation succeeds,
+// @Description in best_effort mode the failed operations are skipped and the others stored.
+// @Description Update and delete operations take the version of the group as returned in its ETag.
+// @ID          batch-groups
+// @Tags  	    groups
+// @Accept      json
+// @Produce     json
+// @Param       request body groupBatchRequest true "Operations"
+// @Success     200 {object} batchResponse[entity.Group]
+// @Failure     400 {object} problem
+// @Failure     403 {object} problem
+// @Failure     500 {object} problem
+// @Router      /groups/batch [post]
+func (r *groupRoutes) batchGroups(ctx *fiber.Ctx) error {
+	var request groupBatchRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		r.l.Error(err, "http - v1 - batchGroups")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid request body")
+	}
+
+	if err := r.v.Struct(request); err != nil {
+		return handleError(ctx, r.l, validationError(err), "http - v1 - batchGroups - validation")
+	}
+
+	ops := make([]entity.BatchOp[entity.Group], len(request.Operations))
+	for i, op := range request.Operations {
+		ops[i] = entity.BatchOp[entity.Group]{
+			Op: op.Op,
+			Item: entity.Group{
+				ID:       op.ID,
+				Name:     op.Name,
+				ParentID: op.ParentID,
+				Version:  op.Version,
+			},
+		}
+	}
+
+	report, err := r.g.BatchGroups(ctx.UserContext(), ops, request.Mode != _batchBestEffort)
+	if err != nil {
+		return handleError(ctx, r.l, err, "http - v1 - batchGroups - r.g.BatchGroups")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(newBatchResponse(ctx, report))
+}
+
+// @Summary     Export groups
+// @Description Stream the group tree as CSV, XLSX or JSON Lines, parents before their subgroups.
+// @Description Tabular exports flatten the tree into level_N columns holding the names from the root group down,
+// @Description the format is negotiated from the Accept header unless given explicitly
+// @ID          export-groups
+// @Tags  	    groups
+// @Produce     text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/x-ndjson
+// @Param       format query string false "File format" Enums(csv, xlsx, jsonl)
+// @Success     200 {file} file
+// @Failure     400 {object} problem
+// @Failure     403 {object} problem
+// @Failure     406 {object} problem
+// @Failure     500 {object} problem
+// @Router      /groups/export [get]
+func (r *groupRoutes) exportGroups(ctx *fiber.Ctx) error {
+	var request exportQuery
+	if err := ctx.QueryParser(&request); err != nil {
+		r.l.Error(err, "http - v1 - exportGroups")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid query parameters")
+	}
+
+	if err := r.v.Struct(request); err != nil {
+		return handleError(ctx, r.l, validationError(err), "http - v1 - exportGroups - validation")
+	}
+
+	format := exportFormat(ctx, request.Format)
+	if format == "" {
+		return errorResponse(ctx, http.StatusNotAcceptable, "groups can be exported as text/csv, XLSX or application/x-ndjson")
+	}
+
+	// The export runs after the handler has returned
+	uctx := ctx.UserContext()
+
+	// Authorized before the stream starts, a denied export still gets its status
+	export, err := r.g.ExportGroups(uctx, entity.GroupFilter{})
+	if err != nil {
+		return handleError(ctx, r.l, err, "http - v1 - exportGroups - r.g.ExportGroups")
+	}
+
+	return streamExport(ctx, format, "groups", func(open func([]string) (exportWriter, error)) error {
+		var w exportWriter
+
+		err := export(func(g entity.GroupPath) error {
+			// The level columns are known with the first group, which carries the depth of the export
+			if w == nil {
+				var err error
+				if w, err = open(groupExportHeader(g.Levels)); err != nil {
+					return err
+				}
+			}
+
+			cells := []string{
+				strconv.Itoa(g.ID),
+				formatID(g.ParentID),
+				g.Name,
+				strconv.Itoa(len(g.Path) - 1),
+				strings.Join(g.Path, " / "),
+			}
+
+			return w.Write(g, append(cells, g.Path...))
+		})
+		if err == nil && w == nil {
+			_, err = open(groupExportHeader(0))
 		}
-		r.l.Error(err, "http - v1 - deleteGroup - r.g.DeleteGroup")
-		return errorResponse(ctx, http.StatusInternalServerError, "failed to delete group")
+
+		return err
+	}, func(err error) {
+		r.l.Error(err, "http - v1 - exportGroups - export")
+	})
+}
+
+// groupExportHeader returns the header of a group export with the given number of level columns.
+func groupExportHeader(levels int) []string {
+	header := []string{"id", "parent_id", "name", "depth", "path"}
+	for i := 1; i <= levels; i++ {
+		header = append(header, "level_"+strconv.Itoa(i))
 	}
 
-	return ctx.SendStatus(http.StatusNoContent)
+	return header
 }
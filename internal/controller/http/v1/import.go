@@ -0,0 +1,163 @@
+package v1
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Roster file formats.
+const (
+	_formatCSV  = "csv"
+	_formatXLSX = "xlsx"
+)
+
+// _maxImportRows limits the number of students imported at once.
+const _maxImportRows = 5000
+
+// _bom is the UTF-8 byte order mark spreadsheet applications put in front of CSV files.
+var _bom = []byte("\xef\xbb\xbf") //nolint:gochecknoglobals // constant bytes
+
+var (
+	errUnknownFormat = errors.New("unknown file format, use a .csv or .xlsx file")
+	errEmptyRoster   = errors.New("file contains no students")
+	errLargeRoster   = fmt.Errorf("file contains more than %d students", _maxImportRows)
+)
+
+// _rosterColumns maps the accepted header names of a roster to the student fields.
+var _rosterColumns = map[string]string{ //nolint:gochecknoglobals // lookup table
+	"name":       "name",
+	"email":      "email",
+	"e-mail":     "email",
+	"group":      "group",
+	"group_id":   "group",
+	"group_name": "group",
+}
+
+// rosterRecord is a non-empty row of a roster with its line number.
+type rosterRecord struct {
+	line   int
+	fields map[string]string
+}
+
+// readRoster reads the students of a CSV or XLSX roster. The first row is a header naming
+// the columns, the format is taken from the file name unless given explicitly.
+func readRoster(header *multipart.FileHeader, format string) ([]rosterRecord, error) {
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(header.Filename)), ".")
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return nil, fmt.Errorf("header.Open: %w", err)
+	}
+	defer file.Close()
+
+	var rows [][]string
+
+	switch format {
+	case _formatCSV:
+		rows, err = readCSV(file)
+	case _formatXLSX:
+		rows, err = readXLSX(file)
+	default:
+		return nil, errUnknownFormat
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return rosterRecords(rows)
+}
+
+// readCSV reads a comma or semicolon separated file, as saved by spreadsheet applications.
+func readCSV(r io.Reader) ([][]string, error) {
+	br := bufio.NewReader(r)
+
+	// Skip the byte order mark
+	if bom, _ := br.Peek(len(_bom)); bytes.Equal(bom, _bom) {
+		_, _ = br.Discard(len(_bom))
+	}
+
+	reader := csv.NewReader(br)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	// Guess the separator from the header, a short file is peeked whole
+	head, _ := br.Peek(br.Size())
+	if line, _, _ := bytes.Cut(head, []byte("\n")); bytes.Count(line, []byte(";")) > bytes.Count(line, []byte(",")) {
+		reader.Comma = ';'
+	}
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("reader.ReadAll: %w", err)
+	}
+
+	return rows, nil
+}
+
+// readXLSX reads the first sheet of a workbook.
+func readXLSX(r io.Reader) ([][]string, error) {
+	book, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("excelize.OpenReader: %w", err)
+	}
+	defer book.Close()
+
+	rows, err := book.GetRows(book.GetSheetName(0))
+	if err != nil {
+		return nil, fmt.Errorf("book.GetRows: %w", err)
+	}
+
+	return rows, nil
+}
+
+// rosterRecords maps the rows after the header to the student fields, skipping blank rows.
+func rosterRecords(rows [][]string) ([]rosterRecord, error) {
+	if len(rows) < 2 { //nolint:mnd // header and at least one student
+		return nil, errEmptyRoster
+	}
+
+	columns := make([]string, len(rows[0]))
+	for i, name := range rows[0] {
+		columns[i] = _rosterColumns[strings.ToLower(strings.TrimSpace(name))]
+	}
+
+	records := make([]rosterRecord, 0, len(rows)-1)
+
+	for i, row := range rows[1:] {
+		record := rosterRecord{line: i + 2, fields: make(map[string]string, len(_rosterColumns))} //nolint:mnd // lines are 1-based after the header
+		blank := true
+
+		for j, value := range row {
+			value = strings.TrimSpace(value)
+			if j < len(columns) && columns[j] != "" && value != "" {
+				record.fields[columns[j]] = value
+				blank = false
+			}
+		}
+
+		if !blank {
+			records = append(records, record)
+		}
+	}
+
+	switch {
+	case len(records) == 0:
+		return nil, errEmptyRoster
+	case len(records) > _maxImportRows:
+		return nil, errLargeRoster
+	}
+
+	return records, nil
+}
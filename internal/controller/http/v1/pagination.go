@@ -0,0 +1,79 @@
+package v1
+
+import (
+	"strings"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+)
+
+// newPageRequest builds a page request from the limit, sort and cursor query parameters.
+// The cursor must have been issued for the same sort order.
+func newPageRequest(limit int, sort, cursor string) (entity.PageRequest, error) {
+	page := entity.PageRequest{
+		Limit: limit,
+		Sort:  strings.TrimPrefix(sort, "-"),
+		Desc:  strings.HasPrefix(sort, "-"),
+	}
+
+	if page.Limit == 0 {
+		page.Limit = entity.DefaultPageLimit
+	}
+
+	if page.Sort == "" {
+		page.Sort = entity.SortByID
+	}
+
+	if cursor == "" {
+		return page, nil
+	}
+
+	after, err := entity.DecodeCursor(cursor)
+	if err != nil {
+		return entity.PageRequest{}, err
+	}
+
+	if after.Sort != page.Sort || after.Desc != page.Desc {
+		return entity.PageRequest{}, entity.ErrInvalidCursor
+	}
+
+	page.After = &after
+
+	return page, nil
+}
+
+// encodeCursor returns the opaque next_cursor value, empty on the last page.
+func encodeCursor(c *entity.Cursor) string {
+	if c == nil {
+		return ""
+	}
+
+	return c.Encode()
+}
+
+// depthLimit converts an omitted max_depth query parameter into an unlimited depth.
+func depthLimit(maxDepth *int) int {
+	if maxDepth == nil {
+		return entity.UnlimitedDepth
+	}
+
+	return *maxDepth
+}
+
+// optionalID converts an omitted numeric query parameter into a nil pointer.
+func optionalID(id int) *int {
+	if id == 0 {
+		return nil
+	}
+
+	return &id
+}
+
+// optionalTime converts an omitted time query parameter into a nil pointer.
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+
+	return &t
+}
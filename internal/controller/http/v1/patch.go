@@ -0,0 +1,74 @@
+package v1
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/gofiber/fiber/v2"
+)
+
+// _mergePatchContentType is the media type of JSON Merge Patch (RFC 7396) documents.
+const _mergePatchContentType = "application/merge-patch+json"
+
+var errUnsupportedMediaType = errors.New("unsupported media type")
+
+// mergePatch is a decoded JSON Merge Patch document: present members are changed,
+// members set to null are removed and absent members are left unchanged.
+type mergePatch map[string]json.RawMessage
+
+// parseMergePatch decodes the request body both as a merge patch and into target,
+// which must be a pointer. Plain application/json bodies are accepted as well.
+func parseMergePatch(ctx *fiber.Ctx, target any) (mergePatch, error) {
+	mediaType, _, err := mime.ParseMediaType(ctx.Get(fiber.HeaderContentType))
+	if err != nil || (mediaType != _mergePatchContentType && mediaType != fiber.MIMEApplicationJSON) {
+		return nil, errUnsupportedMediaType
+	}
+
+	var patch mergePatch
+	if err := json.Unmarshal(ctx.Body(), &patch); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(ctx.Body(), target); err != nil {
+		return nil, err
+	}
+
+	return patch, nil
+}
+
+// patchBodyError writes the response for a merge patch body that could not be parsed.
+func patchBodyError(ctx *fiber.Ctx, err error) error {
+	if errors.Is(err, errUnsupportedMediaType) {
+		return errorResponse(ctx, http.StatusUnsupportedMediaType, "use "+_mergePatchContentType)
+	}
+
+	return errorResponse(ctx, http.StatusBadRequest, "invalid request body")
+}
+
+// has reports whether the patch changes the member.
+func (p mergePatch) has(key string) bool {
+	_, ok := p[key]
+
+	return ok
+}
+
+// nonNullable returns a validation error listing the given members that the patch sets to null.
+func (p mergePatch) nonNullable(keys ...string) error {
+	var fields []entity.FieldError
+
+	for _, key := range keys {
+		if raw, ok := p[key]; ok && bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+			fields = append(fields, entity.FieldError{Field: key, Tag: "required", Message: "cannot be removed"})
+		}
+	}
+
+	if len(fields) == 0 {
+		return nil
+	}
+
+	return entity.NewError(entity.ErrValidation, "validation_failed", "validation failed", fields...)
+}
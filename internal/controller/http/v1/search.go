@@ -0,0 +1,33 @@
+package v1
+
+import (
+	"github.com/evrone/go-clean-template/internal/entity"
+)
+
+// searchQuery holds the query parameters of a search on a list endpoint.
+type searchQuery struct {
+	Query          string `query:"query"           validate:"max=200"`
+	Mode           string `query:"mode"            validate:"omitempty,oneof=exact prefix fuzzy"`
+	Limit          int    `query:"limit"           validate:"omitempty,min=1,max=500"`
+	IncludeDeleted bool   `query:"include_deleted"`
+}
+
+// newSearchQuery builds a search from the query parameters, searching word prefixes by default.
+func newSearchQuery(request searchQuery) entity.SearchQuery {
+	query := entity.SearchQuery{
+		Text:           request.Query,
+		Mode:           request.Mode,
+		Limit:          request.Limit,
+		IncludeDeleted: request.IncludeDeleted,
+	}
+
+	if query.Mode == "" {
+		query.Mode = entity.SearchPrefix
+	}
+
+	if query.Limit == 0 {
+		query.Limit = entity.DefaultPageLimit
+	}
+
+	return query
+}
@@ -1,8 +1,12 @@
 package v1
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/evrone/go-clean-template/internal/entity"
 	"github.com/evrone/go-clean-template/internal/usecase"
@@ -13,37 +17,114 @@ import (
 
 type studentRoutes struct {
 	s usecase.Student
+	p usecase.Privacy
 	l logger.Interface
 	v *validator.Validate
 }
 
-func NewStudentRoutes(router fiber.Router, s usecase.Student, l logger.Interface) {
-	r := &studentRoutes{s, l, validator.New(validator.WithRequiredStructEnabled())}
+func NewStudentRoutes(router fiber.Router, s usecase.Student, p usecase.Privacy, l logger.Interface) {
+	r := &studentRoutes{s, p, l, newValidator()}
 
 	// Register routes
 	router.Post("/students", r.createStudent)
 	router.Get("/students", r.getStudents)
+	router.Get("/students/export", r.exportStudents)
 	router.Get("/students/:id", r.getStudentByID)
+	router.Get("/students/:id/contact", r.getStudentContact)
+	router.Get("/students/:id/enrollments", r.getStudentEnrollments)
+	router.Get("/groups/:id/students", r.getGroupStudents)
 	router.Put("/students/:id", r.updateStudent)
+	router.Patch("/students/:id", r.patchStudent)
 	router.Delete("/students/:id", r.deleteStudent)
+	router.Post("/students/:id/restore", r.restoreStudent)
+	router.Post("/students/:id/status", r.changeStudentStatus)
+	router.Post("/students/import", r.importStudents)
+	router.Post("/students/batch", r.batchStudents)
+}
+
+// mask hides or masks the personal data of the students as the privacy policy says for the endpoint.
+func (r *studentRoutes) mask(ctx context.Context, endpoint string, students ...*entity.Student) error {
+	mask, err := r.p.StudentMask(ctx, endpoint)
+	if err != nil {
+		return fmt.Errorf("r.p.StudentMask: %w", err)
+	}
+
+	for _, s := range students {
+		if err = mask(s); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// emailRevealed returns a function telling whether the caller sees the email of a student in full
+// on endpoints of the given kind. Finding a student by its exact email reveals the email,
+// so the email filter only finds the students whose emails the caller may see.
+func (r *studentRoutes) emailRevealed(ctx context.Context, endpoint string) (func(entity.Student) (bool, error), error) {
+	view, err := r.p.StudentView(ctx, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("r.p.StudentView: %w", err)
+	}
+
+	return func(s entity.Student) (bool, error) {
+		v, err := view(s)
+
+		return v == entity.PIIFull, err
+	}, nil
+}
+
+// pointers returns pointers to the elements of a slice.
+func pointers[T any](items []T) []*T {
+	p := make([]*T, len(items))
+	for i := range items {
+		p[i] = &items[i]
+	}
+
+	return p
+}
+
+type guardianRequest struct {
+	Name         string `json:"name"         validate:"required,max=255"                       example:"Jane Doe"`
+	Relationship string `json:"relationship" validate:"required,max=64"                        example:"mother"`
+	Phone        string `json:"phone"        validate:"required_without=Email,omitempty,e164" example:"+4915112345678"`
+	Email        string `json:"email"        validate:"omitempty,email"                        example:"jane@example.com"`
+}
+
+// newGuardians converts the guardians of a request, never returning nil.
+func newGuardians(requests []guardianRequest) []entity.Guardian {
+	guardians := make([]entity.Guardian, len(requests))
+	for i, g := range requests {
+		guardians[i] = entity.Guardian(g)
+	}
+
+	return guardians
 }
 
 type createStudentRequest struct {
-	Name    string `json:"name" validate:"required"`
-	Email   string `json:"email" validate:"required,email"`
-	GroupID int    `json:"group_id" validate:"required"`
+	Name             string            `json:"name" validate:"required"`
+	Email            string            `json:"email" validate:"required,email"`
+	GroupID          int               `json:"group_id" validate:"required"`
+	DateOfBirth      *entity.Date      `json:"date_of_birth" validate:"omitempty,lt" swaggertype:"string" format:"date" example:"2005-03-01"`
+	Phone            string            `json:"phone" validate:"omitempty,e164"`
+	EnrollmentNumber string            `json:"enrollment_number" validate:"omitempty,max=32"`
+	Guardians        []guardianRequest `json:"guardians" validate:"omitempty,max=5,dive"`
 }
 
 // @Summary     Create a student
-// @Description Add a new student to the system
+// @Description Add a new student to the system, new students are active
 // @ID          create-student
 // @Tags  	    students
 // @Accept      json
 // @Produce     json
 // @Param       request body createStudentRequest true "Student data"
 // @Success     201 {object} entity.Student
-// @Failure     400 {object} response
-// @Failure     500 {object} response
+// @Header      201 {string} ETag "Version of the created student"
+// @Failure     400 {object} problem
+// @Failure     403 {object} problem
+// @Failure     409 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
 // @Router      /students [post]
 func (r *studentRoutes) createStudent(ctx *fiber.Ctx) error {
 	var request createStudentRequest
@@ -54,73 +135,258 @@ func (r *studentRoutes) createStudent(ctx *fiber.Ctx) error {
 	}
 
 	if err := r.v.Struct(request); err != nil {
-		r.l.Error(err, "http - v1 - createStudent - validation")
-		return errorResponse(ctx, http.StatusBadRequest, "validation failed")
+		return handleError(ctx, r.l, validationError(err), "http - v1 - createStudent - validation")
 	}
 
 	student := entity.Student{
-		Name:    request.Name,
-		Email:   request.Email,
-		GroupID: request.GroupID,
+		Name:             request.Name,
+		Email:            request.Email,
+		GroupID:          request.GroupID,
+		DateOfBirth:      request.DateOfBirth,
+		Phone:            request.Phone,
+		EnrollmentNumber: request.EnrollmentNumber,
+		Guardians:        newGuardians(request.Guardians),
 	}
 
 	createdStudent, err := r.s.CreateStudent(ctx.UserContext(), student)
 	if err != nil {
-		r.l.Error(err, "http - v1 - createStudent - r.s.CreateStudent")
-		return errorResponse(ctx, http.StatusInternalServerError, "failed to create student")
+		return handleError(ctx, r.l, err, "http - v1 - createStudent - r.s.CreateStudent")
+	}
+
+	if err = r.mask(ctx.UserContext(), entity.PIIDetail, &createdStudent); err != nil {
+		return handleError(ctx, r.l, err, "http - v1 - createStudent - r.mask")
 	}
 
+	setETag(ctx, createdStudent.Version)
+
 	return ctx.Status(http.StatusCreated).JSON(createdStudent)
 }
 
+type listStudentsQuery struct {
+	Limit          int    `query:"limit"           validate:"omitempty,min=1,max=500"`
+	Cursor         string `query:"cursor"`
+	Sort           string `query:"sort"            validate:"omitempty,oneof=id -id name -name group_id -group_id"`
+	GroupID        int    `query:"group_id"        validate:"omitempty,min=1"`
+	Email          string `query:"email"           validate:"omitempty,email"`
+	EmailDomain    string `query:"email_domain"    validate:"omitempty,fqdn"`
+	IncludeDeleted bool   `query:"include_deleted"`
+}
+
+type studentListResponse struct {
+	Items      []entity.Student `json:"items"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+	Total      int              `json:"total"`
+}
+
 // @Summary     Get all students
-// @Description Retrieve a list of all students
+// @Description Retrieve a page of students, optionally filtered by group or email domain.
+// @Description With query, an array of entity.StudentHit ranked by relevance is returned instead,
+// @Description matching student or group names in Cyrillic and Latin spelling.
 // @ID          get-students
 // @Tags  	    students
 // @Accept      json
 // @Produce     json
-// @Param       query query string false "Search query"
-// @Success     200 {array} entity.Student
-// @Failure     500 {object} response
+// @Param       query           query string false "Search query"
+// @Param       mode            query string false "Search mode (default prefix)" Enums(exact, prefix, fuzzy)
+// @Param       limit           query int    false "Page size (1-500, default 50)"
+// @Param       cursor          query string false "Cursor returned as next_cursor by the previous page"
+// @Param       sort            query string false "Sort field, prefix with - for descending" Enums(id, -id, name, -name, group_id, -group_id)
+// @Param       group_id        query int    false "Only students of this group"
+// @Param       email           query string false "Only the student with this email, ignoring case, if the caller sees its email in full"
+// @Param       email_domain    query string false "Only students with an email in this domain"
+// @Param       include_deleted query bool   false "Also list soft-deleted students, admins of every group only"
+// @Success     200 {object} studentListResponse
+// @Failure     400 {object} problem
+// @Failure     403 {object} problem
+// @Failure     500 {object} problem
 // @Router      /students [get]
 func (r *studentRoutes) getStudents(ctx *fiber.Ctx) error {
 	// Check if there's a search query
-	query := ctx.Query("query")
-	if query != "" {
-		return r.searchStudents(ctx, query)
+	if ctx.Query("query") != "" {
+		return r.searchStudents(ctx)
+	}
+
+	var request listStudentsQuery
+	if err := ctx.QueryParser(&request); err != nil {
+		r.l.Error(err, "http - v1 - getStudents")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid query parameters")
 	}
 
-	students, err := r.s.GetStudents(ctx.UserContext())
+	if err := r.v.Struct(request); err != nil {
+		return handleError(ctx, r.l, validationError(err), "http - v1 - getStudents - validation")
+	}
+
+	page, err := newPageRequest(request.Limit, request.Sort, request.Cursor)
 	if err != nil {
-		r.l.Error(err, "http - v1 - getStudents - r.s.GetStudents")
-		return errorResponse(ctx, http.StatusInternalServerError, "failed to get students")
+		return handleError(ctx, r.l, err, "http - v1 - getStudents - newPageRequest")
 	}
 
-	return ctx.Status(http.StatusOK).JSON(students)
+	filter := entity.StudentFilter{
+		GroupID:        optionalID(request.GroupID),
+		Email:          request.Email,
+		EmailDomain:    request.EmailDomain,
+		IncludeDeleted: request.IncludeDeleted,
+	}
+
+	students, err := r.s.GetStudents(ctx.UserContext(), filter, page)
+	if err != nil {
+		return handleError(ctx, r.l, err, "http - v1 - getStudents - r.s.GetStudents")
+	}
+
+	if filter.Email != "" {
+		if students, err = r.revealedOnly(ctx.UserContext(), students); err != nil {
+			return handleError(ctx, r.l, err, "http - v1 - getStudents - r.revealedOnly")
+		}
+	}
+
+	if err = r.mask(ctx.UserContext(), entity.PIIList, pointers(students.Items)...); err != nil {
+		return handleError(ctx, r.l, err, "http - v1 - getStudents - r.mask")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(studentListResponse{
+		Items:      students.Items,
+		NextCursor: encodeCursor(students.NextCursor),
+		Total:      students.Total,
+	})
+}
+
+// revealedOnly keeps the students of a page found by email whose emails the caller sees in full on lists.
+// Emails are unique, so the page holds every match.
+func (r *studentRoutes) revealedOnly(ctx context.Context, students entity.Page[entity.Student]) (entity.Page[entity.Student], error) {
+	revealed, err := r.emailRevealed(ctx, entity.PIIList)
+	if err != nil {
+		return entity.Page[entity.Student]{}, err
+	}
+
+	items := make([]entity.Student, 0, len(students.Items))
+
+	for _, s := range students.Items {
+		ok, err := revealed(s)
+		if err != nil {
+			return entity.Page[entity.Student]{}, err
+		}
+
+		if ok {
+			items = append(items, s)
+		}
+	}
+
+	return entity.Page[entity.Student]{Items: items, Total: len(items)}, nil
+}
+
+type groupStudentsQuery struct {
+	Limit     int       `query:"limit"     validate:"omitempty,min=1,max=500"`
+	Cursor    string    `query:"cursor"`
+	Sort      string    `query:"sort"      validate:"omitempty,oneof=id -id name -name group_id -group_id"`
+	Recursive bool      `query:"recursive"`
+	AsOf      time.Time `query:"as_of"`
+}
+
+// @Summary     Get students of a group
+// @Description Retrieve a page of the students of a group, with recursive also those of its subgroups at any depth.
+// @Description With as_of, retrieve the roster of the group at that time: the students enrolled in it then,
+// @Description including those deleted since, as they are now.
+// @ID          get-group-students
+// @Tags  	    groups
+// @Accept      json
+// @Produce     json
+// @Param       id        path  int    true  "Group ID"
+// @Param       recursive query bool   false "Include the students of all subgroups"
+// @Param       as_of     query string false "Roster at this time (RFC 3339)"
+// @Param       limit     query int    false "Page size (1-500, default 50)"
+// @Param       cursor    query string false "Cursor returned as next_cursor by the previous page"
+// @Param       sort      query string false "Sort field, prefix with - for descending" Enums(id, -id, name, -name, group_id, -group_id)
+// @Success     200 {object} studentListResponse
+// @Failure     400 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     500 {object} problem
+// @Router      /groups/{id}/students [get]
+func (r *studentRoutes) getGroupStudents(ctx *fiber.Ctx) error {
+	id, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - getGroupStudents")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid id parameter")
+	}
+
+	var request groupStudentsQuery
+	if err = ctx.QueryParser(&request); err != nil {
+		r.l.Error(err, "http - v1 - getGroupStudents")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid query parameters")
+	}
+
+	if err = r.v.Struct(request); err != nil {
+		return handleError(ctx, r.l, validationError(err), "http - v1 - getGroupStudents - validation")
+	}
+
+	page, err := newPageRequest(request.Limit, request.Sort, request.Cursor)
+	if err != nil {
+		return handleError(ctx, r.l, err, "http - v1 - getGroupStudents - newPageRequest")
+	}
+
+	students, err := r.s.GetGroupStudents(ctx.UserContext(), id, request.Recursive, optionalTime(request.AsOf), page)
+	if err != nil {
+		return handleError(ctx, r.l, err, "http - v1 - getGroupStudents - r.s.GetGroupStudents")
+	}
+
+	if err = r.mask(ctx.UserContext(), entity.PIIList, pointers(students.Items)...); err != nil {
+		return handleError(ctx, r.l, err, "http - v1 - getGroupStudents - r.mask")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(studentListResponse{
+		Items:      students.Items,
+		NextCursor: encodeCursor(students.NextCursor),
+		Total:      students.Total,
+	})
 }
 
 // Search students based on query
-func (r *studentRoutes) searchStudents(ctx *fiber.Ctx, query string) error {
-	students, err := r.s.SearchStudents(ctx.UserContext(), query)
+func (r *studentRoutes) searchStudents(ctx *fiber.Ctx) error {
+	var request searchQuery
+	if err := ctx.QueryParser(&request); err != nil {
+		r.l.Error(err, "http - v1 - searchStudents")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid query parameters")
+	}
+
+	if err := r.v.Struct(request); err != nil {
+		return handleError(ctx, r.l, validationError(err), "http - v1 - searchStudents - validation")
+	}
+
+	students, err := r.s.SearchStudents(ctx.UserContext(), newSearchQuery(request))
 	if err != nil {
-		r.l.Error(err, "http - v1 - searchStudents - r.s.SearchStudents")
-		return errorResponse(ctx, http.StatusInternalServerError, "failed to search students")
+		return handleError(ctx, r.l, err, "http - v1 - searchStudents - r.s.SearchStudents")
+	}
+
+	found := make([]*entity.Student, len(students))
+	for i := range students {
+		found[i] = &students[i].Student
+	}
+
+	if err = r.mask(ctx.UserContext(), entity.PIIList, found...); err != nil {
+		return handleError(ctx, r.l, err, "http - v1 - searchStudents - r.mask")
 	}
 
 	return ctx.Status(http.StatusOK).JSON(students)
 }
 
+type getStudentQuery struct {
+	Include string `query:"include" validate:"omitempty,oneof=path"`
+}
+
 // @Summary     Get student by ID
-// @Description Retrieve a specific student by ID
+// @Description Retrieve a specific student by ID, optionally with the path from the root group down to its group
 // @ID          get-student-by-id
 // @Tags  	    students
 // @Accept      json
 // @Produce     json
-// @Param       id path int true "Student ID"
+// @Param       id      path  int    true  "Student ID"
+// @Param       include query string false "Set to path to load the path of the group" Enums(path)
 // @Success     200 {object} entity.Student
-// @Failure     400 {object} response
-// @Failure     404 {object} response
-// @Failure     500 {object} response
+// @Header      200 {string} ETag "Version of the student, send it back in If-Match"
+// @Failure     400 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     500 {object} problem
 // @Router      /students/{id} [get]
 func (r *studentRoutes) getStudentByID(ctx *fiber.Ctx) error {
 	id, err := strconv.Atoi(ctx.Params("id"))
@@ -129,32 +395,126 @@ func (r *studentRoutes) getStudentByID(ctx *fiber.Ctx) error {
 		return errorResponse(ctx, http.StatusBadRequest, "invalid id parameter")
 	}
 
-	student, err := r.s.GetStudentByID(ctx.UserContext(), id)
+	var request getStudentQuery
+	if err = ctx.QueryParser(&request); err != nil {
+		r.l.Error(err, "http - v1 - getStudentByID")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid query parameters")
+	}
+
+	if err = r.v.Struct(request); err != nil {
+		return handleError(ctx, r.l, validationError(err), "http - v1 - getStudentByID - validation")
+	}
+
+	var student entity.Student
+	if request.Include == "path" {
+		student, err = r.s.GetStudentWithPath(ctx.UserContext(), id)
+	} else {
+		student, err = r.s.GetStudentByID(ctx.UserContext(), id)
+	}
+
 	if err != nil {
-		r.l.Error(err, "http - v1 - getStudentByID - r.s.GetStudentByID")
-		return errorResponse(ctx, http.StatusNotFound, "student not found")
+		return handleError(ctx, r.l, err, "http - v1 - getStudentByID - r.s.GetStudentByID")
 	}
 
+	if err = r.mask(ctx.UserContext(), entity.PIIDetail, &student); err != nil {
+		return handleError(ctx, r.l, err, "http - v1 - getStudentByID - r.mask")
+	}
+
+	setETag(ctx, student.Version)
+
 	return ctx.Status(http.StatusOK).JSON(student)
 }
 
+// @Summary     Get student contact details
+// @Description Retrieve the email and phone number of a student and its guardians in full,
+// @Description which other responses hide or mask. Needs the contact role of the privacy policy on the group.
+// @ID          get-student-contact
+// @Tags  	    students
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Student ID"
+// @Success     200 {object} entity.StudentContact
+// @Failure     400 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     500 {object} problem
+// @Router      /students/{id}/contact [get]
+func (r *studentRoutes) getStudentContact(ctx *fiber.Ctx) error {
+	id, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - getStudentContact")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid id parameter")
+	}
+
+	contact, err := r.p.GetStudentContact(ctx.UserContext(), id)
+	if err != nil {
+		return handleError(ctx, r.l, err, "http - v1 - getStudentContact - r.p.GetStudentContact")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(contact)
+}
+
+type enrollmentListResponse struct {
+	Items []entity.Enrollment `json:"items"`
+}
+
+// @Summary     Get student enrollments
+// @Description Retrieve the group memberships of a student, the earliest first, the current one without ended_at
+// @ID          get-student-enrollments
+// @Tags  	    students
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Student ID"
+// @Success     200 {object} enrollmentListResponse
+// @Failure     400 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     500 {object} problem
+// @Router      /students/{id}/enrollments [get]
+func (r *studentRoutes) getStudentEnrollments(ctx *fiber.Ctx) error {
+	id, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - getStudentEnrollments")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid id parameter")
+	}
+
+	enrollments, err := r.s.GetStudentEnrollments(ctx.UserContext(), id)
+	if err != nil {
+		return handleError(ctx, r.l, err, "http - v1 - getStudentEnrollments - r.s.GetStudentEnrollments")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(enrollmentListResponse{Items: enrollments})
+}
+
 type updateStudentRequest struct {
-	Name    string `json:"name" validate:"required"`
-	GroupID int    `json:"group_id" validate:"required"`
+	Name             string            `json:"name" validate:"required"`
+	Email            string            `json:"email" validate:"required,email"`
+	GroupID          int               `json:"group_id" validate:"required"`
+	DateOfBirth      *entity.Date      `json:"date_of_birth" validate:"omitempty,lt" swaggertype:"string" format:"date" example:"2005-03-01"`
+	Phone            string            `json:"phone" validate:"omitempty,e164"`
+	EnrollmentNumber string            `json:"enrollment_number" validate:"omitempty,max=32"`
+	Guardians        []guardianRequest `json:"guardians" validate:"omitempty,max=5,dive"`
 }
 
 // @Summary     Update student
-// @Description Update a student's information
+// @Description Update a student's information, the status is changed through POST /students/{id}/status
 // @ID          update-student
 // @Tags  	    students
 // @Accept      json
 // @Produce     json
 // @Param       id path int true "Student ID"
+// @Param       If-Match header string true "ETag of the student being replaced, * to skip the check"
 // @Param       request body updateStudentRequest true "Updated student data"
 // @Success     200 {object} entity.Student
-// @Failure     400 {object} response
-// @Failure     404 {object} response
-// @Failure     500 {object} response
+// @Header      200 {string} ETag "New version of the student"
+// @Failure     400 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     409 {object} problem
+// @Failure     412 {object} problem
+// @Failure     422 {object} problem
+// @Failure     428 {object} problem
+// @Failure     500 {object} problem
 // @Router      /students/{id} [put]
 func (r *studentRoutes) updateStudent(ctx *fiber.Ctx) error {
 	id, err := strconv.Atoi(ctx.Params("id"))
@@ -163,6 +523,11 @@ func (r *studentRoutes) updateStudent(ctx *fiber.Ctx) error {
 		return errorResponse(ctx, http.StatusBadRequest, "invalid id parameter")
 	}
 
+	version, err := ifMatch(ctx)
+	if err != nil {
+		return handleError(ctx, r.l, err, "http - v1 - updateStudent - ifMatch")
+	}
+
 	var request updateStudentRequest
 	if err := ctx.BodyParser(&request); err != nil {
 		r.l.Error(err, "http - v1 - updateStudent")
@@ -170,37 +535,122 @@ func (r *studentRoutes) updateStudent(ctx *fiber.Ctx) error {
 	}
 
 	if err := r.v.Struct(request); err != nil {
-		r.l.Error(err, "http - v1 - updateStudent - validation")
-		return errorResponse(ctx, http.StatusBadRequest, "validation failed")
+		return handleError(ctx, r.l, validationError(err), "http - v1 - updateStudent - validation")
 	}
 
-	// First check if student exists
-	_, err = r.s.GetStudentByID(ctx.UserContext(), id)
+	student := entity.Student{
+		ID:               id,
+		Name:             request.Name,
+		Email:            request.Email,
+		GroupID:          request.GroupID,
+		DateOfBirth:      request.DateOfBirth,
+		Phone:            request.Phone,
+		EnrollmentNumber: request.EnrollmentNumber,
+		Guardians:        newGuardians(request.Guardians),
+		Version:          version,
+	}
+
+	updatedStudent, err := r.s.UpdateStudent(ctx.UserContext(), student)
 	if err != nil {
-		r.l.Error(err, "http - v1 - updateStudent - r.s.GetStudentByID")
-		return errorResponse(ctx, http.StatusNotFound, "student not found")
+		return handleError(ctx, r.l, err, "http - v1 - updateStudent - r.s.UpdateStudent")
 	}
 
-	student := entity.Student{
-		ID:      id,
-		Name:    request.Name,
-		GroupID: request.GroupID,
+	if err = r.mask(ctx.UserContext(), entity.PIIDetail, &updatedStudent); err != nil {
+		return handleError(ctx, r.l, err, "http - v1 - updateStudent - r.mask")
 	}
 
-	err = r.s.UpdateStudent(ctx.UserContext(), student)
+	setETag(ctx, updatedStudent.Version)
+
+	return ctx.Status(http.StatusOK).JSON(updatedStudent)
+}
+
+type patchStudentRequest struct {
+	Name             *string           `json:"name"              validate:"omitempty,min=1"`
+	Email            *string           `json:"email"             validate:"omitempty,email"`
+	GroupID          *int              `json:"group_id"          validate:"omitempty,min=1"`
+	DateOfBirth      *entity.Date      `json:"date_of_birth"     validate:"omitempty,lt" swaggertype:"string" format:"date" example:"2005-03-01"`
+	Phone            *string           `json:"phone"             validate:"omitempty,e164"`
+	EnrollmentNumber *string           `json:"enrollment_number" validate:"omitempty,max=32"`
+	Guardians        []guardianRequest `json:"guardians"         validate:"omitempty,max=5,dive"`
+}
+
+// @Summary     Patch student
+// @Description Change some of a student's fields using a JSON Merge Patch (RFC 7396).
+// @Description Setting date_of_birth to null removes it, guardians are replaced as a whole.
+// @ID          patch-student
+// @Tags  	    students
+// @Accept      application/merge-patch+json
+// @Produce     json
+// @Param       id path int true "Student ID"
+// @Param       If-Match header string true "ETag of the student being changed, * to skip the check"
+// @Param       request body patchStudentRequest true "Fields to change"
+// @Success     200 {object} entity.Student
+// @Header      200 {string} ETag "New version of the student"
+// @Failure     400 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     409 {object} problem
+// @Failure     412 {object} problem
+// @Failure     415 {object} problem
+// @Failure     422 {object} problem
+// @Failure     428 {object} problem
+// @Failure     500 {object} problem
+// @Router      /students/{id} [patch]
+func (r *studentRoutes) patchStudent(ctx *fiber.Ctx) error {
+	id, err := strconv.Atoi(ctx.Params("id"))
 	if err != nil {
-		r.l.Error(err, "http - v1 - updateStudent - r.s.UpdateStudent")
-		return errorResponse(ctx, http.StatusInternalServerError, "failed to update student")
+		r.l.Error(err, "http - v1 - patchStudent")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid id parameter")
 	}
 
-	// Get the updated student to return in response
-	updatedStudent, err := r.s.GetStudentByID(ctx.UserContext(), id)
+	version, err := ifMatch(ctx)
 	if err != nil {
-		r.l.Error(err, "http - v1 - updateStudent - r.s.GetStudentByID")
-		return errorResponse(ctx, http.StatusInternalServerError, "student updated but failed to retrieve updated data")
+		return handleError(ctx, r.l, err, "http - v1 - patchStudent - ifMatch")
 	}
 
-	return ctx.Status(http.StatusOK).JSON(updatedStudent)
+	var request patchStudentRequest
+	patch, err := parseMergePatch(ctx, &request)
+	if err != nil {
+		r.l.Error(err, "http - v1 - patchStudent")
+		return patchBodyError(ctx, err)
+	}
+
+	if err := patch.nonNullable("name", "email", "group_id", "phone", "enrollment_number", "guardians"); err != nil {
+		return handleError(ctx, r.l, err, "http - v1 - patchStudent - validation")
+	}
+
+	if err := r.v.Struct(request); err != nil {
+		return handleError(ctx, r.l, validationError(err), "http - v1 - patchStudent - validation")
+	}
+
+	studentPatch := entity.StudentPatch{
+		Name:             request.Name,
+		Email:            request.Email,
+		GroupID:          request.GroupID,
+		DateOfBirth:      request.DateOfBirth,
+		SetDateOfBirth:   patch.has("date_of_birth"),
+		Phone:            request.Phone,
+		EnrollmentNumber: request.EnrollmentNumber,
+		Version:          version,
+	}
+
+	if patch.has("guardians") {
+		guardians := newGuardians(request.Guardians)
+		studentPatch.Guardians = &guardians
+	}
+
+	student, err := r.s.PatchStudent(ctx.UserContext(), id, studentPatch)
+	if err != nil {
+		return handleError(ctx, r.l, err, "http - v1 - patchStudent - r.s.PatchStudent")
+	}
+
+	if err = r.mask(ctx.UserContext(), entity.PIIDetail, &student); err != nil {
+		return handleError(ctx, r.l, err, "http - v1 - patchStudent - r.mask")
+	}
+
+	setETag(ctx, student.Version)
+
+	return ctx.Status(http.StatusOK).JSON(student)
 }
 
 // @Summary     Delete student
@@ -210,10 +660,14 @@ func (r *studentRoutes) updateStudent(ctx *fiber.Ctx) error {
 // @Accept      json
 // @Produce     json
 // @Param       id path int true "Student ID"
+// @Param       If-Match header string true "ETag of the student being removed, * to skip the check"
 // @Success     204 "No Content"
-// @Failure     400 {object} response
-// @Failure     404 {object} response
-// @Failure     500 {object} response
+// @Failure     400 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     412 {object} problem
+// @Failure     428 {object} problem
+// @Failure     500 {object} problem
 // @Router      /students/{id} [delete]
 func (r *studentRoutes) deleteStudent(ctx *fiber.Ctx) error {
 	id, err := strconv.Atoi(ctx.Params("id"))
@@ -222,18 +676,391 @@ func (r *studentRoutes) deleteStudent(ctx *fiber.Ctx) error {
 		return errorResponse(ctx, http.StatusBadRequest, "invalid id parameter")
 	}
 
-	// First check if student exists
-	_, err = r.s.GetStudentByID(ctx.UserContext(), id)
+	version, err := ifMatch(ctx)
 	if err != nil {
-		r.l.Error(err, "http - v1 - deleteStudent - r.s.GetStudentByID")
-		return errorResponse(ctx, http.StatusNotFound, "student not found")
+		return handleError(ctx, r.l, err, "http - v1 - deleteStudent - ifMatch")
 	}
 
-	err = r.s.DeleteStudent(ctx.UserContext(), id)
+	err = r.s.DeleteStudent(ctx.UserContext(), id, version)
 	if err != nil {
-		r.l.Error(err, "http - v1 - deleteStudent - r.s.DeleteStudent")
-		return errorResponse(ctx, http.StatusInternalServerError, "failed to delete student")
+		return handleError(ctx, r.l, err, "http - v1 - deleteStudent - r.s.DeleteStudent")
 	}
 
 	return ctx.SendStatus(http.StatusNoContent)
 }
+
+// @Summary     Restore student
+// @Description Undo the deletion of a student, the student's group must not be deleted
+// @ID          restore-student
+// @Tags  	    students
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Student ID"
+// @Success     200 {object} entity.Student
+// @Header      200 {string} ETag "New version of the student"
+// @Failure     400 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     409 {object} problem
+// @Failure     422 {object} problem
+// @Failure     500 {object} problem
+// @Router      /students/{id}/restore [post]
+func (r *studentRoutes) restoreStudent(ctx *fiber.Ctx) error {
+	id, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - restoreStudent")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid id parameter")
+	}
+
+	student, err := r.s.RestoreStudent(ctx.UserContext(), id)
+	if err != nil {
+		return handleError(ctx, r.l, err, "http - v1 - restoreStudent - r.s.RestoreStudent")
+	}
+
+	if err = r.mask(ctx.UserContext(), entity.PIIDetail, &student); err != nil {
+		return handleError(ctx, r.l, err, "http - v1 - restoreStudent - r.mask")
+	}
+
+	setETag(ctx, student.Version)
+
+	return ctx.Status(http.StatusOK).JSON(student)
+}
+
+type studentStatusRequest struct {
+	Status   string `json:"status"   validate:"required,oneof=active on_leave graduated expelled" example:"on_leave"`
+	Override bool   `json:"override"                                                               example:"false"`
+}
+
+// @Summary     Change student status
+// @Description Move a student through its life cycle: active students may go on leave, graduate or be expelled,
+// @Description students on leave may return or be expelled, graduated and expelled students keep their status.
+// @Description Other transitions are rejected unless override is set, which needs the admin role on the group.
+// @ID          change-student-status
+// @Tags  	    students
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Student ID"
+// @Param       If-Match header string true "ETag of the student being changed, * to skip the check"
+// @Param       request body studentStatusRequest true "New status"
+// @Success     200 {object} entity.Student
+// @Header      200 {string} ETag "New version of the student"
+// @Failure     400 {object} problem
+// @Failure     403 {object} problem
+// @Failure     404 {object} problem
+// @Failure     409 {object} problem
+// @Failure     412 {object} problem
+// @Failure     422 {object} problem
+// @Failure     428 {object} problem
+// @Failure     500 {object} problem
+// @Router      /students/{id}/status [post]
+func (r *studentRoutes) changeStudentStatus(ctx *fiber.Ctx) error {
+	id, err := strconv.Atoi(ctx.Params("id"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - changeStudentStatus")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid id parameter")
+	}
+
+	version, err := ifMatch(ctx)
+	if err != nil {
+		return handleError(ctx, r.l, err, "http - v1 - changeStudentStatus - ifMatch")
+	}
+
+	var request studentStatusRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		r.l.Error(err, "http - v1 - changeStudentStatus")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid request body")
+	}
+
+	if err := r.v.Struct(request); err != nil {
+		return handleError(ctx, r.l, validationError(err), "http - v1 - changeStudentStatus - validation")
+	}
+
+	student, err := r.s.ChangeStudentStatus(ctx.UserContext(), id, entity.StatusChange{
+		Status:   request.Status,
+		Override: request.Override,
+		Version:  version,
+	})
+	if err != nil {
+		return handleError(ctx, r.l, err, "http - v1 - changeStudentStatus - r.s.ChangeStudentStatus")
+	}
+
+	if err = r.mask(ctx.UserContext(), entity.PIIDetail, &student); err != nil {
+		return handleError(ctx, r.l, err, "http - v1 - changeStudentStatus - r.mask")
+	}
+
+	setETag(ctx, student.Version)
+
+	return ctx.Status(http.StatusOK).JSON(student)
+}
+
+type importStudentsQuery struct {
+	DryRun bool   `query:"dry_run"`
+	Format string `query:"format"  validate:"omitempty,oneof=csv xlsx"`
+}
+
+// @Summary     Import students
+// @Description Create students from a CSV or XLSX roster whose header row names the name, email and group columns,
+// @Description the group is given by ID or name. Rows are checked like single students and stored in one transaction
+// @Description only when every row is valid. With dry_run the roster is only checked.
+// @ID          import-students
+// @Tags  	    students
+// @Accept      multipart/form-data
+// @Produce     json
+// @Param       file    formData file   true  "Roster"
+// @Param       dry_run query    bool   false "Check the roster without storing it"
+// @Param       format  query    string false "File format, taken from the file name by default" Enums(csv, xlsx)
+// @Success     200 {object} entity.ImportReport "Dry run or failed rows, nothing was stored"
+// @Success     201 {object} entity.ImportReport
+// @Failure     400 {object} problem
+// @Failure     403 {object} problem
+// @Failure     415 {object} problem
+// @Failure     500 {object} problem
+// @Router      /students/import [post]
+func (r *studentRoutes) importStudents(ctx *fiber.Ctx) error {
+	var request importStudentsQuery
+	if err := ctx.QueryParser(&request); err != nil {
+		r.l.Error(err, "http - v1 - importStudents")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid query parameters")
+	}
+
+	if err := r.v.Struct(request); err != nil {
+		return handleError(ctx, r.l, validationError(err), "http - v1 - importStudents - validation")
+	}
+
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		r.l.Error(err, "http - v1 - importStudents")
+		return errorResponse(ctx, http.StatusBadRequest, "file is required")
+	}
+
+	records, err := readRoster(file, request.Format)
+	if err != nil {
+		r.l.Error(err, "http - v1 - importStudents - readRoster")
+
+		switch {
+		case errors.Is(err, errUnknownFormat):
+			return errorResponse(ctx, http.StatusUnsupportedMediaType, err.Error())
+		case errors.Is(err, errEmptyRoster), errors.Is(err, errLargeRoster):
+			return errorResponse(ctx, http.StatusBadRequest, err.Error())
+		default:
+			return errorResponse(ctx, http.StatusBadRequest, "invalid file")
+		}
+	}
+
+	rows := make([]entity.StudentImport, len(records))
+	for i, record := range records {
+		student := createStudentRequest{
+			Name:  record.fields["name"],
+			Email: record.fields["email"],
+		}
+
+		rows[i] = entity.StudentImport{
+			Row:     record.line,
+			Student: entity.Student{Name: student.Name, Email: student.Email},
+			Group:   record.fields["group"],
+		}
+
+		// Same rules as for a single student, the group is resolved by the use case
+		rows[i].Errors = fieldErrors(r.v.StructExcept(student, "GroupID"))
+	}
+
+	report, err := r.s.ImportStudents(ctx.UserContext(), rows, request.DryRun)
+	if err != nil {
+		return handleError(ctx, r.l, err, "http - v1 - importStudents - r.s.ImportStudents")
+	}
+
+	if report.Created > 0 {
+		return ctx.Status(http.StatusCreated).JSON(report)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(report)
+}
+
+type studentOperation struct {
+	Op               string            `json:"op"                validate:"required,oneof=create update delete"       example:"update"`
+	ID               int               `json:"id"                validate:"required_unless=Op create"                 example:"1"`
+	Version          int               `json:"version"           validate:"required_unless=Op create"                 example:"3"`
+	Name             string            `json:"name"              validate:"required_unless=Op delete"                 example:"John Doe"`
+	Email            string            `json:"email"             validate:"required_unless=Op delete,omitempty,email" example:"john@example.com"`
+	GroupID          int               `json:"group_id"          validate:"required_unless=Op delete"                 example:"2"`
+	DateOfBirth      *entity.Date      `json:"date_of_birth"     validate:"omitempty,lt"                              swaggertype:"string" format:"date" example:"2005-03-01"`
+	Phone            string            `json:"phone"             validate:"omitempty,e164"                            example:"+4915112345678"`
+	EnrollmentNumber string            `json:"enrollment_number" validate:"omitempty,max=32"                          example:"PH-2024-0042"`
+	Guardians        []guardianRequest `json:"guardians"         validate:"omitempty,max=5,dive"`
+}
+
+type studentBatchRequest struct {
+	Mode       string             `json:"mode"       validate:"omitempty,oneof=atomic best_effort" example:"atomic"`
+	Operations []studentOperation `json:"operations" validate:"required,min=1,max=1000,dive"`
+}
+
+// @Summary     Create, update and delete students in bulk
+// @Description Apply a list of operations in a single transaction and report the outcome of each.
+// @Description In atomic mode, the default, nothing is stored unless every operation succeeds,
+// @Description in best_effort mode the failed operations are skipped and the others stored.
+// @Description Update and delete operations take the version of the student as returned in its ETag.
+// @ID          batch-students
+// @Tags  	    students
+// @Accept      json
+// @Produce     json
+// @Param       request body studentBatchRequest true "Operations"
+// @Success     200 {object} batchResponse[entity.Student]
+// @Failure     400 {object} problem
+// @Failure     403 {object} problem
+// @Failure     500 {object} problem
+// @Router      /students/batch [post]
+func (r *studentRoutes) batchStudents(ctx *fiber.Ctx) error {
+	var request studentBatchRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		r.l.Error(err, "http - v1 - batchStudents")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid request body")
+	}
+
+	if err := r.v.Struct(request); err != nil {
+		return handleError(ctx, r.l, validationError(err), "http - v1 - batchStudents - validation")
+	}
+
+	ops := make([]entity.BatchOp[entity.Student], len(request.Operations))
+	for i, op := range request.Operations {
+		ops[i] = entity.BatchOp[entity.Student]{
+			Op: op.Op,
+			Item: entity.Student{
+				ID:               op.ID,
+				Name:             op.Name,
+				Email:            op.Email,
+				GroupID:          op.GroupID,
+				DateOfBirth:      op.DateOfBirth,
+				Phone:            op.Phone,
+				EnrollmentNumber: op.EnrollmentNumber,
+				Guardians:        newGuardians(op.Guardians),
+				Version:          op.Version,
+			},
+		}
+	}
+
+	report, err := r.s.BatchStudents(ctx.UserContext(), ops, request.Mode != _batchBestEffort)
+	if err != nil {
+		return handleError(ctx, r.l, err, "http - v1 - batchStudents - r.s.BatchStudents")
+	}
+
+	changed := make([]*entity.Student, 0, len(report.Results))
+	for _, res := range report.Results {
+		if res.Item != nil {
+			changed = append(changed, res.Item)
+		}
+	}
+
+	if err = r.mask(ctx.UserContext(), entity.PIIList, changed...); err != nil {
+		return handleError(ctx, r.l, err, "http - v1 - batchStudents - r.mask")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(newBatchResponse(ctx, report))
+}
+
+type exportStudentsQuery struct {
+	Format         string `query:"format"          validate:"omitempty,oneof=csv xlsx jsonl"`
+	GroupID        int    `query:"group_id"        validate:"omitempty,min=1"`
+	Email          string `query:"email"           validate:"omitempty,email"`
+	EmailDomain    string `query:"email_domain"    validate:"omitempty,fqdn"`
+	IncludeDeleted bool   `query:"include_deleted"`
+}
+
+// _studentExportHeader names the columns of tabular student exports.
+var _studentExportHeader = []string{ //nolint:gochecknoglobals // column list
+	"id", "name", "email", "phone", "group_id", "enrollment_number", "status", "created_at", "updated_at", "deleted_at",
+}
+
+// @Summary     Export students
+// @Description Stream the students matching the filters as CSV, XLSX or JSON Lines,
+// @Description the format is negotiated from the Accept header unless given explicitly.
+// @Description Emails and phone numbers are hidden or masked as the privacy policy says for exports.
+// @ID          export-students
+// @Tags  	    students
+// @Produce     text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/x-ndjson
+// @Param       format          query string false "File format" Enums(csv, xlsx, jsonl)
+// @Param       group_id        query int    false "Only students of this group"
+// @Param       email           query string false "Only the student with this email, ignoring case, if the caller sees its email in full"
+// @Param       email_domain    query string false "Only students with an email in this domain"
+// @Param       include_deleted query bool   false "Also export soft-deleted students, admins of every group only"
+// @Success     200 {file} file
+// @Failure     400 {object} problem
+// @Failure     403 {object} problem
+// @Failure     406 {object} problem
+// @Failure     500 {object} problem
+// @Router      /students/export [get]
+func (r *studentRoutes) exportStudents(ctx *fiber.Ctx) error {
+	var request exportStudentsQuery
+	if err := ctx.QueryParser(&request); err != nil {
+		r.l.Error(err, "http - v1 - exportStudents")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid query parameters")
+	}
+
+	if err := r.v.Struct(request); err != nil {
+		return handleError(ctx, r.l, validationError(err), "http - v1 - exportStudents - validation")
+	}
+
+	format := exportFormat(ctx, request.Format)
+	if format == "" {
+		return errorResponse(ctx, http.StatusNotAcceptable, "students can be exported as text/csv, XLSX or application/x-ndjson")
+	}
+
+	filter := entity.StudentFilter{
+		GroupID:        optionalID(request.GroupID),
+		Email:          request.Email,
+		EmailDomain:    request.EmailDomain,
+		IncludeDeleted: request.IncludeDeleted,
+	}
+
+	// The export runs after the handler has returned
+	uctx := ctx.UserContext()
+
+	mask, err := r.p.StudentMask(uctx, entity.PIIExport)
+	if err != nil {
+		return handleError(ctx, r.l, err, "http - v1 - exportStudents - r.p.StudentMask")
+	}
+
+	revealed, err := r.emailRevealed(uctx, entity.PIIExport)
+	if err != nil {
+		return handleError(ctx, r.l, err, "http - v1 - exportStudents - r.emailRevealed")
+	}
+
+	// Authorized before the stream starts, a denied export still gets its status
+	export, err := r.s.ExportStudents(uctx, filter)
+	if err != nil {
+		return handleError(ctx, r.l, err, "http - v1 - exportStudents - r.s.ExportStudents")
+	}
+
+	return streamExport(ctx, format, "students", func(open func([]string) (exportWriter, error)) error {
+		w, err := open(_studentExportHeader)
+		if err != nil {
+			return err
+		}
+
+		return export(func(s entity.Student) error {
+			if filter.Email != "" {
+				if ok, err := revealed(s); err != nil || !ok {
+					return err
+				}
+			}
+
+			if err := mask(&s); err != nil {
+				return err
+			}
+
+			return w.Write(s, []string{
+				strconv.Itoa(s.ID),
+				s.Name,
+				s.Email,
+				s.Phone,
+				strconv.Itoa(s.GroupID),
+				s.EnrollmentNumber,
+				s.Status,
+				formatTime(&s.CreatedAt),
+				formatTime(&s.UpdatedAt),
+				formatTime(s.DeletedAt),
+			})
+		})
+	}, func(err error) {
+		r.l.Error(err, "http - v1 - exportStudents - export")
+	})
+}
@@ -0,0 +1,154 @@
+package v1_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	v1 "github.com/evrone/go-clean-template/internal/controller/http/v1"
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/usecase"
+	"github.com/evrone/go-clean-template/pkg/logger"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// problem is the part of the problem details checked by the tests.
+type problem struct {
+	Status        int                 `json:"status"`
+	Code          string              `json:"code"`
+	Instance      string              `json:"instance"`
+	InvalidParams []entity.FieldError `json:"invalid_params"`
+}
+
+// studentStub implements the student use case calls made by a test, any other call panics.
+type studentStub struct {
+	usecase.Student
+	create func(entity.Student) (entity.Student, error)
+	get    func(id int) (entity.Student, error)
+	update func(entity.Student) (entity.Student, error)
+	delete func(id, version int) error
+	export entity.Export[entity.Student]
+	denied error
+}
+
+func (s studentStub) CreateStudent(_ context.Context, student entity.Student) (entity.Student, error) {
+	return s.create(student)
+}
+
+func (s studentStub) GetStudentByID(_ context.Context, id int) (entity.Student, error) {
+	return s.get(id)
+}
+
+func (s studentStub) UpdateStudent(_ context.Context, student entity.Student) (entity.Student, error) {
+	return s.update(student)
+}
+
+func (s studentStub) DeleteStudent(_ context.Context, id, version int) error {
+	return s.delete(id, version)
+}
+
+func (s studentStub) ExportStudents(context.Context, entity.StudentFilter) (entity.Export[entity.Student], error) {
+	if s.denied != nil {
+		return nil, s.denied
+	}
+
+	return s.export, nil
+}
+
+// privacyStub reveals the personal data of every student.
+type privacyStub struct {
+	usecase.Privacy
+}
+
+func (privacyStub) StudentView(context.Context, string) (func(entity.Student) (string, error), error) {
+	return func(entity.Student) (string, error) { return entity.PIIFull, nil }, nil
+}
+
+func (privacyStub) StudentMask(context.Context, string) (func(*entity.Student) error, error) {
+	return func(*entity.Student) error { return nil }, nil
+}
+
+func studentApp(s usecase.Student) *fiber.App {
+	app := fiber.New()
+	v1.NewStudentRoutes(app, s, privacyStub{}, logger.New("error"))
+
+	return app
+}
+
+// send runs the request against the app and decodes the problem details of an error response.
+func send(t *testing.T, app *fiber.App, req *http.Request) (*http.Response, problem) {
+	t.Helper()
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { resp.Body.Close() })
+
+	var p problem
+	if resp.StatusCode >= http.StatusBadRequest {
+		require.Equal(t, "application/problem+json", resp.Header.Get(fiber.HeaderContentType))
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&p))
+	}
+
+	return resp, p
+}
+
+func TestCreateStudentDuplicateEmail(t *testing.T) {
+	t.Parallel()
+
+	app := studentApp(studentStub{
+		create: func(s entity.Student) (entity.Student, error) {
+			if strings.EqualFold(s.Email, "taken@example.com") {
+				return entity.Student{}, entity.NewError(entity.ErrConflict, "student_already_exists", "student already exists",
+					entity.FieldError{Field: "email", Message: "is already taken"})
+			}
+
+			s.ID, s.Version = 1, 1
+
+			return s, nil
+		},
+	})
+
+	tests := []struct {
+		name   string
+		email  string
+		status int
+		code   string
+		fields []entity.FieldError
+	}{
+		{
+			name:   "new email",
+			email:  "new@example.com",
+			status: http.StatusCreated,
+		},
+		{
+			name:   "taken email in another case",
+			email:  "Taken@Example.com",
+			status: http.StatusConflict,
+			code:   "student_already_exists",
+			fields: []entity.FieldError{{Field: "email", Message: "is already taken"}},
+		},
+	}
+
+	for _, tc := range tests {
+		localTc := tc
+
+		t.Run(localTc.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodPost, "/students",
+				strings.NewReader(`{"name":"Ann","email":"`+localTc.email+`","group_id":1}`))
+			req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+			resp, p := send(t, app, req)
+
+			require.Equal(t, localTc.status, resp.StatusCode)
+			require.Equal(t, localTc.code, p.Code)
+			require.Equal(t, localTc.fields, p.InvalidParams)
+		})
+	}
+}
@@ -17,7 +17,7 @@ type translationRoutes struct {
 }
 
 func NewTranslationRoutes(apiV1Group fiber.Router, t usecase.Translation, l logger.Interface) {
-	r := &translationRoutes{t, l, validator.New(validator.WithRequiredStructEnabled())}
+	r := &translationRoutes{t, l, newValidator()}
 
 	translationGroup := apiV1Group.Group("/translation")
 	{
@@ -37,7 +37,7 @@ type historyResponse struct {
 // @Accept      json
 // @Produce     json
 // @Success     200 {object} historyResponse
-// @Failure     500 {object} response
+// @Failure     500 {object} problem
 // @Router      /translation/history [get]
 func (r *translationRoutes) history(ctx *fiber.Ctx) error {
 	translations, err := r.t.History(ctx.UserContext())
@@ -64,8 +64,8 @@ type doTranslateRequest struct {
 // @Produce     json
 // @Param       request body doTranslateRequest true "Set up translation"
 // @Success     200 {object} entity.Translation
-// @Failure     400 {object} response
-// @Failure     500 {object} response
+// @Failure     400 {object} problem
+// @Failure     500 {object} problem
 // @Router      /translation/do-translate [post]
 func (r *translationRoutes) doTranslate(ctx *fiber.Ctx) error {
 	var request doTranslateRequest
@@ -77,9 +77,7 @@ func (r *translationRoutes) doTranslate(ctx *fiber.Ctx) error {
 	}
 
 	if err := r.v.Struct(request); err != nil {
-		r.l.Error(err, "http - v1 - doTranslate")
-
-		return errorResponse(ctx, http.StatusBadRequest, "invalid request body")
+		return handleError(ctx, r.l, validationError(err), "http - v1 - doTranslate")
 	}
 
 	translation, err := r.t.Translate(
@@ -0,0 +1,113 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/go-playground/validator/v10"
+)
+
+// newValidator returns a validator that reports fields by their json or query names.
+// Dates are checked like times, so lt requires a date in the past.
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+
+	v.RegisterCustomTypeFunc(func(f reflect.Value) any {
+		if d, ok := f.Interface().(entity.Date); ok {
+			return d.Time
+		}
+
+		return nil
+	}, entity.Date{})
+
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		for _, tag := range []string{"json", "query", "form"} {
+			name, _, _ := strings.Cut(f.Tag.Get(tag), ",")
+			if name == "-" {
+				return ""
+			}
+
+			if name != "" {
+				return name
+			}
+		}
+
+		return f.Name
+	})
+
+	return v
+}
+
+// validationError converts the errors of validator.Struct into a domain validation error
+// listing every rejected field with the failed rule and its parameter.
+func validationError(err error) error {
+	fields := fieldErrors(err)
+	if fields == nil {
+		return err
+	}
+
+	return &entity.Error{
+		Kind:    entity.ErrValidation,
+		Code:    "validation_failed",
+		Message: "validation failed",
+		Fields:  fields,
+		Err:     err,
+	}
+}
+
+// fieldErrors lists the fields rejected by validator.Struct, nil for other errors.
+// Nested fields are named by their path, e.g. operations[2].email.
+func fieldErrors(err error) []entity.FieldError {
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) {
+		return nil
+	}
+
+	fields := make([]entity.FieldError, 0, len(errs))
+	for _, fe := range errs {
+		// Drop the name of the validated struct
+		_, field, _ := strings.Cut(fe.Namespace(), ".")
+
+		fields = append(fields, entity.FieldError{
+			Field:   field,
+			Tag:     fe.Tag(),
+			Param:   fe.Param(),
+			Message: fieldMessage(fe),
+		})
+	}
+
+	return fields
+}
+
+// fieldMessage describes a failed validation rule in plain words.
+func fieldMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required", "required_if", "required_unless":
+		return "is required"
+	case "required_without":
+		return "is required unless " + strings.ToLower(fe.Param()) + " is given"
+	case "email":
+		return "must be a valid email address"
+	case "e164":
+		return "must be a phone number in international format, e.g. +4915112345678"
+	case "fqdn":
+		return "must be a valid domain name"
+	case "min":
+		return "must be at least " + fe.Param()
+	case "max":
+		return "must be at most " + fe.Param()
+	case "lt":
+		if fe.Param() == "" {
+			return "must be in the past"
+		}
+
+		return "must be less than " + fe.Param()
+	case "oneof":
+		return "must be one of: " + strings.ReplaceAll(fe.Param(), " ", ", ")
+	default:
+		return fmt.Sprintf("failed on the %q rule", fe.Tag())
+	}
+}
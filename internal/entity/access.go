@@ -0,0 +1,40 @@
+package entity
+
+import "time"
+
+// Roles, each one includes the permissions of the roles before it.
+const (
+	// RoleRead views students and groups.
+	RoleRead = "read"
+	// RoleWrite also manages students and creates and edits groups.
+	RoleWrite = "write"
+	// RoleAdmin also deletes, moves and merges groups and manages grants.
+	RoleAdmin = "admin"
+)
+
+// _roleLevels orders the roles by their permissions.
+var _roleLevels = map[string]int{RoleRead: 1, RoleWrite: 2, RoleAdmin: 3} //nolint:gochecknoglobals // lookup table
+
+// RoleIncludes reports whether role has the permissions of required.
+func RoleIncludes(role, required string) bool {
+	return _roleLevels[role] > 0 && _roleLevels[role] >= _roleLevels[required]
+}
+
+// MaxRole returns the role with more permissions.
+func MaxRole(a, b string) string {
+	if _roleLevels[b] > _roleLevels[a] {
+		return b
+	}
+
+	return a
+}
+
+// Grant gives a subject a role on a group and all its descendants,
+// or on every group when GroupID is nil.
+type Grant struct {
+	ID        int       `json:"id"         example:"1"`
+	Subject   string    `json:"subject"    example:"secretary-physics"`
+	GroupID   *int      `json:"group_id"   example:"3"`
+	Role      string    `json:"role"       example:"write"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,156 @@
+package entity
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"time"
+)
+
+// Audited actions.
+const (
+	AuditCreate  = "create"
+	AuditUpdate  = "update"
+	AuditDelete  = "delete"
+	AuditRestore = "restore"
+	AuditMove    = "move"
+	AuditMerge   = "merge"
+)
+
+// Audited entities.
+const (
+	AuditStudent = "student"
+	AuditGroup   = "group"
+)
+
+// AnonymousActor is recorded for changes made without an authenticated actor.
+const AnonymousActor = "anonymous"
+
+// AuditChange holds the value of a field before and after a change, nil when the field was absent.
+type AuditChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+// AuditEntry records who changed which student or group, when and in which request.
+// Diff holds the fields that changed, keyed by their JSON names.
+type AuditEntry struct {
+	ID        int                    `json:"id"         example:"1"`
+	Actor     string                 `json:"actor"      example:"admin"`
+	Action    string                 `json:"action"     example:"update"`
+	Entity    string                 `json:"entity"     example:"student"`
+	EntityID  int                    `json:"entity_id"  example:"42"`
+	Diff      map[string]AuditChange `json:"diff"`
+	RequestID string                 `json:"request_id" example:"4f2a6c1e-8d3b-4b7a-9e0f-1c2d3e4f5a6b"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+// AuditFilter narrows down the audit log, zero fields match every entry.
+type AuditFilter struct {
+	Entity    string
+	EntityID  *int
+	Actor     string
+	Action    string
+	RequestID string
+	Since     *time.Time
+	Until     *time.Time
+}
+
+// _auditIgnored are fields that are derived from others or change along with every entry.
+var _auditIgnored = []string{"created_at", "updated_at", "path", "subGroups", "student_count"} //nolint:gochecknoglobals // field list
+
+// _auditPersonal are the fields of students holding personal data. The audit log records that they changed,
+// but their values only as the PIIMasked view shows them, values without a masked form are left out.
+var _auditPersonal = []string{"email", "phone", "date_of_birth", "guardians"} //nolint:gochecknoglobals // field list
+
+// NewAuditEntry records an action on an entity. before and after are the entity before and after the change,
+// or nil when it did not exist yet or any longer, only the fields that differ end up in the diff.
+// Personal data of students is masked. The actor and request ID are taken from the context.
+func NewAuditEntry(ctx context.Context, action, entity string, id int, before, after any) AuditEntry {
+	changes := diff(auditFields(before), auditFields(after))
+
+	maskedBefore, maskedAfter := auditFields(auditMasked(before)), auditFields(auditMasked(after))
+	for _, name := range _auditPersonal {
+		if _, ok := changes[name]; ok {
+			changes[name] = AuditChange{Before: maskedBefore[name], After: maskedAfter[name]}
+		}
+	}
+
+	return AuditEntry{
+		Actor:     ActorFrom(ctx),
+		Action:    action,
+		Entity:    entity,
+		EntityID:  id,
+		Diff:      changes,
+		RequestID: RequestIDFrom(ctx),
+	}
+}
+
+// auditMasked returns a student with its personal data masked for the audit log, other entities unchanged.
+func auditMasked(v any) any {
+	s, ok := v.(Student)
+	if !ok {
+		return v
+	}
+
+	MaskStudent(&s, PIIMasked)
+	s.DateOfBirth = nil
+
+	return s
+}
+
+// auditFields returns the JSON fields of an entity.
+func auditFields(v any) map[string]any {
+	b, _ := json.Marshal(v) //nolint:errchkjson // plain structs and maps, cannot fail
+
+	var fields map[string]any
+	_ = json.Unmarshal(b, &fields) //nolint:errcheck // null for nil entities
+
+	for _, name := range _auditIgnored {
+		delete(fields, name)
+	}
+
+	return fields
+}
+
+// diff returns the fields whose values differ.
+func diff(before, after map[string]any) map[string]AuditChange {
+	changes := make(map[string]AuditChange)
+
+	for name, value := range before {
+		if !reflect.DeepEqual(value, after[name]) {
+			changes[name] = AuditChange{Before: value, After: after[name]}
+		}
+	}
+
+	for name, value := range after {
+		if _, ok := before[name]; !ok {
+			changes[name] = AuditChange{After: value}
+		}
+	}
+
+	return changes
+}
+
+type requestIDKey struct{}
+
+// ActorFrom returns the subject of the principal carried by the context, AnonymousActor if there is none.
+func ActorFrom(ctx context.Context) string {
+	if p, ok := PrincipalFrom(ctx); ok && p.Subject != "" {
+		return p.Subject
+	}
+
+	return AnonymousActor
+}
+
+// WithRequestID returns a context carrying the ID of the request it serves.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFrom returns the request ID carried by the context, empty if there is none.
+func RequestIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+
+	return id
+}
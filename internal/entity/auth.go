@@ -0,0 +1,30 @@
+package entity
+
+import "context"
+
+// Authentication methods.
+const (
+	AuthJWT    = "jwt"
+	AuthAPIKey = "api_key"
+)
+
+// Principal is the authenticated caller of a request. Subject is the subject of the JWT
+// or the name of the API key, Method tells which of them authenticated the caller.
+type Principal struct {
+	Subject string
+	Method  string
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a context carrying the authenticated caller.
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFrom returns the authenticated caller carried by the context.
+func PrincipalFrom(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+
+	return p, ok
+}
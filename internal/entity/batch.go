@@ -0,0 +1,82 @@
+package entity
+
+// Batch operations.
+const (
+	BatchCreate = "create"
+	BatchUpdate = "update"
+	BatchDelete = "delete"
+)
+
+// Batch operation statuses.
+const (
+	// BatchApplied operations have been committed.
+	BatchApplied = "applied"
+	// BatchRolledBack operations succeeded but were undone because another operation of an atomic batch failed.
+	BatchRolledBack = "rolled_back"
+	// BatchFailed operations were rejected, see their error.
+	BatchFailed = "failed"
+)
+
+// BatchOp is one operation of a batch. Create and update operations carry the whole item,
+// delete operations only its ID and Version.
+type BatchOp[T any] struct {
+	Op   string
+	Item T
+}
+
+// BatchResult is the outcome of one operation of a batch. Item is the stored item of applied
+// create and update operations.
+type BatchResult[T any] struct {
+	Op     string
+	Status string
+	Item   *T
+	Err    error
+}
+
+// BatchReport summarizes a batch. Atomic batches are committed all at once or not at all,
+// other batches skip the failed operations.
+type BatchReport[T any] struct {
+	Atomic  bool
+	Total   int
+	Applied int
+	Failed  int
+	Results []BatchResult[T]
+}
+
+// NewBatchReport reports the outcome of the operations given the item and error
+// returned for each of them.
+func NewBatchReport[T any](ops []BatchOp[T], items []T, errs []error, atomic bool) BatchReport[T] {
+	report := BatchReport[T]{
+		Atomic:  atomic,
+		Total:   len(ops),
+		Results: make([]BatchResult[T], len(ops)),
+	}
+
+	for _, err := range errs {
+		if err != nil {
+			report.Failed++
+		}
+	}
+
+	for i, op := range ops {
+		result := BatchResult[T]{Op: op.Op, Status: BatchApplied}
+
+		switch {
+		case errs[i] != nil:
+			result.Status = BatchFailed
+			result.Err = errs[i]
+		case atomic && report.Failed > 0:
+			result.Status = BatchRolledBack
+		case op.Op != BatchDelete:
+			result.Item = &items[i]
+		}
+
+		if result.Status == BatchApplied {
+			report.Applied++
+		}
+
+		report.Results[i] = result
+	}
+
+	return report
+}
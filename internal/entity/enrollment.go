@@ -0,0 +1,32 @@
+package entity
+
+import "time"
+
+// Reasons an enrollment starts or ends.
+const (
+	// EnrollmentEnrolled starts the enrollment of a new student.
+	EnrollmentEnrolled = "enrolled"
+	// EnrollmentTransferred starts and ends enrollments when a student changes groups.
+	EnrollmentTransferred = "transferred"
+	// EnrollmentRegrouped starts and ends enrollments when the students of a group
+	// move to another group as the group is merged or deleted.
+	EnrollmentRegrouped = "regrouped"
+	// EnrollmentWithdrawn ends the enrollment of a deleted student.
+	EnrollmentWithdrawn = "withdrawn"
+	// EnrollmentGroupDeleted ends the enrollments of the students deleted along with their group.
+	EnrollmentGroupDeleted = "group_deleted"
+	// EnrollmentRestored starts the enrollment of a restored student.
+	EnrollmentRestored = "restored"
+)
+
+// Enrollment records the membership of a student in a group from StartedAt until EndedAt,
+// an enrollment without EndedAt is the current one. A student has at most one current enrollment.
+type Enrollment struct {
+	ID        int        `json:"id"                   example:"1"`
+	StudentID int        `json:"student_id"           example:"42"`
+	GroupID   int        `json:"group_id"             example:"7"`
+	StartedAt time.Time  `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at,omitempty"`
+	Reason    string     `json:"reason"               example:"enrolled"`
+	EndReason string     `json:"end_reason,omitempty" example:"transferred"`
+}
@@ -0,0 +1,149 @@
+package entity
+
+import "errors"
+
+// Error kinds. Every domain error belongs to exactly one kind,
+// which decides how it is reported to API clients.
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrValidation   = errors.New("validation failed")
+	ErrForeignKey   = errors.New("referenced entity does not exist")
+	ErrPrecondition = errors.New("precondition failed")
+	ErrForbidden    = errors.New("forbidden")
+)
+
+// Domain errors returned by use cases and repositories.
+var (
+	ErrGroupCycle = &Error{
+		Kind:    ErrConflict,
+		Code:    "group_cycle",
+		Message: "group cannot be moved under itself or its subgroup",
+		Fields:  []FieldError{{Field: "parent_id", Message: "must not be the group itself or one of its subgroups"}},
+	}
+	ErrParentNotFound = &Error{
+		Kind:    ErrForeignKey,
+		Code:    "parent_not_found",
+		Message: "parent group not found",
+		Fields:  []FieldError{{Field: "parent_id", Message: "group does not exist"}},
+	}
+	ErrGroupNotFound = &Error{
+		Kind:    ErrForeignKey,
+		Code:    "group_not_found",
+		Message: "group not found",
+		Fields:  []FieldError{{Field: "group_id", Message: "group does not exist"}},
+	}
+	ErrTargetNotFound = &Error{
+		Kind:    ErrNotFound,
+		Code:    "target_not_found",
+		Message: "target group not found",
+		Fields:  []FieldError{{Field: "target", Message: "group does not exist"}},
+	}
+	ErrMergeCycle = &Error{
+		Kind:    ErrConflict,
+		Code:    "merge_cycle",
+		Message: "group cannot be merged into itself or its subgroup",
+		Fields:  []FieldError{{Field: "target", Message: "must not be the group itself or one of its subgroups"}},
+	}
+	ErrGroupHasSubgroups = &Error{
+		Kind:    ErrConflict,
+		Code:    "group_has_subgroups",
+		Message: "cannot delete group with subgroups, delete them or reassign them to another group first",
+	}
+	ErrGroupHasStudents = &Error{
+		Kind:    ErrConflict,
+		Code:    "group_has_students",
+		Message: "cannot delete group with students, delete them or reassign them to another group first",
+	}
+	ErrVersionMismatch = &Error{
+		Kind:    ErrPrecondition,
+		Code:    "version_mismatch",
+		Message: "the resource was modified by someone else, reload it and try again",
+	}
+	ErrUnknownOperation = &Error{
+		Kind:    ErrValidation,
+		Code:    "unknown_operation",
+		Message: "unknown batch operation",
+		Fields:  []FieldError{{Field: "op", Message: "must be one of: create, update, delete"}},
+	}
+	ErrAccessDenied = &Error{
+		Kind:    ErrForbidden,
+		Code:    "access_denied",
+		Message: "you have no permission to do this in the group",
+	}
+	ErrIllegalTransition = &Error{
+		Kind:    ErrConflict,
+		Code:    "illegal_transition",
+		Message: "the student cannot change to this status, an admin may override the check",
+		Fields:  []FieldError{{Field: "status", Message: "is not allowed after the current status"}},
+	}
+	ErrInvalidCursor = &Error{
+		Kind:    ErrValidation,
+		Code:    "invalid_cursor",
+		Message: "invalid cursor",
+		Fields:  []FieldError{{Field: "cursor", Message: "must be a next_cursor value returned for the same sort order"}},
+	}
+)
+
+// FieldError describes why a single field was rejected.
+type FieldError struct {
+	Field   string `json:"field"           example:"email"`
+	Tag     string `json:"tag,omitempty"   example:"email"`
+	Param   string `json:"param,omitempty" example:""`
+	Message string `json:"message"         example:"must be a valid email address"`
+}
+
+// Error is a domain error with a machine-readable code and optional field details.
+type Error struct {
+	Kind    error
+	Code    string
+	Message string
+	Fields  []FieldError
+	Err     error
+}
+
+// NewError returns a domain error of the given kind.
+func NewError(kind error, code, message string, fields ...FieldError) *Error {
+	return &Error{
+		Kind:    kind,
+		Code:    code,
+		Message: message,
+		Fields:  fields,
+	}
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+
+	return e.Message
+}
+
+// Unwrap exposes both the kind and the cause to errors.Is and errors.As.
+func (e *Error) Unwrap() []error {
+	if e.Err != nil {
+		return []error{e.Kind, e.Err}
+	}
+
+	return []error{e.Kind}
+}
+
+// Is reports whether target is a domain error with the same code,
+// so errors created by Wrap still match the predeclared ones.
+func (e *Error) Is(target error) bool {
+	var t *Error
+	if !errors.As(target, &t) {
+		return false
+	}
+
+	return t.Code == e.Code
+}
+
+// Wrap returns a copy of the error carrying err as its cause.
+func (e *Error) Wrap(err error) *Error {
+	c := *e
+	c.Err = err
+
+	return &c
+}
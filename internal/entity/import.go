@@ -0,0 +1,91 @@
+package entity
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Import row statuses.
+const (
+	// ImportCreated rows have been stored.
+	ImportCreated = "created"
+	// ImportValid rows passed every check but were not stored, because of a dry run or other failing rows.
+	ImportValid = "valid"
+	// ImportFailed rows were rejected, see their errors.
+	ImportFailed = "failed"
+)
+
+// StudentImport is one row of a student roster. Group refers to the group of the student
+// by ID or by name, Errors holds the problems found while reading the row.
+type StudentImport struct {
+	Row     int
+	Student Student
+	Group   string
+	Errors  []FieldError
+}
+
+// ImportRow is the outcome of importing one row of a roster.
+type ImportRow struct {
+	Row       int          `json:"row"                  example:"2"`
+	Status    string       `json:"status"               example:"created"`
+	StudentID int          `json:"student_id,omitempty" example:"42"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+// ImportReport summarizes an import. Rows are stored all at once or not at all.
+type ImportReport struct {
+	DryRun  bool        `json:"dry_run"`
+	Total   int         `json:"total"   example:"120"`
+	Created int         `json:"created" example:"118"`
+	Failed  int         `json:"failed"  example:"2"`
+	Rows    []ImportRow `json:"rows"`
+}
+
+// ImportGroupRefs returns the group IDs and names that the rows of a roster refer to.
+func ImportGroupRefs(rows []StudentImport) (ids []int, names []string) {
+	ids = make([]int, 0, len(rows))
+	names = make([]string, 0, len(rows))
+
+	for _, row := range rows {
+		if id, err := strconv.Atoi(row.Group); err == nil {
+			ids = append(ids, id)
+		} else if row.Group != "" {
+			names = append(names, row.Group)
+		}
+	}
+
+	return ids, names
+}
+
+// ResolveImportGroups sets the group of every row from its reference to one of the groups by ID or,
+// ignoring case, by name. Rows referring to a missing or ambiguous group get an error instead.
+func ResolveImportGroups(rows []StudentImport, groups []Group) {
+	byID := make(map[int]bool, len(groups))
+	byName := make(map[string][]int, len(groups))
+
+	for _, g := range groups {
+		byID[g.ID] = true
+		byName[strings.ToLower(g.Name)] = append(byName[strings.ToLower(g.Name)], g.ID)
+	}
+
+	for i := range rows {
+		row := &rows[i]
+
+		id, err := strconv.Atoi(row.Group)
+
+		switch {
+		case row.Group == "":
+			row.Errors = append(row.Errors, FieldError{Field: "group", Tag: "required", Message: "is required"})
+		case err == nil && byID[id]:
+			row.Student.GroupID = id
+		case err == nil:
+			row.Errors = append(row.Errors, FieldError{Field: "group", Message: "group does not exist"})
+		case len(byName[strings.ToLower(row.Group)]) == 1:
+			row.Student.GroupID = byName[strings.ToLower(row.Group)][0]
+		case len(byName[strings.ToLower(row.Group)]) > 1:
+			row.Errors = append(row.Errors, FieldError{Field: "group", Message: "several groups have this name, use the group ID"})
+		default:
+			row.Errors = append(row.Errors, FieldError{Field: "group", Message: "group does not exist"})
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package entity
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+)
+
+// Sort fields accepted by list endpoints. A leading "-" in the request reverses the order.
+const (
+	SortByID      = "id"
+	SortByName    = "name"
+	SortByGroupID = "group_id"
+)
+
+// UnlimitedDepth loads group subtrees down to the leaves.
+const UnlimitedDepth = -1
+
+// DefaultPageLimit is used when a list request does not specify a limit.
+const DefaultPageLimit = 50
+
+// Cursor points at the last row of a page. Rows are ordered by (sort key, id),
+// so the sort key value together with the id identifies a unique position.
+type Cursor struct {
+	Sort  string `json:"s"`
+	Desc  bool   `json:"d,omitempty"`
+	Value string `json:"v"`
+	ID    int    `json:"id"`
+}
+
+// Encode returns the opaque representation of the cursor handed out to clients.
+func (c Cursor) Encode() string {
+	b, _ := json.Marshal(c) //nolint:errchkjson // plain struct, cannot fail
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeCursor parses a cursor previously produced by Cursor.Encode.
+func DecodeCursor(s string) (Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor.Wrap(err)
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(b, &c); err != nil {
+		return Cursor{}, ErrInvalidCursor.Wrap(err)
+	}
+
+	return c, nil
+}
+
+// PageRequest describes which slice of a list is requested.
+type PageRequest struct {
+	Limit int
+	Sort  string
+	Desc  bool
+	After *Cursor
+}
+
+// Page is one slice of a list together with the position of the next slice.
+type Page[T any] struct {
+	Items      []T
+	NextCursor *Cursor
+	Total      int
+}
+
+// Export streams the items of a list to yield when run, stopping at the first error.
+// It is handed out once the caller has been authorized, so running it fails only on I/O errors.
+type Export[T any] func(yield func(T) error) error
+
+// StudentFilter narrows down the list of students. Soft-deleted students are listed only with IncludeDeleted.
+// With Recursive, GroupID also matches the students of the active subgroups of the group at any depth.
+// A non-nil Scope limits the list to the students of the given groups and their active subgroups.
+// With AsOf, GroupID matches the students enrolled in the group at that time instead, deleted since or not,
+// the subgroups of a Recursive filter are the current ones.
+type StudentFilter struct {
+	GroupID        *int
+	Recursive      bool
+	AsOf           *time.Time
+	Email          string
+	EmailDomain    string
+	IncludeDeleted bool
+	Scope          []int
+}
+
+// GroupFilter narrows down the list of groups. Without ParentID only root groups are listed,
+// or the groups of a non-nil Scope in their place.
+// MaxDepth limits how many levels of subgroups are loaded under each listed group.
+// Soft-deleted groups are listed only with IncludeDeleted, subgroups are always active ones.
+// Exports hold the whole active tree, or the subtrees of a non-nil Scope, and ignore the other fields.
+type GroupFilter struct {
+	ParentID       *int
+	MaxDepth       int
+	IncludeDeleted bool
+	Scope          []int
+}
@@ -0,0 +1,108 @@
+package entity
+
+import "strings"
+
+// Views of the personal data of students, from the least to the most revealing.
+const (
+	// PIIHidden leaves personal data out.
+	PIIHidden = "hidden"
+	// PIIMasked shows just enough of personal data to recognize it, e.g. j***@example.com.
+	PIIMasked = "masked"
+	// PIIFull shows personal data as it is.
+	PIIFull = "full"
+)
+
+// Kinds of endpoints returning students, each with its own view of personal data.
+const (
+	// PIIDetail endpoints return a single student, e.g. GET /students/:id.
+	PIIDetail = "detail"
+	// PIIList endpoints return many students, e.g. lists, searches and batch reports.
+	PIIList = "list"
+	// PIIExport endpoints stream students to files.
+	PIIExport = "export"
+)
+
+// PIIPolicy decides how much of the personal data of students, their emails and phone numbers along with those
+// of their guardians, callers see. Each kind of endpoint maps the role of the caller on the group of a student
+// to a view, roles that are not listed get PIIHidden. ContactRole is the role needed to read the contact details
+// of a student in full.
+type PIIPolicy struct {
+	Detail      map[string]string
+	List        map[string]string
+	Export      map[string]string
+	ContactRole string
+}
+
+// View returns the view of personal data for a caller with the role on an endpoint of the given kind.
+func (p PIIPolicy) View(endpoint, role string) string {
+	var views map[string]string
+
+	switch endpoint {
+	case PIIDetail:
+		views = p.Detail
+	case PIIList:
+		views = p.List
+	case PIIExport:
+		views = p.Export
+	}
+
+	if view, ok := views[role]; ok {
+		return view
+	}
+
+	return PIIHidden
+}
+
+// StudentContact holds the contact details of a student, which other responses hide or mask.
+type StudentContact struct {
+	ID        int        `json:"id"        example:"1"`
+	Email     string     `json:"email"     example:"john@example.com"`
+	Phone     string     `json:"phone"     example:"+4915112345678"`
+	Guardians []Guardian `json:"guardians"`
+}
+
+// MaskStudent applies a view to the personal data of a student.
+func MaskStudent(s *Student, view string) {
+	if view == PIIFull {
+		return
+	}
+
+	s.Email = maskEmail(s.Email, view)
+	s.Phone = maskPhone(s.Phone, view)
+
+	if s.Guardians == nil {
+		return
+	}
+
+	// The guardians may be shared with the caller
+	guardians := make([]Guardian, len(s.Guardians))
+	for i, g := range s.Guardians {
+		g.Email = maskEmail(g.Email, view)
+		g.Phone = maskPhone(g.Phone, view)
+		guardians[i] = g
+	}
+
+	s.Guardians = guardians
+}
+
+// maskEmail keeps the first letter and the domain of a masked email.
+func maskEmail(email, view string) string {
+	local, domain, ok := strings.Cut(email, "@")
+	if view != PIIMasked || !ok || local == "" {
+		return ""
+	}
+
+	return local[:1] + "***@" + domain
+}
+
+// _phoneVisible is the number of characters kept at either end of a masked phone number.
+const _phoneVisible = 3
+
+// maskPhone keeps the first and the last characters of a masked phone number.
+func maskPhone(phone, view string) string {
+	if view != PIIMasked || len(phone) <= 2*_phoneVisible {
+		return ""
+	}
+
+	return phone[:_phoneVisible] + strings.Repeat("*", len(phone)-2*_phoneVisible) + phone[len(phone)-_phoneVisible:]
+}
@@ -0,0 +1,119 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Student statuses.
+const (
+	// StudentActive students attend their group.
+	StudentActive = "active"
+	// StudentOnLeave students pause their studies and may return.
+	StudentOnLeave = "on_leave"
+	// StudentGraduated students have finished their studies.
+	StudentGraduated = "graduated"
+	// StudentExpelled students have been removed from their studies.
+	StudentExpelled = "expelled"
+)
+
+// _statusTransitions lists the statuses each status may change to, graduated and expelled are final.
+var _statusTransitions = map[string][]string{ //nolint:gochecknoglobals // lookup table
+	StudentActive:    {StudentOnLeave, StudentGraduated, StudentExpelled},
+	StudentOnLeave:   {StudentActive, StudentExpelled},
+	StudentGraduated: {},
+	StudentExpelled:  {},
+}
+
+// CanTransition reports whether a student may change from one status to another without an override.
+func CanTransition(from, to string) bool {
+	return slices.Contains(_statusTransitions[from], to)
+}
+
+// StatusChange asks to change the status of a student. Override allows transitions that are not allowed otherwise,
+// such as readmitting a graduated student. A non-zero Version must match the stored version of the student.
+type StatusChange struct {
+	Status   string
+	Override bool
+	Version  int
+}
+
+// Guardian is a parent or other contact person of a student.
+type Guardian struct {
+	Name         string `json:"name"            example:"Jane Doe"`
+	Relationship string `json:"relationship"    example:"mother"`
+	Phone        string `json:"phone,omitempty" example:"+4915112345678"`
+	Email        string `json:"email,omitempty" example:"jane@example.com"`
+}
+
+// _dateLayout is the format of dates in JSON and in the database.
+const _dateLayout = time.DateOnly
+
+var errNotDate = errors.New("cannot scan into a date")
+
+// Date is a calendar date without time of day, written as 2006-01-02.
+type Date struct {
+	time.Time
+}
+
+// NewDate returns the date of the given day.
+func NewDate(year int, month time.Month, day int) Date {
+	return Date{time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+}
+
+// ParseDate parses a date written as 2006-01-02.
+func ParseDate(s string) (Date, error) {
+	t, err := time.Parse(_dateLayout, s)
+	if err != nil {
+		return Date{}, fmt.Errorf("time.Parse: %w", err)
+	}
+
+	return Date{t}, nil
+}
+
+func (d Date) String() string {
+	return d.Format(_dateLayout)
+}
+
+// MarshalJSON writes the date as a JSON string.
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON reads the date from a JSON string.
+func (d *Date) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("json.Unmarshal: %w", err)
+	}
+
+	parsed, err := ParseDate(s)
+	if err != nil {
+		return err
+	}
+
+	*d = parsed
+
+	return nil
+}
+
+// Scan reads a DATE column.
+func (d *Date) Scan(src any) error {
+	t, ok := src.(time.Time)
+	if !ok {
+		return fmt.Errorf("%w: %T", errNotDate, src)
+	}
+
+	*d = Date{t}
+
+	return nil
+}
+
+// Value writes the date to a DATE column.
+func (d Date) Value() (driver.Value, error) {
+	return d.String(), nil
+}
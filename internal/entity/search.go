@@ -0,0 +1,37 @@
+package entity
+
+// Search modes.
+const (
+	// SearchExact matches whole words.
+	SearchExact = "exact"
+	// SearchPrefix matches words starting with the query words.
+	SearchPrefix = "prefix"
+	// SearchFuzzy matches words similar to the query words, tolerating typos.
+	SearchFuzzy = "fuzzy"
+)
+
+// SearchQuery describes a full-text search. Terms holds the query text in every spelling
+// it should be matched in, e.g. its Cyrillic and Latin transliterations.
+// A non-nil Scope limits the hits to the given groups and their active subgroups.
+type SearchQuery struct {
+	Text           string
+	Terms          []string
+	Mode           string
+	Limit          int
+	IncludeDeleted bool
+	Scope          []int
+}
+
+// StudentHit is a student found by a search, the best matches have the highest rank.
+type StudentHit struct {
+	Student
+	Rank      float64 `json:"rank"      example:"0.6"`
+	Highlight string  `json:"highlight" example:"<mark>John</mark> Doe"`
+}
+
+// GroupHit is a group found by a search, the best matches have the highest rank.
+type GroupHit struct {
+	Group
+	Rank      float64 `json:"rank"      example:"0.6"`
+	Highlight string  `json:"highlight" example:"<mark>Computer</mark> Science"`
+}
@@ -0,0 +1,59 @@
+package entity
+
+// GroupStats describes the size and position of an active group in the hierarchy.
+// Direct counts cover the group itself, total counts its whole active subtree.
+// Depth is 0 for root groups, a leaf group has no subgroups and an empty group no students in its subtree.
+type GroupStats struct {
+	ID              int    `json:"id"                  example:"4"`
+	ParentID        *int   `json:"parent_id,omitempty" example:"1"`
+	Name            string `json:"name"                example:"Faculty of Physics"`
+	Depth           int    `json:"depth"               example:"1"`
+	DirectStudents  int    `json:"direct_students"     example:"12"`
+	TotalStudents   int    `json:"total_students"      example:"340"`
+	DirectSubgroups int    `json:"direct_subgroups"    example:"3"`
+	TotalSubgroups  int    `json:"total_subgroups"     example:"11"`
+	Leaf            bool   `json:"leaf"                example:"false"`
+	Empty           bool   `json:"empty"               example:"false"`
+}
+
+// GroupStatsSummary sums up the statistics of every active group in the hierarchy.
+// MaxDepth is the depth of the deepest group.
+type GroupStatsSummary struct {
+	Groups      int          `json:"groups"       example:"42"`
+	Students    int          `json:"students"     example:"1250"`
+	MaxDepth    int          `json:"max_depth"    example:"3"`
+	LeafGroups  int          `json:"leaf_groups"  example:"30"`
+	EmptyGroups int          `json:"empty_groups" example:"2"`
+	Items       []GroupStats `json:"items"`
+}
+
+// NewGroupStatsSummary sums up the statistics of whole subtrees of active groups, parents before their subgroups.
+// The students are counted at the top groups of the subtrees, whose parents are not among the items.
+func NewGroupStatsSummary(items []GroupStats) GroupStatsSummary {
+	summary := GroupStatsSummary{
+		Groups: len(items),
+		Items:  items,
+	}
+
+	listed := make(map[int]bool, len(items))
+
+	for _, s := range items {
+		listed[s.ID] = true
+
+		if s.ParentID == nil || !listed[*s.ParentID] {
+			summary.Students += s.TotalStudents
+		}
+
+		summary.MaxDepth = max(summary.MaxDepth, s.Depth)
+
+		if s.Leaf {
+			summary.LeafGroups++
+		}
+
+		if s.Empty {
+			summary.EmptyGroups++
+		}
+	}
+
+	return summary
+}
@@ -1,20 +1,125 @@
 // Package entity defines main entities for business logic.
 package entity
 
+import "time"
+
 // Student represents a student in the educational institution.
+// A student with DeletedAt set has been soft-deleted and can be restored.
 type Student struct {
-	ID      int    `json:"id"`
-	GroupID int    `json:"group_id"`
-	Name    string `json:"name"`
-	Email   string `json:"email,omitempty"` // Email is omitted in responses as per requirements
+	ID               int        `json:"id"`
+	GroupID          int        `json:"group_id"`
+	Path             []GroupRef `json:"path,omitempty"` // Path leads from the root group down to the group of the student
+	Name             string     `json:"name"`
+	Email            string     `json:"email,omitempty"` // Email is hidden or masked in responses as PIIPolicy says
+	DateOfBirth      *Date      `json:"date_of_birth,omitempty"     swaggertype:"string" format:"date" example:"2005-03-01"`
+	Phone            string     `json:"phone,omitempty"             example:"+4915112345678"`
+	EnrollmentNumber string     `json:"enrollment_number,omitempty" example:"PH-2024-0042"`
+	Status           string     `json:"status"                      example:"active"`
+	Guardians        []Guardian `json:"guardians,omitempty"`
+	Version          int        `json:"-"` // Version is exposed as the ETag header
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+	DeletedAt        *time.Time `json:"deleted_at,omitempty"`
 }
 
 // Group represents an academic group.
+// A group with DeletedAt set has been soft-deleted and can be restored.
 type Group struct {
-	ID        int     `json:"id"`
-	ParentID  *int    `json:"parent_id,omitempty"`
-	Name      string  `json:"name"`
-	SubGroups []Group `json:"subGroups,omitempty"`
+	ID           int           `json:"id"`
+	ParentID     *int          `json:"parent_id,omitempty"`
+	Name         string        `json:"name"`
+	Path         []GroupRef    `json:"path,omitempty"` // Path leads from the root group down to the group itself
+	SubGroups    []Group       `json:"subGroups,omitempty"`
+	StudentCount *StudentCount `json:"student_count,omitempty"` // StudentCount is loaded along with subgroups
+	Version      int           `json:"-"`                       // Version is exposed as the ETag header
+	CreatedAt    time.Time     `json:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at"`
+	DeletedAt    *time.Time    `json:"deleted_at,omitempty"`
+}
+
+// StudentCount counts the active students of a group, Total also those of its active subgroups at any depth.
+type StudentCount struct {
+	Direct int `json:"direct" example:"30"`
+	Total  int `json:"total"  example:"120"`
+}
+
+// GroupRef identifies a group in the path of a group or student.
+type GroupRef struct {
+	ID   int    `json:"id"   example:"1"`
+	Name string `json:"name" example:"Faculty of Physics"`
+}
+
+// NewPath turns a group followed by its ancestors up to the root into the path from the root down to the group.
+func NewPath(ancestors []Group) []GroupRef {
+	path := make([]GroupRef, len(ancestors))
+	for i, g := range ancestors {
+		path[len(ancestors)-1-i] = GroupRef{ID: g.ID, Name: g.Name}
+	}
+
+	return path
+}
+
+// GroupPath is a group flattened for export, Path holds the names of its ancestors
+// from the root group down to the group itself. Levels is the length of the longest path
+// of the export, the same for every group of it.
+type GroupPath struct {
+	Group
+	Path   []string `json:"path"`
+	Levels int      `json:"-"`
+}
+
+// StudentPatch holds the fields of a partial student update. Nil fields are left unchanged,
+// except DateOfBirth which is applied whenever SetDateOfBirth is true, so it can be removed.
+// Status is only changed through a status change, which checks the transition.
+// A non-zero Version must match the stored version of the student.
+type StudentPatch struct {
+	Name             *string
+	Email            *string
+	GroupID          *int
+	DateOfBirth      *Date
+	SetDateOfBirth   bool
+	Phone            *string
+	EnrollmentNumber *string
+	Guardians        *[]Guardian
+	Status           *string
+	Version          int
+}
+
+// GroupPatch holds the fields of a partial group update. Nil fields are left unchanged,
+// except ParentID which is applied whenever SetParent is true, so a group can become a root.
+// A non-zero Version must match the stored version of the group.
+type GroupPatch struct {
+	Name      *string
+	ParentID  *int
+	SetParent bool
+	Version   int
+}
+
+// Group deletion modes.
+const (
+	// DeleteRestrict refuses to delete groups with active subgroups or students.
+	DeleteRestrict = "restrict"
+	// DeleteCascade deletes the subgroups and students of the group along with it.
+	DeleteCascade = "cascade"
+	// DeleteReassign moves the subgroups and students of the group to a target group first.
+	DeleteReassign = "reassign"
+)
+
+// GroupDeletion says what happens to the subgroups and students of a deleted group,
+// Target is the group receiving them in reassign mode.
+type GroupDeletion struct {
+	Mode   string
+	Target int
+}
+
+// GroupChange summarizes a move, merge or deletion of groups. Group is the moved group or the group merged into,
+// Students and Subgroups count the active students and subgroups that moved or were deleted along with it,
+// StudentIDs lists those students for the audit log.
+type GroupChange struct {
+	Group      Group `json:"group"`
+	Students   int   `json:"students"  example:"120"`
+	Subgroups  int   `json:"subgroups" example:"4"`
+	StudentIDs []int `json:"-"`
 }
 
 // StudentCreateRequest represents request body for creating a student.
@@ -22,23 +22,58 @@ type (
 	}
 )
 
+// Transactor runs a function in a transaction that every repository called with the context passed to it takes part in.
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
 type StudentRepo interface {
 	CreateStudent(ctx context.Context, student entity.Student) (entity.Student, error)
-	GetStudents(ctx context.Context) ([]entity.Student, error)
+	GetStudents(ctx context.Context, filter entity.StudentFilter, page entity.PageRequest) (entity.Page[entity.Student], error)
 	GetStudentByID(ctx context.Context, id int) (entity.Student, error)
-	UpdateStudent(ctx context.Context, student entity.Student) error
-	DeleteStudent(ctx context.Context, id int) error
-	SearchStudents(ctx context.Context, query string) ([]entity.Student, error)
+	UpdateStudent(ctx context.Context, student entity.Student) (entity.Student, error)
+	PatchStudent(ctx context.Context, id int, patch entity.StudentPatch) (entity.Student, error)
+	DeleteStudent(ctx context.Context, id, version int) error
+	RestoreStudent(ctx context.Context, id int) (entity.Student, error)
+	ExportStudents(ctx context.Context, filter entity.StudentFilter, yield func(entity.Student) error) error
+	SearchStudents(ctx context.Context, query entity.SearchQuery) ([]entity.StudentHit, error)
+	GetEnrollments(ctx context.Context, studentID int) ([]entity.Enrollment, error)
+}
+
+// AuditRepo defines the audit log repository interface.
+type AuditRepo interface {
+	StoreAudit(ctx context.Context, entries ...entity.AuditEntry) error
+	GetAudit(ctx context.Context, filter entity.AuditFilter, page entity.PageRequest) (entity.Page[entity.AuditEntry], error)
+}
+
+// GrantRepo defines the repository interface of the roles of subjects on groups.
+type GrantRepo interface {
+	GetGrants(ctx context.Context, subject string) ([]entity.Grant, error)
+	GetGrantByID(ctx context.Context, id int) (entity.Grant, error)
+	CreateGrant(ctx context.Context, grant entity.Grant) (entity.Grant, error)
+	DeleteGrant(ctx context.Context, id int) error
 }
 
 // GroupRepo defines the group repository interface.
 type GroupRepo interface {
 	CreateGroup(ctx context.Context, group entity.Group) (entity.Group, error)
-	GetGroups(ctx context.Context) ([]entity.Group, error)
+	GetGroups(ctx context.Context, filter entity.GroupFilter, page entity.PageRequest) (entity.Page[entity.Group], error)
 	GetGroupByID(ctx context.Context, id int) (entity.Group, error)
-	UpdateGroup(ctx context.Context, group entity.Group) error
-	DeleteGroup(ctx context.Context, id int) error
-	SearchGroups(ctx context.Context, query string) ([]entity.Group, error)
+	UpdateGroup(ctx context.Context, group entity.Group) (entity.Group, error)
+	PatchGroup(ctx context.Context, id int, patch entity.GroupPatch) (entity.Group, error)
+	DeleteGroup(ctx context.Context, id, version int) error
+	DeleteDescendants(ctx context.Context, id int) (subgroups int, students []int, err error)
+	RestoreGroup(ctx context.Context, id int) (entity.Group, error)
+	SearchGroups(ctx context.Context, query entity.SearchQuery) ([]entity.GroupHit, error)
 	HasSubgroups(ctx context.Context, id int) (bool, error)
-	GetGroupWithSubgroups(ctx context.Context, id int) (entity.Group, error)
+	HasStudents(ctx context.Context, id int) (bool, error)
+	SubtreeSize(ctx context.Context, id int) (subgroups, students int, err error)
+	MoveStudents(ctx context.Context, from, to int) ([]int, error)
+	MoveSubgroups(ctx context.Context, from, to int) (int, error)
+	GetGroupWithSubgroups(ctx context.Context, id, maxDepth int) (entity.Group, error)
+	GetAncestors(ctx context.Context, id int) ([]entity.Group, error)
+	LockTree(ctx context.Context) error
+	FindGroups(ctx context.Context, ids []int, names []string) ([]entity.Group, error)
+	ExportGroups(ctx context.Context, filter entity.GroupFilter, yield func(entity.GroupPath) error) error
+	GroupStats(ctx context.Context, id *int) ([]entity.GroupStats, error)
 }
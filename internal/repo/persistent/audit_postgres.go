@@ -0,0 +1,136 @@
+package persistent
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/pkg/postgres"
+)
+
+// AuditRepo stores the audit log of changes to students and groups.
+type AuditRepo struct {
+	*postgres.Postgres
+}
+
+// NewAuditRepo creates a new audit repository.
+func NewAuditRepo(pg *postgres.Postgres) *AuditRepo {
+	return &AuditRepo{pg}
+}
+
+// StoreAudit appends entries to the audit log, in the transaction of the context if there is one
+func (r *AuditRepo) StoreAudit(ctx context.Context, entries ...entity.AuditEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	b := r.Builder.
+		Insert("audit_log").
+		Columns("actor", "action", "entity", "entity_id", "diff", "request_id")
+
+	for _, e := range entries {
+		b = b.Values(e.Actor, e.Action, e.Entity, e.EntityID, e.Diff, e.RequestID)
+	}
+
+	sql, args, err := b.ToSql()
+	if err != nil {
+		return fmt.Errorf("AuditRepo - StoreAudit - r.Builder: %w", err)
+	}
+
+	if _, err = r.Conn(ctx).Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("AuditRepo - StoreAudit - r.Conn.Exec: %w", err)
+	}
+
+	return nil
+}
+
+// _auditColumns are the columns scanned by auditEntryFields, in the same order.
+var _auditColumns = []string{"id", "actor", "action", "entity", "entity_id", "diff", "request_id", "created_at"} //nolint:gochecknoglobals // column list
+
+// auditEntryFields returns scan destinations for _auditColumns.
+func auditEntryFields(e *entity.AuditEntry) []any {
+	return []any{&e.ID, &e.Actor, &e.Action, &e.Entity, &e.EntityID, &e.Diff, &e.RequestID, &e.CreatedAt}
+}
+
+// filterAudit applies the audit filter to a query over the audit_log table.
+func filterAudit(b squirrel.SelectBuilder, filter entity.AuditFilter) squirrel.SelectBuilder {
+	if filter.Entity != "" {
+		b = b.Where("entity = ?", filter.Entity)
+	}
+
+	if filter.EntityID != nil {
+		b = b.Where("entity_id = ?", *filter.EntityID)
+	}
+
+	if filter.Actor != "" {
+		b = b.Where("actor = ?", filter.Actor)
+	}
+
+	if filter.Action != "" {
+		b = b.Where("action = ?", filter.Action)
+	}
+
+	if filter.RequestID != "" {
+		b = b.Where("request_id = ?", filter.RequestID)
+	}
+
+	if filter.Since != nil {
+		b = b.Where("created_at >= ?", *filter.Since)
+	}
+
+	if filter.Until != nil {
+		b = b.Where("created_at < ?", *filter.Until)
+	}
+
+	return b
+}
+
+// GetAudit retrieves one page of audit entries matching the filter, ordered by id
+func (r *AuditRepo) GetAudit(ctx context.Context, filter entity.AuditFilter, page entity.PageRequest) (entity.Page[entity.AuditEntry], error) {
+	sql, args, err := filterAudit(r.Builder.Select("COUNT(*)").From("audit_log"), filter).ToSql()
+	if err != nil {
+		return entity.Page[entity.AuditEntry]{}, fmt.Errorf("AuditRepo - GetAudit - r.Builder: %w", err)
+	}
+
+	var result entity.Page[entity.AuditEntry]
+	if err = r.Conn(ctx).QueryRow(ctx, sql, args...).Scan(&result.Total); err != nil {
+		return entity.Page[entity.AuditEntry]{}, fmt.Errorf("AuditRepo - GetAudit - r.Conn.QueryRow: %w", err)
+	}
+
+	b, err := keyset(filterAudit(r.Builder.Select(_auditColumns...).From("audit_log"), filter), sortColumn{name: "id", numeric: true}, page)
+	if err != nil {
+		return entity.Page[entity.AuditEntry]{}, fmt.Errorf("AuditRepo - GetAudit - keyset: %w", err)
+	}
+
+	sql, args, err = b.ToSql()
+	if err != nil {
+		return entity.Page[entity.AuditEntry]{}, fmt.Errorf("AuditRepo - GetAudit - r.Builder: %w", err)
+	}
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return entity.Page[entity.AuditEntry]{}, fmt.Errorf("AuditRepo - GetAudit - r.Conn.Query: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]entity.AuditEntry, 0, page.Limit+1)
+	for rows.Next() {
+		var e entity.AuditEntry
+		if err := rows.Scan(auditEntryFields(&e)...); err != nil {
+			return entity.Page[entity.AuditEntry]{}, fmt.Errorf("AuditRepo - GetAudit - rows.Scan: %w", err)
+		}
+		entries = append(entries, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return entity.Page[entity.AuditEntry]{}, fmt.Errorf("AuditRepo - GetAudit - rows.Err: %w", err)
+	}
+
+	result.Items, result.NextCursor = nextCursor(entries, page, func(e entity.AuditEntry) (string, int) {
+		return strconv.Itoa(e.ID), e.ID
+	})
+
+	return result, nil
+}
@@ -0,0 +1,110 @@
+package persistent
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/pkg/postgres"
+	"github.com/jackc/pgx/v5"
+)
+
+// _enrollmentColumns are the columns scanned by enrollmentFields, in the same order.
+var _enrollmentColumns = []string{ //nolint:gochecknoglobals // column list
+	"id", "student_id", "group_id", "started_at", "ended_at", "reason", "COALESCE(end_reason, '')",
+}
+
+// enrollmentFields returns scan destinations for _enrollmentColumns.
+func enrollmentFields(e *entity.Enrollment) []any {
+	return []any{&e.ID, &e.StudentID, &e.GroupID, &e.StartedAt, &e.EndedAt, &e.Reason, &e.EndReason}
+}
+
+// enroll brings the enrollments of the given students in line with the students after a change: the current
+// enrollment of a student that has been deleted or has left its group ends, and active students without a current
+// enrollment start one in their group. Both take the reason and the start time of the transaction, so callers
+// run it in the transaction of the change.
+func enroll(ctx context.Context, pg *postgres.Postgres, reason string, studentIDs ...int) error {
+	if len(studentIDs) == 0 {
+		return nil
+	}
+
+	sql, args, err := pg.Builder.
+		Update("enrollments e").
+		Set("ended_at", squirrel.Expr("now()")).
+		Set("end_reason", reason).
+		From("students s").
+		Where("s.id = e.student_id").
+		Where("e.ended_at IS NULL").
+		Where("s.id = ANY(?)", studentIDs).
+		Where("(s.deleted_at IS NOT NULL OR s.group_id <> e.group_id)").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("enroll - pg.Builder: %w", err)
+	}
+
+	if _, err = pg.Conn(ctx).Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("enroll - pg.Conn.Exec: %w", err)
+	}
+
+	sql, args, err = pg.Builder.
+		Insert("enrollments").
+		Columns("student_id", "group_id", "reason").
+		Select(squirrel.
+			Select("s.id", "s.group_id").
+			Column(squirrel.Expr("?", reason)).
+			From("students s").
+			Where("s.id = ANY(?)", studentIDs).
+			Where("s.deleted_at IS NULL").
+			Where("NOT EXISTS (SELECT 1 FROM enrollments e WHERE e.student_id = s.id AND e.ended_at IS NULL)")).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("enroll - pg.Builder: %w", err)
+	}
+
+	if _, err = pg.Conn(ctx).Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("enroll - pg.Conn.Exec: %w", err)
+	}
+
+	return nil
+}
+
+// enrolledAt matches the students enrolled at the given time in a group matched by groups,
+// a condition on the group_id column of the enrollments.
+func enrolledAt(groups squirrel.Sqlizer, at time.Time) squirrel.Sqlizer {
+	return squirrel.Expr(
+		"id IN (SELECT student_id FROM enrollments WHERE ? AND started_at <= ? AND (ended_at IS NULL OR ended_at > ?))",
+		groups, at, at,
+	)
+}
+
+// GetEnrollments retrieves the enrollments of a student, the earliest first
+func (r *StudentRepo) GetEnrollments(ctx context.Context, studentID int) ([]entity.Enrollment, error) {
+	sql, args, err := r.Builder.
+		Select(_enrollmentColumns...).
+		From("enrollments").
+		Where("student_id = ?", studentID).
+		OrderBy("started_at", "id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("StudentRepo - GetEnrollments - r.Builder: %w", err)
+	}
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("StudentRepo - GetEnrollments - r.Conn.Query: %w", err)
+	}
+
+	enrollments, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (entity.Enrollment, error) {
+		var e entity.Enrollment
+		err := row.Scan(enrollmentFields(&e)...)
+
+		return e, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("StudentRepo - GetEnrollments - pgx.CollectRows: %w", err)
+	}
+
+	return enrollments, nil
+}
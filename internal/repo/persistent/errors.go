@@ -0,0 +1,65 @@
+package persistent
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Postgres error codes translated into domain errors.
+const (
+	_pgNotNullViolation    = "23502"
+	_pgForeignKeyViolation = "23503"
+	_pgUniqueViolation     = "23505"
+	_pgCheckViolation      = "23514"
+	_pgStringTooLong       = "22001"
+)
+
+// _uniqueFields maps unique constraints and indexes to the field they protect.
+var _uniqueFields = map[string]string{ //nolint:gochecknoglobals // lookup table
+	"idx_students_email_unique":             "email",
+	"idx_students_enrollment_number_unique": "enrollment_number",
+	"access_grants_subject_group_key":       "group_id",
+}
+
+// notFound returns the not found error for the given subject, e.g. "student".
+func notFound(subject string) *entity.Error {
+	return entity.NewError(entity.ErrNotFound, subject+"_not_found", subject+" not found")
+}
+
+// mapError translates driver errors into domain errors about the given subject.
+// Errors without a domain meaning are returned unchanged.
+func mapError(err error, subject string) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return notFound(subject).Wrap(err)
+	}
+
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	switch pgErr.Code {
+	case _pgUniqueViolation:
+		field, ok := _uniqueFields[pgErr.ConstraintName]
+		if !ok {
+			field = pgErr.ConstraintName
+		}
+
+		return entity.NewError(entity.ErrConflict, subject+"_already_exists", subject+" already exists",
+			entity.FieldError{Field: field, Message: "is already taken"}).Wrap(err)
+	case _pgForeignKeyViolation:
+		field := strings.TrimSuffix(strings.TrimPrefix(pgErr.ConstraintName, pgErr.TableName+"_"), "_fkey")
+
+		return entity.NewError(entity.ErrForeignKey, "foreign_key_violation", "referenced entity does not exist",
+			entity.FieldError{Field: field, Message: "references an entity that does not exist"}).Wrap(err)
+	case _pgNotNullViolation, _pgCheckViolation, _pgStringTooLong:
+		return entity.NewError(entity.ErrValidation, "invalid_"+subject, "invalid "+subject,
+			entity.FieldError{Field: pgErr.ColumnName, Message: pgErr.Message}).Wrap(err)
+	}
+
+	return err
+}
@@ -0,0 +1,87 @@
+package persistent
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMapError(t *testing.T) {
+	t.Parallel()
+
+	errOther := errors.New("connection reset")
+
+	tests := []struct {
+		name   string
+		err    error
+		kind   error
+		code   string
+		fields []entity.FieldError
+	}{
+		{
+			name: "no rows",
+			err:  pgx.ErrNoRows,
+			kind: entity.ErrNotFound,
+			code: "student_not_found",
+		},
+		{
+			name:   "duplicate email",
+			err:    &pgconn.PgError{Code: _pgUniqueViolation, ConstraintName: "idx_students_email_unique"},
+			kind:   entity.ErrConflict,
+			code:   "student_already_exists",
+			fields: []entity.FieldError{{Field: "email", Message: "is already taken"}},
+		},
+		{
+			name:   "duplicate enrollment number",
+			err:    &pgconn.PgError{Code: _pgUniqueViolation, ConstraintName: "idx_students_enrollment_number_unique"},
+			kind:   entity.ErrConflict,
+			code:   "student_already_exists",
+			fields: []entity.FieldError{{Field: "enrollment_number", Message: "is already taken"}},
+		},
+		{
+			name: "missing group",
+			err: &pgconn.PgError{
+				Code:           _pgForeignKeyViolation,
+				TableName:      "students",
+				ConstraintName: "students_group_id_fkey",
+			},
+			kind:   entity.ErrForeignKey,
+			code:   "foreign_key_violation",
+			fields: []entity.FieldError{{Field: "group_id", Message: "references an entity that does not exist"}},
+		},
+		{
+			name:   "value too long",
+			err:    &pgconn.PgError{Code: _pgStringTooLong, ColumnName: "name", Message: "value too long"},
+			kind:   entity.ErrValidation,
+			code:   "invalid_student",
+			fields: []entity.FieldError{{Field: "name", Message: "value too long"}},
+		},
+	}
+
+	for _, tc := range tests {
+		localTc := tc
+
+		t.Run(localTc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := mapError(localTc.err, "student")
+
+			var domainErr *entity.Error
+			require.ErrorAs(t, err, &domainErr)
+			require.ErrorIs(t, err, localTc.kind)
+			require.ErrorIs(t, err, localTc.err)
+			require.Equal(t, localTc.code, domainErr.Code)
+			require.Equal(t, localTc.fields, domainErr.Fields)
+		})
+	}
+
+	t.Run("other error", func(t *testing.T) {
+		t.Parallel()
+
+		require.Equal(t, errOther, mapError(errOther, "student"))
+	})
+}
@@ -0,0 +1,123 @@
+package persistent
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/pkg/postgres"
+	"github.com/jackc/pgx/v5"
+)
+
+// GrantRepo stores the roles of subjects on groups.
+type GrantRepo struct {
+	*postgres.Postgres
+}
+
+// NewGrantRepo creates a new grant repository.
+func NewGrantRepo(pg *postgres.Postgres) *GrantRepo {
+	return &GrantRepo{pg}
+}
+
+// _grantColumns are the columns scanned by grantFields, in the same order.
+var _grantColumns = []string{"id", "subject", "group_id", "role", "created_at"} //nolint:gochecknoglobals // column list
+
+// grantFields returns scan destinations for _grantColumns.
+func grantFields(g *entity.Grant) []any {
+	return []any{&g.ID, &g.Subject, &g.GroupID, &g.Role, &g.CreatedAt}
+}
+
+// GetGrants retrieves the grants of a subject, or of every subject when subject is empty
+func (r *GrantRepo) GetGrants(ctx context.Context, subject string) ([]entity.Grant, error) {
+	b := r.Builder.
+		Select(_grantColumns...).
+		From("access_grants").
+		OrderBy("id")
+
+	if subject != "" {
+		b = b.Where("subject = ?", subject)
+	}
+
+	sql, args, err := b.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("GrantRepo - GetGrants - r.Builder: %w", err)
+	}
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("GrantRepo - GetGrants - r.Conn.Query: %w", err)
+	}
+
+	grants, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (entity.Grant, error) {
+		var g entity.Grant
+		err := row.Scan(grantFields(&g)...)
+
+		return g, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("GrantRepo - GetGrants - pgx.CollectRows: %w", err)
+	}
+
+	return grants, nil
+}
+
+// GetGrantByID retrieves a grant by ID
+func (r *GrantRepo) GetGrantByID(ctx context.Context, id int) (entity.Grant, error) {
+	sql, args, err := r.Builder.
+		Select(_grantColumns...).
+		From("access_grants").
+		Where("id = ?", id).
+		ToSql()
+	if err != nil {
+		return entity.Grant{}, fmt.Errorf("GrantRepo - GetGrantByID - r.Builder: %w", err)
+	}
+
+	var grant entity.Grant
+	if err = r.Conn(ctx).QueryRow(ctx, sql, args...).Scan(grantFields(&grant)...); err != nil {
+		return entity.Grant{}, fmt.Errorf("GrantRepo - GetGrantByID - r.Conn.QueryRow: %w", mapError(err, "grant"))
+	}
+
+	return grant, nil
+}
+
+// CreateGrant creates a grant, a subject has at most one grant per group
+func (r *GrantRepo) CreateGrant(ctx context.Context, grant entity.Grant) (entity.Grant, error) {
+	sql, args, err := r.Builder.
+		Insert("access_grants").
+		Columns("subject", "group_id", "role").
+		Values(grant.Subject, grant.GroupID, grant.Role).
+		Suffix("RETURNING id, subject, group_id, role, created_at").
+		ToSql()
+	if err != nil {
+		return entity.Grant{}, fmt.Errorf("GrantRepo - CreateGrant - r.Builder: %w", err)
+	}
+
+	var created entity.Grant
+	if err = r.Conn(ctx).QueryRow(ctx, sql, args...).Scan(grantFields(&created)...); err != nil {
+		return entity.Grant{}, fmt.Errorf("GrantRepo - CreateGrant - r.Conn.QueryRow: %w", mapError(err, "grant"))
+	}
+
+	return created, nil
+}
+
+// DeleteGrant deletes a grant
+func (r *GrantRepo) DeleteGrant(ctx context.Context, id int) error {
+	sql, args, err := r.Builder.
+		Delete("access_grants").
+		Where("id = ?", id).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("GrantRepo - DeleteGrant - r.Builder: %w", err)
+	}
+
+	tag, err := r.Conn(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("GrantRepo - DeleteGrant - r.Conn.Exec: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("GrantRepo - DeleteGrant - r.Conn.Exec: %w", notFound("grant"))
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package persistent
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/evrone/go-clean-template/internal/entity"
+)
+
+// sortColumn maps a public sort field to the column it orders by.
+type sortColumn struct {
+	name    string
+	numeric bool
+}
+
+// keyset orders the query by (column, id) and positions it right after the page cursor.
+// One extra row is requested so the caller can tell whether a next page exists.
+func keyset(b squirrel.SelectBuilder, column sortColumn, page entity.PageRequest) (squirrel.SelectBuilder, error) {
+	dir, op := "ASC", ">"
+	if page.Desc {
+		dir, op = "DESC", "<"
+	}
+
+	if page.After != nil {
+		var value any = page.After.Value
+
+		if column.numeric {
+			n, err := strconv.Atoi(page.After.Value)
+			if err != nil {
+				return b, entity.ErrInvalidCursor.Wrap(err)
+			}
+
+			value = n
+		}
+
+		b = b.Where(fmt.Sprintf("(%s, id) %s (?, ?)", column.name, op), value, page.After.ID)
+	}
+
+	return b.
+		OrderBy(column.name+" "+dir, "id "+dir).
+		Limit(uint64(page.Limit) + 1), nil //nolint:gosec // limit is validated by the caller
+}
+
+// nextCursor trims the extra row requested by keyset and returns the cursor of the next page.
+func nextCursor[T any](items []T, page entity.PageRequest, key func(T) (string, int)) ([]T, *entity.Cursor) {
+	if len(items) <= page.Limit {
+		return items, nil
+	}
+
+	items = items[:page.Limit]
+	value, id := key(items[len(items)-1])
+
+	return items, &entity.Cursor{
+		Sort:  page.Sort,
+		Desc:  page.Desc,
+		Value: value,
+		ID:    id,
+	}
+}
@@ -0,0 +1,124 @@
+package persistent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/evrone/go-clean-template/pkg/postgres"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	// _fuzzyThreshold is the word similarity a name needs to match a fuzzy query,
+	// low enough for short names with a typo or two.
+	_fuzzyThreshold = "0.3"
+	// _setFuzzyThreshold sets the fuzzy match threshold until the end of the transaction.
+	_setFuzzyThreshold = "SELECT set_config('pg_trgm.word_similarity_threshold', $1, true)"
+	// _headlineOptions marks the matched words in search highlights.
+	_headlineOptions = "StartSel=<mark>, StopSel=</mark>, HighlightAll=true"
+)
+
+// tsQuery builds a to_tsquery expression matching all words of any of the terms,
+// as prefixes when prefix is set. The result is empty when the terms have no words.
+func tsQuery(terms []string, prefix bool) string {
+	alternatives := make([]string, 0, len(terms))
+
+	for _, term := range terms {
+		words := strings.FieldsFunc(strings.ToLower(term), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		if len(words) == 0 {
+			continue
+		}
+
+		for i, w := range words {
+			words[i] = "'" + w + "'"
+			if prefix {
+				words[i] += ":*"
+			}
+		}
+
+		alternatives = append(alternatives, "("+strings.Join(words, " & ")+")")
+	}
+
+	return strings.Join(alternatives, " | ")
+}
+
+// similarity returns the fuzzy match condition of the columns against any of the terms
+// and the rank column of the best match. The condition can use trigram indexes.
+func similarity(terms []string, columns ...string) (match squirrel.Or, rank squirrel.Sqlizer) {
+	ranks := make([]string, 0, len(terms)*len(columns))
+	args := make([]any, 0, len(terms)*len(columns))
+
+	for _, term := range terms {
+		for _, column := range columns {
+			match = append(match, squirrel.Expr("? <% "+column, term))
+			ranks = append(ranks, "word_similarity(?, "+column+")")
+			args = append(args, term)
+		}
+	}
+
+	return match, squirrel.Expr("GREATEST("+strings.Join(ranks, ", ")+")::float8 AS rank", args...)
+}
+
+// qualified prefixes the columns with a table alias.
+func qualified(alias string, columns []string) []string {
+	result := make([]string, len(columns))
+	for i, c := range columns {
+		result[i] = alias + "." + c
+	}
+
+	return result
+}
+
+// search runs a search query in a read-only transaction that lowers the fuzzy match threshold of pg_trgm.
+// Inside another transaction the query runs under a savepoint of it instead, where the transaction options
+// do not apply, and the previous threshold is restored so that it does not leak into the outer transaction.
+func search[T any](ctx context.Context, pg *postgres.Postgres, b squirrel.SelectBuilder, scan pgx.RowToFunc[T]) ([]T, error) {
+	sql, args, err := b.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("search - b.ToSql: %w", err)
+	}
+
+	var hits []T
+
+	err = pg.WithinTxOptions(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly}, func(ctx context.Context) error {
+		var previous *string
+
+		err := pg.Conn(ctx).QueryRow(ctx, "SELECT current_setting('pg_trgm.word_similarity_threshold', true)").Scan(&previous)
+		if err != nil {
+			return fmt.Errorf("pg.Conn.QueryRow: %w", err)
+		}
+
+		_, err = pg.Conn(ctx).Exec(ctx, _setFuzzyThreshold, _fuzzyThreshold)
+		if err != nil {
+			return fmt.Errorf("pg.Conn.Exec: %w", err)
+		}
+
+		rows, err := pg.Conn(ctx).Query(ctx, sql, args...)
+		if err != nil {
+			return fmt.Errorf("pg.Conn.Query: %w", err)
+		}
+
+		hits, err = pgx.CollectRows(rows, scan)
+		if err != nil {
+			return fmt.Errorf("pgx.CollectRows: %w", err)
+		}
+
+		// A null setting resets the threshold to its default
+		_, err = pg.Conn(ctx).Exec(ctx, _setFuzzyThreshold, previous)
+		if err != nil {
+			return fmt.Errorf("pg.Conn.Exec: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("search - pg.WithinTxOptions: %w", err)
+	}
+
+	return hits, nil
+}
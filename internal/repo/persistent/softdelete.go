@@ -0,0 +1,44 @@
+package persistent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/pkg/postgres"
+	"github.com/jackc/pgx/v5"
+)
+
+// _notDeleted excludes soft-deleted rows.
+const _notDeleted = "deleted_at IS NULL"
+
+// missedRestore explains why a restore matched no rows: the row does not exist or the row
+// it references has been deleted in the meantime. It returns nil when the row is not deleted,
+// restoring an active row is a no-op.
+func missedRestore(ctx context.Context, r *postgres.Postgres, table, subject string, id int, orphaned *entity.Error) error {
+	sql, args, err := r.Builder.
+		Select(_notDeleted).
+		From(table).
+		Where("id = ?", id).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("missedRestore - r.Builder: %w", err)
+	}
+
+	var active bool
+	err = r.Conn(ctx).QueryRow(ctx, sql, args...).Scan(&active)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return notFound(subject)
+	}
+
+	if err != nil {
+		return fmt.Errorf("missedRestore - r.Conn.QueryRow: %w", err)
+	}
+
+	if active {
+		return nil
+	}
+
+	return orphaned
+}
@@ -2,10 +2,15 @@ package persistent
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 
+	"github.com/Masterminds/squirrel"
 	"github.com/evrone/go-clean-template/internal/entity"
 	"github.com/evrone/go-clean-template/pkg/postgres"
+	"github.com/jackc/pgx/v5"
 )
 
 // StudentRepo implements the student repository interface
@@ -28,140 +33,465 @@ func NewGroupRepo(pg *postgres.Postgres) *GroupRepo {
 	return &GroupRepo{pg}
 }
 
-// CreateStudent creates a new student
+// CreateStudent creates a new student and enrolls it in its group, students without a status are active
 func (r *StudentRepo) CreateStudent(ctx context.Context, student entity.Student) (entity.Student, error) {
+	if student.Status == "" {
+		student.Status = entity.StudentActive
+	}
+
+	student.Guardians = guardians(student.Guardians)
+
 	sql, args, err := r.Builder.
 		Insert("students").
-		Columns("name", "email", "group_id").
-		Values(student.Name, student.Email, student.GroupID).
-		Suffix("RETURNING id").
+		Columns("name", "email", "group_id", "date_of_birth", "phone", "enrollment_number", "status", "guardians").
+		Values(student.Name, student.Email, student.GroupID, student.DateOfBirth, student.Phone, student.EnrollmentNumber,
+			student.Status, student.Guardians).
+		Suffix("RETURNING id, version, created_at, updated_at").
 		ToSql()
 	if err != nil {
 		return entity.Student{}, fmt.Errorf("StudentRepo - CreateStudent - r.Builder: %w", err)
 	}
 
-	err = r.Pool.QueryRow(ctx, sql, args...).Scan(&student.ID)
+	err = r.WithinTx(ctx, func(ctx context.Context) error {
+		err := r.Conn(ctx).QueryRow(ctx, sql, args...).Scan(&student.ID, &student.Version, &student.CreatedAt, &student.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("r.Conn.QueryRow: %w", mapError(err, "student"))
+		}
+
+		return enroll(ctx, r.Postgres, entity.EnrollmentEnrolled, student.ID)
+	})
 	if err != nil {
-		return entity.Student{}, fmt.Errorf("StudentRepo - CreateStudent - r.Pool.QueryRow: %w", err)
+		return entity.Student{}, fmt.Errorf("StudentRepo - CreateStudent - r.WithinTx: %w", err)
 	}
 
 	return student, nil
 }
 
-// GetStudents retrieves all students
-func (r *StudentRepo) GetStudents(ctx context.Context) ([]entity.Student, error) {
-	sql, _, err := r.Builder.
-		Select("id", "name", "group_id").
-		From("students").
+// ExportStudents streams the students matching the filter ordered by ID, calling yield for each of them
+func (r *StudentRepo) ExportStudents(ctx context.Context, filter entity.StudentFilter, yield func(entity.Student) error) error {
+	sql, args, err := filterStudents(r.Builder.Select(_studentColumns...).From("students"), filter).
+		OrderBy("id").
 		ToSql()
 	if err != nil {
-		return nil, fmt.Errorf("StudentRepo - GetStudents - r.Builder: %w", err)
+		return fmt.Errorf("StudentRepo - ExportStudents - r.Builder: %w", err)
 	}
 
-	rows, err := r.Pool.Query(ctx, sql)
+	rows, err := r.Conn(ctx).Query(ctx, sql, args...)
 	if err != nil {
-		return nil, fmt.Errorf("StudentRepo - GetStudents - r.Pool.Query: %w", err)
+		return fmt.Errorf("StudentRepo - ExportStudents - r.Conn.Query: %w", err)
 	}
 	defer rows.Close()
 
-	var students []entity.Student
 	for rows.Next() {
 		var s entity.Student
-		if err := rows.Scan(&s.ID, &s.Name, &s.GroupID); err != nil {
-			return nil, fmt.Errorf("StudentRepo - GetStudents - rows.Scan: %w", err)
+		if err := rows.Scan(studentFields(&s)...); err != nil {
+			return fmt.Errorf("StudentRepo - ExportStudents - rows.Scan: %w", err)
+		}
+
+		if err := yield(s); err != nil {
+			return fmt.Errorf("StudentRepo - ExportStudents - yield: %w", err)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("StudentRepo - ExportStudents - rows.Err: %w", err)
+	}
+
+	return nil
+}
+
+// _studentColumns are the columns scanned by studentFields, in the same order.
+var _studentColumns = []string{ //nolint:gochecknoglobals // column list
+	"id", "name", "email", "group_id", "date_of_birth", "phone", "enrollment_number", "status", "guardians",
+	"version", "created_at", "updated_at", "deleted_at",
+}
+
+// studentFields returns scan destinations for _studentColumns.
+func studentFields(s *entity.Student) []any {
+	return []any{
+		&s.ID, &s.Name, &s.Email, &s.GroupID, &s.DateOfBirth, &s.Phone, &s.EnrollmentNumber, &s.Status, &s.Guardians,
+		&s.Version, &s.CreatedAt, &s.UpdatedAt, &s.DeletedAt,
+	}
+}
+
+// guardians returns the guardians to store, never nil as the column holds a JSON array.
+func guardians(g []entity.Guardian) []entity.Guardian {
+	if g == nil {
+		return []entity.Guardian{}
+	}
+
+	return g
+}
+
+// _studentSortColumns maps sort fields accepted for students to their columns.
+var _studentSortColumns = map[string]sortColumn{ //nolint:gochecknoglobals // lookup table
+	entity.SortByID:      {name: "id", numeric: true},
+	entity.SortByName:    {name: "name"},
+	entity.SortByGroupID: {name: "group_id", numeric: true},
+}
+
+// studentKey returns the sort key value and id of a student for cursor building.
+func studentKey(sort string) func(entity.Student) (string, int) {
+	return func(s entity.Student) (string, int) {
+		switch sort {
+		case entity.SortByName:
+			return s.Name, s.ID
+		case entity.SortByGroupID:
+			return strconv.Itoa(s.GroupID), s.ID
+		default:
+			return strconv.Itoa(s.ID), s.ID
+		}
+	}
+}
+
+// filterStudents applies the student filter to a query over the students table.
+func filterStudents(b squirrel.SelectBuilder, filter entity.StudentFilter) squirrel.SelectBuilder {
+	if !filter.IncludeDeleted && (filter.GroupID == nil || filter.AsOf == nil) {
+		b = b.Where(_notDeleted)
+	}
+
+	switch {
+	case filter.GroupID != nil && filter.AsOf != nil && filter.Recursive:
+		b = b.Where(enrolledAt(inScope("group_id", []int{*filter.GroupID}), *filter.AsOf))
+	case filter.GroupID != nil && filter.AsOf != nil:
+		b = b.Where(enrolledAt(squirrel.Eq{"group_id": *filter.GroupID}, *filter.AsOf))
+	case filter.GroupID != nil && filter.Recursive:
+		b = b.Where(inScope("group_id", []int{*filter.GroupID}))
+	case filter.GroupID != nil:
+		b = b.Where("group_id = ?", *filter.GroupID)
+	}
+
+	if filter.Email != "" {
+		b = b.Where("LOWER(email) = LOWER(?)", filter.Email)
+	}
+
+	if filter.EmailDomain != "" {
+		b = b.Where("LOWER(email) LIKE ?", "%@"+strings.ToLower(filter.EmailDomain))
+	}
+
+	if filter.Scope != nil {
+		b = b.Where(inScope("group_id", filter.Scope))
+	}
+
+	return b
+}
+
+// GetStudents retrieves one page of students matching the filter
+func (r *StudentRepo) GetStudents(ctx context.Context, filter entity.StudentFilter, page entity.PageRequest) (entity.Page[entity.Student], error) {
+	column, ok := _studentSortColumns[page.Sort]
+	if !ok {
+		column = _studentSortColumns[entity.SortByID]
+	}
+
+	sql, args, err := filterStudents(r.Builder.Select("COUNT(*)").From("students"), filter).ToSql()
+	if err != nil {
+		return entity.Page[entity.Student]{}, fmt.Errorf("StudentRepo - GetStudents - r.Builder: %w", err)
+	}
+
+	var result entity.Page[entity.Student]
+	if err = r.Conn(ctx).QueryRow(ctx, sql, args...).Scan(&result.Total); err != nil {
+		return entity.Page[entity.Student]{}, fmt.Errorf("StudentRepo - GetStudents - r.Conn.QueryRow: %w", err)
+	}
+
+	b, err := keyset(filterStudents(r.Builder.Select(_studentColumns...).From("students"), filter), column, page)
+	if err != nil {
+		return entity.Page[entity.Student]{}, fmt.Errorf("StudentRepo - GetStudents - keyset: %w", err)
+	}
+
+	sql, args, err = b.ToSql()
+	if err != nil {
+		return entity.Page[entity.Student]{}, fmt.Errorf("StudentRepo - GetStudents - r.Builder: %w", err)
+	}
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return entity.Page[entity.Student]{}, fmt.Errorf("StudentRepo - GetStudents - r.Conn.Query: %w", err)
+	}
+	defer rows.Close()
+
+	students := make([]entity.Student, 0, page.Limit+1)
+	for rows.Next() {
+		var s entity.Student
+		if err := rows.Scan(studentFields(&s)...); err != nil {
+			return entity.Page[entity.Student]{}, fmt.Errorf("StudentRepo - GetStudents - rows.Scan: %w", err)
 		}
 		students = append(students, s)
 	}
 
-	return students, nil
+	if err := rows.Err(); err != nil {
+		return entity.Page[entity.Student]{}, fmt.Errorf("StudentRepo - GetStudents - rows.Err: %w", err)
+	}
+
+	result.Items, result.NextCursor = nextCursor(students, page, studentKey(page.Sort))
+
+	return result, nil
 }
 
-// GetStudentByID retrieves a student by ID
+// GetStudentByID retrieves an active student by ID
 func (r *StudentRepo) GetStudentByID(ctx context.Context, id int) (entity.Student, error) {
 	sql, args, err := r.Builder.
-		Select("id", "name", "group_id").
+		Select(_studentColumns...).
 		From("students").
 		Where("id = ?", id).
+		Where(_notDeleted).
 		ToSql()
 	if err != nil {
 		return entity.Student{}, fmt.Errorf("StudentRepo - GetStudentByID - r.Builder: %w", err)
 	}
 
 	var student entity.Student
-	err = r.Pool.QueryRow(ctx, sql, args...).Scan(&student.ID, &student.Name, &student.GroupID)
+	err = r.Conn(ctx).QueryRow(ctx, sql, args...).Scan(studentFields(&student)...)
 	if err != nil {
-		return entity.Student{}, fmt.Errorf("StudentRepo - GetStudentByID - r.Pool.QueryRow: %w", err)
+		return entity.Student{}, fmt.Errorf("StudentRepo - GetStudentByID - r.Conn.QueryRow: %w", mapError(err, "student"))
 	}
 
 	return student, nil
 }
 
-// UpdateStudent updates an existing student
-func (r *StudentRepo) UpdateStudent(ctx context.Context, student entity.Student) error {
-	sql, args, err := r.Builder.
+// UpdateStudent updates an active student and returns the stored student, a non-zero Version must match
+// the stored one. The status is left unchanged, a student moved to another group is transferred there
+func (r *StudentRepo) UpdateStudent(ctx context.Context, student entity.Student) (entity.Student, error) {
+	sql, args, err := whereVersion(r.Builder.
 		Update("students").
 		Set("name", student.Name).
+		Set("email", student.Email).
 		Set("group_id", student.GroupID).
+		Set("date_of_birth", student.DateOfBirth).
+		Set("phone", student.Phone).
+		Set("enrollment_number", student.EnrollmentNumber).
+		Set("guardians", guardians(student.Guardians)).
+		Set("version", squirrel.Expr("version + 1")).
+		Set("updated_at", squirrel.Expr("now()")).
 		Where("id = ?", student.ID).
+		Where(_notDeleted), student.Version).
+		Suffix("RETURNING " + strings.Join(_studentColumns, ", ")).
 		ToSql()
 	if err != nil {
-		return fmt.Errorf("StudentRepo - UpdateStudent - r.Builder: %w", err)
+		return entity.Student{}, fmt.Errorf("StudentRepo - UpdateStudent - r.Builder: %w", err)
 	}
 
-	_, err = r.Pool.Exec(ctx, sql, args...)
+	var updated entity.Student
+
+	err = r.WithinTx(ctx, func(ctx context.Context) error {
+		err := r.Conn(ctx).QueryRow(ctx, sql, args...).Scan(studentFields(&updated)...)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return missedWrite(ctx, r.Postgres, "students", "student", student.ID)
+		}
+
+		if err != nil {
+			return fmt.Errorf("r.Conn.QueryRow: %w", mapError(err, "student"))
+		}
+
+		return enroll(ctx, r.Postgres, entity.EnrollmentTransferred, updated.ID)
+	})
 	if err != nil {
-		return fmt.Errorf("StudentRepo - UpdateStudent - r.Pool.Exec: %w", err)
+		return entity.Student{}, fmt.Errorf("StudentRepo - UpdateStudent - r.WithinTx: %w", err)
 	}
 
-	return nil
+	return updated, nil
 }
 
-// DeleteStudent deletes a student by ID
-func (r *StudentRepo) DeleteStudent(ctx context.Context, id int) error {
-	sql, args, err := r.Builder.
-		Delete("students").
+// PatchStudent updates only the fields present in the patch and returns the updated student,
+// a non-zero patch Version must match the stored one. A student moved to another group is transferred there
+func (r *StudentRepo) PatchStudent(ctx context.Context, id int, patch entity.StudentPatch) (entity.Student, error) {
+	// Nothing to change
+	if patch.Name == nil && patch.Email == nil && patch.GroupID == nil && !patch.SetDateOfBirth &&
+		patch.Phone == nil && patch.EnrollmentNumber == nil && patch.Guardians == nil && patch.Status == nil {
+		student, err := r.GetStudentByID(ctx, id)
+		if err == nil && patch.Version != 0 && patch.Version != student.Version {
+			return entity.Student{}, fmt.Errorf("StudentRepo - PatchStudent: %w", entity.ErrVersionMismatch)
+		}
+
+		return student, err
+	}
+
+	b := r.Builder.Update("students").
+		Set("version", squirrel.Expr("version + 1")).
+		Set("updated_at", squirrel.Expr("now()"))
+
+	if patch.Name != nil {
+		b = b.Set("name", *patch.Name)
+	}
+
+	if patch.Email != nil {
+		b = b.Set("email", *patch.Email)
+	}
+
+	if patch.GroupID != nil {
+		b = b.Set("group_id", *patch.GroupID)
+	}
+
+	if patch.SetDateOfBirth {
+		b = b.Set("date_of_birth", patch.DateOfBirth)
+	}
+
+	if patch.Phone != nil {
+		b = b.Set("phone", *patch.Phone)
+	}
+
+	if patch.EnrollmentNumber != nil {
+		b = b.Set("enrollment_number", *patch.EnrollmentNumber)
+	}
+
+	if patch.Guardians != nil {
+		b = b.Set("guardians", guardians(*patch.Guardians))
+	}
+
+	if patch.Status != nil {
+		b = b.Set("status", *patch.Status)
+	}
+
+	sql, args, err := whereVersion(b.Where("id = ?", id).Where(_notDeleted), patch.Version).
+		Suffix("RETURNING " + strings.Join(_studentColumns, ", ")).
+		ToSql()
+	if err != nil {
+		return entity.Student{}, fmt.Errorf("StudentRepo - PatchStudent - r.Builder: %w", err)
+	}
+
+	var student entity.Student
+
+	err = r.WithinTx(ctx, func(ctx context.Context) error {
+		err := r.Conn(ctx).QueryRow(ctx, sql, args...).Scan(studentFields(&student)...)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return missedWrite(ctx, r.Postgres, "students", "student", id)
+		}
+
+		if err != nil {
+			return fmt.Errorf("r.Conn.QueryRow: %w", mapError(err, "student"))
+		}
+
+		if patch.GroupID == nil {
+			return nil
+		}
+
+		return enroll(ctx, r.Postgres, entity.EnrollmentTransferred, id)
+	})
+	if err != nil {
+		return entity.Student{}, fmt.Errorf("StudentRepo - PatchStudent - r.WithinTx: %w", err)
+	}
+
+	return student, nil
+}
+
+// DeleteStudent soft-deletes a student by ID and withdraws it from its group, a non-zero version must match the stored one
+func (r *StudentRepo) DeleteStudent(ctx context.Context, id, version int) error {
+	sql, args, err := whereVersion(r.Builder.
+		Update("students").
+		Set("deleted_at", squirrel.Expr("now()")).
+		Set("updated_at", squirrel.Expr("now()")).
+		Set("version", squirrel.Expr("version + 1")).
 		Where("id = ?", id).
+		Where(_notDeleted), version).
 		ToSql()
 	if err != nil {
 		return fmt.Errorf("StudentRepo - DeleteStudent - r.Builder: %w", err)
 	}
 
-	_, err = r.Pool.Exec(ctx, sql, args...)
+	err = r.WithinTx(ctx, func(ctx context.Context) error {
+		tag, err := r.Conn(ctx).Exec(ctx, sql, args...)
+		if err != nil {
+			return fmt.Errorf("r.Conn.Exec: %w", mapError(err, "student"))
+		}
+
+		if tag.RowsAffected() == 0 {
+			return missedWrite(ctx, r.Postgres, "students", "student", id)
+		}
+
+		return enroll(ctx, r.Postgres, entity.EnrollmentWithdrawn, id)
+	})
 	if err != nil {
-		return fmt.Errorf("StudentRepo - DeleteStudent - r.Pool.Exec: %w", err)
+		return fmt.Errorf("StudentRepo - DeleteStudent - r.WithinTx: %w", err)
 	}
 
 	return nil
 }
 
-// SearchStudents searches for students by name or group name
-func (r *StudentRepo) SearchStudents(ctx context.Context, query string) ([]entity.Student, error) {
+// RestoreStudent undoes the soft delete of a student, enrolls it in its group again and returns it,
+// the student cannot be restored into a group that has been deleted
+func (r *StudentRepo) RestoreStudent(ctx context.Context, id int) (entity.Student, error) {
 	sql, args, err := r.Builder.
-		Select("s.id", "s.name", "s.group_id").
-		From("students s").
-		Join("groups g ON s.group_id = g.id").
-		Where("LOWER(s.name) LIKE LOWER(?) OR LOWER(g.name) LIKE LOWER(?)", "%"+query+"%", "%"+query+"%").
+		Update("students").
+		Set("deleted_at", nil).
+		Set("updated_at", squirrel.Expr("now()")).
+		Set("version", squirrel.Expr("version + 1")).
+		Where("id = ?", id).
+		Where("deleted_at IS NOT NULL").
+		Where("group_id IN (SELECT id FROM groups WHERE deleted_at IS NULL)").
+		Suffix("RETURNING " + strings.Join(_studentColumns, ", ")).
 		ToSql()
 	if err != nil {
-		return nil, fmt.Errorf("StudentRepo - SearchStudents - r.Builder: %w", err)
+		return entity.Student{}, fmt.Errorf("StudentRepo - RestoreStudent - r.Builder: %w", err)
+	}
+
+	var student entity.Student
+
+	err = r.WithinTx(ctx, func(ctx context.Context) error {
+		err := r.Conn(ctx).QueryRow(ctx, sql, args...).Scan(studentFields(&student)...)
+		if err != nil {
+			return fmt.Errorf("r.Conn.QueryRow: %w", err)
+		}
+
+		return enroll(ctx, r.Postgres, entity.EnrollmentRestored, id)
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		if err = missedRestore(ctx, r.Postgres, "students", "student", id, entity.ErrGroupNotFound); err != nil {
+			return entity.Student{}, fmt.Errorf("StudentRepo - RestoreStudent: %w", err)
+		}
+
+		// Not deleted, nothing to restore
+		return r.GetStudentByID(ctx, id)
 	}
 
-	rows, err := r.Pool.Query(ctx, sql, args...)
 	if err != nil {
-		return nil, fmt.Errorf("StudentRepo - SearchStudents - r.Pool.Query: %w", err)
+		return entity.Student{}, fmt.Errorf("StudentRepo - RestoreStudent - r.WithinTx: %w", mapError(err, "student"))
 	}
-	defer rows.Close()
 
-	var students []entity.Student
-	for rows.Next() {
-		var s entity.Student
-		if err := rows.Scan(&s.ID, &s.Name, &s.GroupID); err != nil {
-			return nil, fmt.Errorf("StudentRepo - SearchStudents - rows.Scan: %w", err)
-		}
-		students = append(students, s)
+	return student, nil
+}
+
+// SearchStudents searches for students by name or group name, best matches first
+func (r *StudentRepo) SearchStudents(ctx context.Context, query entity.SearchQuery) ([]entity.StudentHit, error) {
+	tsq := tsQuery(query.Terms, query.Mode != entity.SearchExact)
+	if tsq == "" {
+		return nil, nil
+	}
+
+	b := r.Builder.
+		Select(qualified("s", _studentColumns)...).
+		Column("ts_headline('simple', s.name, q, '"+_headlineOptions+"')").
+		From("students s").
+		Join("groups g ON g.id = s.group_id").
+		JoinClause("CROSS JOIN to_tsquery('simple', ?) q", tsq).
+		OrderBy("rank DESC", "s.id").
+		Limit(uint64(query.Limit)) //nolint:gosec // limit is validated by the caller
+
+	if query.Mode == entity.SearchFuzzy {
+		match, rank := similarity(query.Terms, "s.name", "g.name")
+		b = b.Column(rank).Where(match)
+	} else {
+		b = b.Column("GREATEST(ts_rank(s.search, q), ts_rank(g.search, q))::float8 AS rank").
+			Where("(s.search @@ q OR g.search @@ q)")
+	}
+
+	if !query.IncludeDeleted {
+		b = b.Where("s.deleted_at IS NULL")
 	}
 
-	return students, nil
+	if query.Scope != nil {
+		b = b.Where(inScope("s.group_id", query.Scope))
+	}
+
+	hits, err := search(ctx, r.Postgres, b, func(row pgx.CollectableRow) (entity.StudentHit, error) {
+		var h entity.StudentHit
+		err := row.Scan(append(studentFields(&h.Student), &h.Highlight, &h.Rank)...)
+
+		return h, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("StudentRepo - SearchStudents - search: %w", err)
+	}
+
+	return hits, nil
 }
 
 // CreateGroup creates a new group
@@ -170,210 +500,834 @@ func (r *GroupRepo) CreateGroup(ctx context.Context, group entity.Group) (entity
 		Insert("groups").
 		Columns("name", "parent_id").
 		Values(group.Name, group.ParentID).
-		Suffix("RETURNING id").
+		Suffix("RETURNING id, version, created_at, updated_at").
 		ToSql()
 	if err != nil {
 		return entity.Group{}, fmt.Errorf("GroupRepo - CreateGroup - r.Builder: %w", err)
 	}
 
-	err = r.Pool.QueryRow(ctx, sql, args...).Scan(&group.ID)
+	err = r.Conn(ctx).QueryRow(ctx, sql, args...).Scan(&group.ID, &group.Version, &group.CreatedAt, &group.UpdatedAt)
 	if err != nil {
-		return entity.Group{}, fmt.Errorf("GroupRepo - CreateGroup - r.Pool.QueryRow: %w", err)
+		return entity.Group{}, fmt.Errorf("GroupRepo - CreateGroup - r.Conn.QueryRow: %w", mapError(err, "group"))
 	}
 
 	return group, nil
 }
 
-// GetGroups retrieves all groups with their subgroups
-func (r *GroupRepo) GetGroups(ctx context.Context) ([]entity.Group, error) {
-	// First get all root groups (those without parent)
-	sql, _, err := r.Builder.
-		Select("id", "name", "parent_id").
-		From("groups").
-		Where("parent_id IS NULL").
-		ToSql()
+// _groupColumns are the columns scanned by groupFields, in the same order.
+var _groupColumns = []string{"id", "name", "parent_id", "version", "created_at", "updated_at", "deleted_at"} //nolint:gochecknoglobals // column list
+
+// groupFields returns scan destinations for _groupColumns.
+func groupFields(g *entity.Group) []any {
+	return []any{&g.ID, &g.Name, &g.ParentID, &g.Version, &g.CreatedAt, &g.UpdatedAt, &g.DeletedAt}
+}
+
+// _groupSortColumns maps sort fields accepted for groups to their columns.
+var _groupSortColumns = map[string]sortColumn{ //nolint:gochecknoglobals // lookup table
+	entity.SortByID:   {name: "id", numeric: true},
+	entity.SortByName: {name: "name"},
+}
+
+// groupKey returns the sort key value and id of a group for cursor building.
+func groupKey(sort string) func(entity.Group) (string, int) {
+	return func(g entity.Group) (string, int) {
+		if sort == entity.SortByName {
+			return g.Name, g.ID
+		}
+
+		return strconv.Itoa(g.ID), g.ID
+	}
+}
+
+// filterGroups applies the group filter to a query over the groups table.
+func filterGroups(b squirrel.SelectBuilder, filter entity.GroupFilter) squirrel.SelectBuilder {
+	if !filter.IncludeDeleted {
+		b = b.Where(_notDeleted)
+	}
+
+	switch {
+	case filter.ParentID != nil:
+		return b.Where("parent_id = ?", *filter.ParentID)
+	case filter.Scope != nil:
+		return b.Where("id = ANY(?)", filter.Scope)
+	default:
+		return b.Where("parent_id IS NULL")
+	}
+}
+
+// GetGroups retrieves one page of top-level groups with their subgroups down to filter.MaxDepth levels
+func (r *GroupRepo) GetGroups(ctx context.Context, filter entity.GroupFilter, page entity.PageRequest) (entity.Page[entity.Group], error) {
+	column, ok := _groupSortColumns[page.Sort]
+	if !ok {
+		column = _groupSortColumns[entity.SortByID]
+	}
+
+	sql, args, err := filterGroups(r.Builder.Select("COUNT(*)").From("groups"), filter).ToSql()
 	if err != nil {
-		return nil, fmt.Errorf("GroupRepo - GetGroups - r.Builder: %w", err)
+		return entity.Page[entity.Group]{}, fmt.Errorf("GroupRepo - GetGroups - r.Builder: %w", err)
 	}
 
-	rows, err := r.Pool.Query(ctx, sql)
+	var result entity.Page[entity.Group]
+	if err = r.Conn(ctx).QueryRow(ctx, sql, args...).Scan(&result.Total); err != nil {
+		return entity.Page[entity.Group]{}, fmt.Errorf("GroupRepo - GetGroups - r.Conn.QueryRow: %w", err)
+	}
+
+	b, err := keyset(filterGroups(r.Builder.Select(_groupColumns...).From("groups"), filter), column, page)
 	if err != nil {
-		return nil, fmt.Errorf("GroupRepo - GetGroups - r.Pool.Query: %w", err)
+		return entity.Page[entity.Group]{}, fmt.Errorf("GroupRepo - GetGroups - keyset: %w", err)
+	}
+
+	sql, args, err = b.ToSql()
+	if err != nil {
+		return entity.Page[entity.Group]{}, fmt.Errorf("GroupRepo - GetGroups - r.Builder: %w", err)
+	}
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return entity.Page[entity.Group]{}, fmt.Errorf("GroupRepo - GetGroups - r.Conn.Query: %w", err)
 	}
 	defer rows.Close()
 
-	var rootGroups []entity.Group
+	groups := make([]entity.Group, 0, page.Limit+1)
 	for rows.Next() {
 		var g entity.Group
-		var parentID *int
-		if err := rows.Scan(&g.ID, &g.Name, &parentID); err != nil {
-			return nil, fmt.Errorf("GroupRepo - GetGroups - rows.Scan: %w", err)
+		if err := rows.Scan(groupFields(&g)...); err != nil {
+			return entity.Page[entity.Group]{}, fmt.Errorf("GroupRepo - GetGroups - rows.Scan: %w", err)
 		}
-		g.ParentID = parentID
+		groups = append(groups, g)
+	}
 
-		// For each root group, get its subgroups recursively
-		fullGroup, err := r.GetGroupWithSubgroups(ctx, g.ID)
-		if err != nil {
-			return nil, err
-		}
-		rootGroups = append(rootGroups, fullGroup)
+	if err := rows.Err(); err != nil {
+		return entity.Page[entity.Group]{}, fmt.Errorf("GroupRepo - GetGroups - rows.Err: %w", err)
+	}
+
+	result.Items, result.NextCursor = nextCursor(groups, page, groupKey(page.Sort))
+
+	if filter.MaxDepth == 0 || len(result.Items) == 0 {
+		return result, nil
+	}
+
+	ids := make([]int, 0, len(result.Items))
+	for _, g := range result.Items {
+		ids = append(ids, g.ID)
+	}
+
+	// Load the subgroups of the whole page at once
+	trees, err := r.loadSubtrees(ctx, ids, filter.MaxDepth)
+	if err != nil {
+		return entity.Page[entity.Group]{}, err
 	}
 
-	return rootGroups, nil
+	for i, g := range result.Items {
+		result.Items[i].SubGroups = trees[g.ID].SubGroups
+		result.Items[i].StudentCount = trees[g.ID].StudentCount
+	}
+
+	return result, nil
 }
 
-// GetGroupByID retrieves a group by ID
+// GetGroupByID retrieves an active group by ID
 func (r *GroupRepo) GetGroupByID(ctx context.Context, id int) (entity.Group, error) {
 	sql, args, err := r.Builder.
-		Select("id", "name", "parent_id").
+		Select(_groupColumns...).
 		From("groups").
 		Where("id = ?", id).
+		Where(_notDeleted).
 		ToSql()
 	if err != nil {
 		return entity.Group{}, fmt.Errorf("GroupRepo - GetGroupByID - r.Builder: %w", err)
 	}
 
 	var group entity.Group
-	var parentID *int
-	err = r.Pool.QueryRow(ctx, sql, args...).Scan(&group.ID, &group.Name, &parentID)
+	err = r.Conn(ctx).QueryRow(ctx, sql, args...).Scan(groupFields(&group)...)
 	if err != nil {
-		return entity.Group{}, fmt.Errorf("GroupRepo - GetGroupByID - r.Pool.QueryRow: %w", err)
+		return entity.Group{}, fmt.Errorf("GroupRepo - GetGroupByID - r.Conn.QueryRow: %w", mapError(err, "group"))
 	}
-	group.ParentID = parentID
 
 	return group, nil
 }
 
-// GetGroupWithSubgroups retrieves a group with all its subgroups recursively
-func (r *GroupRepo) GetGroupWithSubgroups(ctx context.Context, id int) (entity.Group, error) {
-	// First get the group itself
-	group, err := r.GetGroupByID(ctx, id)
+// _subtreeCTE selects the requested active groups and their active descendants down to a depth limit,
+// a negative limit loads whole subtrees. The path guards against cycles in existing data.
+const _subtreeCTE = `WITH RECURSIVE tree AS (
+	SELECT id, name, parent_id, version, created_at, updated_at, deleted_at, 0 AS depth, ARRAY[id] AS path
+	FROM groups
+	WHERE id = ANY(?) AND deleted_at IS NULL
+	UNION ALL
+	SELECT g.id, g.name, g.parent_id, g.version, g.created_at, g.updated_at, g.deleted_at, t.depth + 1, t.path || g.id
+	FROM groups g
+	JOIN tree t ON g.parent_id = t.id
+	WHERE NOT g.id = ANY(t.path) AND g.deleted_at IS NULL AND (? < 0 OR t.depth < ?)
+)`
+
+// inScope matches the rows whose group, held in column, is one of the scope groups or one of their active subgroups.
+func inScope(column string, scope []int) squirrel.Sqlizer {
+	return squirrel.Expr(column+" IN ("+_subtreeCTE+" SELECT id FROM tree)", scope, entity.UnlimitedDepth, entity.UnlimitedDepth)
+}
+
+// _treeStudentCounts count the active students of each group selected by _subtreeCTE, directly in the group
+// and in its whole active subtree, which may reach below the depth limit of the tree.
+const (
+	_treeDirectStudents = "(SELECT COUNT(*) FROM students s WHERE s.group_id = tree.id AND s.deleted_at IS NULL)"
+	_treeTotalStudents  = `(WITH RECURSIVE sub AS (
+		SELECT tree.id AS id
+		UNION
+		SELECT g.id FROM groups g JOIN sub ON g.parent_id = sub.id WHERE g.deleted_at IS NULL
+	) SELECT COUNT(*) FROM students s JOIN sub ON s.group_id = sub.id WHERE s.deleted_at IS NULL)`
+)
+
+// loadSubtrees loads the given groups with their subgroups and student counts in a single query
+// and assembles the trees in memory. Groups that do not exist are absent from the result.
+func (r *GroupRepo) loadSubtrees(ctx context.Context, ids []int, maxDepth int) (map[int]entity.Group, error) {
+	sql, args, err := r.Builder.
+		Select(append(_groupColumns, "depth", _treeDirectStudents, _treeTotalStudents)...).
+		Prefix(_subtreeCTE, ids, maxDepth, maxDepth).
+		From("tree").
+		OrderBy("depth", "id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("GroupRepo - loadSubtrees - r.Builder: %w", err)
+	}
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("GroupRepo - loadSubtrees - r.Conn.Query: %w", err)
+	}
+	defer rows.Close()
+
+	nodes := make(map[int]entity.Group)
+	children := make(map[int][]int)
+
+	for rows.Next() {
+		var (
+			g      entity.Group
+			depth  int
+			counts entity.StudentCount
+		)
+
+		if err := rows.Scan(append(groupFields(&g), &depth, &counts.Direct, &counts.Total)...); err != nil {
+			return nil, fmt.Errorf("GroupRepo - loadSubtrees - rows.Scan: %w", err)
+		}
+
+		g.StudentCount = &counts
+
+		nodes[g.ID] = g
+
+		// Requested groups are roots of their trees even when their parent is loaded too
+		if depth > 0 {
+			children[*g.ParentID] = append(children[*g.ParentID], g.ID)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("GroupRepo - loadSubtrees - rows.Err: %w", err)
+	}
+
+	trees := make(map[int]entity.Group, len(ids))
+	for _, id := range ids {
+		if _, ok := nodes[id]; ok {
+			trees[id] = assembleTree(id, nodes, children)
+		}
+	}
+
+	return trees, nil
+}
+
+// assembleTree builds the group with the given id and its loaded descendants.
+func assembleTree(id int, nodes map[int]entity.Group, children map[int][]int) entity.Group {
+	g := nodes[id]
+	for _, childID := range children[id] {
+		g.SubGroups = append(g.SubGroups, assembleTree(childID, nodes, children))
+	}
+
+	return g
+}
+
+// GetGroupWithSubgroups retrieves a group with its subgroups down to maxDepth levels,
+// entity.UnlimitedDepth loads the whole subtree
+func (r *GroupRepo) GetGroupWithSubgroups(ctx context.Context, id, maxDepth int) (entity.Group, error) {
+	trees, err := r.loadSubtrees(ctx, []int{id}, maxDepth)
 	if err != nil {
 		return entity.Group{}, err
 	}
 
-	// Then get all direct subgroups
+	group, ok := trees[id]
+	if !ok {
+		return entity.Group{}, fmt.Errorf("GroupRepo - GetGroupWithSubgroups: %w", notFound("group"))
+	}
+
+	return group, nil
+}
+
+// _ancestorsCTE selects an active group and its active ancestors up to the root.
+// The path guards against cycles in existing data.
+const _ancestorsCTE = `WITH RECURSIVE ancestors AS (
+	SELECT id, name, parent_id, 0 AS depth, ARRAY[id] AS path
+	FROM groups
+	WHERE id = ? AND deleted_at IS NULL
+	UNION ALL
+	SELECT g.id, g.name, g.parent_id, a.depth + 1, a.path || g.id
+	FROM groups g
+	JOIN ancestors a ON g.id = a.parent_id
+	WHERE NOT g.id = ANY(a.path) AND g.deleted_at IS NULL
+)`
+
+// GetAncestors retrieves a group followed by its ancestors up to the root,
+// the result is empty when the group does not exist or has been deleted
+func (r *GroupRepo) GetAncestors(ctx context.Context, id int) ([]entity.Group, error) {
 	sql, args, err := r.Builder.
 		Select("id", "name", "parent_id").
-		From("groups").
-		Where("parent_id = ?", id).
+		Prefix(_ancestorsCTE, id).
+		From("ancestors").
+		OrderBy("depth").
 		ToSql()
 	if err != nil {
-		return entity.Group{}, fmt.Errorf("GroupRepo - GetGroupWithSubgroups - r.Builder: %w", err)
+		return nil, fmt.Errorf("GroupRepo - GetAncestors - r.Builder: %w", err)
 	}
 
-	rows, err := r.Pool.Query(ctx, sql, args...)
+	rows, err := r.Conn(ctx).Query(ctx, sql, args...)
 	if err != nil {
-		return entity.Group{}, fmt.Errorf("GroupRepo - GetGroupWithSubgroups - r.Pool.Query: %w", err)
+		return nil, fmt.Errorf("GroupRepo - GetAncestors - r.Conn.Query: %w", err)
 	}
 	defer rows.Close()
 
-	// For each subgroup, recursively get its subgroups
+	var groups []entity.Group
 	for rows.Next() {
-		var subGroup entity.Group
-		var parentID *int
-		if err := rows.Scan(&subGroup.ID, &subGroup.Name, &parentID); err != nil {
-			return entity.Group{}, fmt.Errorf("GroupRepo - GetGroupWithSubgroups - rows.Scan: %w", err)
+		var g entity.Group
+		if err := rows.Scan(&g.ID, &g.Name, &g.ParentID); err != nil {
+			return nil, fmt.Errorf("GroupRepo - GetAncestors - rows.Scan: %w", err)
 		}
-		subGroup.ParentID = parentID
+		groups = append(groups, g)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("GroupRepo - GetAncestors - rows.Err: %w", err)
+	}
+
+	return groups, nil
+}
+
+// _treeLockKey identifies the advisory lock serializing changes to the structure of the group tree.
+const _treeLockKey = 0x67726f757073 // "groups"
+
+// LockTree locks the structure of the group tree until the end of the transaction, so that a parent checked
+// against the ancestors read after the lock stays valid until the change is committed
+func (r *GroupRepo) LockTree(ctx context.Context) error {
+	if _, err := r.Conn(ctx).Exec(ctx, "SELECT pg_advisory_xact_lock($1)", _treeLockKey); err != nil {
+		return fmt.Errorf("GroupRepo - LockTree - r.Conn.Exec: %w", err)
+	}
+
+	return nil
+}
+
+// _pathsCTE selects every active group below an active root with the ids and names of its ancestors.
+// The ids guard against cycles in existing data.
+const _pathsCTE = `WITH RECURSIVE paths AS (
+	SELECT id, name, parent_id, version, created_at, updated_at, deleted_at, ARRAY[id] AS ids, ARRAY[name::text] AS path
+	FROM groups
+	WHERE parent_id IS NULL AND deleted_at IS NULL
+	UNION ALL
+	SELECT g.id, g.name, g.parent_id, g.version, g.created_at, g.updated_at, g.deleted_at, p.ids || g.id, p.path || g.name::text
+	FROM groups g
+	JOIN paths p ON g.parent_id = p.id
+	WHERE NOT g.id = ANY(p.ids) AND g.deleted_at IS NULL
+)`
+
+// ExportGroups streams the group tree depth-first, ordered by name, calling yield for each group
+// in the scope of the filter. The depth of the export is read by the same query, so it matches the groups.
+func (r *GroupRepo) ExportGroups(ctx context.Context, filter entity.GroupFilter, yield func(entity.GroupPath) error) error {
+	b := r.Builder.
+		Select(append(_groupColumns, "path", "MAX(cardinality(ids)) OVER ()")...).
+		Prefix(_pathsCTE).
+		From("paths").
+		OrderBy("path", "ids")
+
+	if filter.Scope != nil {
+		b = b.Where(inScope("id", filter.Scope))
+	}
+
+	sql, args, err := b.ToSql()
+	if err != nil {
+		return fmt.Errorf("GroupRepo - ExportGroups - r.Builder: %w", err)
+	}
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("GroupRepo - ExportGroups - r.Conn.Query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var g entity.GroupPath
+		if err := rows.Scan(append(groupFields(&g.Group), &g.Path, &g.Levels)...); err != nil {
+			return fmt.Errorf("GroupRepo - ExportGroups - rows.Scan: %w", err)
+		}
+
+		if err := yield(g); err != nil {
+			return fmt.Errorf("GroupRepo - ExportGroups - yield: %w", err)
+		}
+	}
 
-		// Recursively get subgroups
-		fullSubGroup, err := r.GetGroupWithSubgroups(ctx, subGroup.ID)
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("GroupRepo - ExportGroups - rows.Err: %w", err)
+	}
+
+	return nil
+}
+
+// _statsCTE extends _pathsCTE with the student and subgroup counts of every active group below an active root,
+// a group is in the subtree of every group in its ids.
+const _statsCTE = _pathsCTE + `, counts AS (
+	SELECT group_id, COUNT(*) AS students
+	FROM students
+	WHERE deleted_at IS NULL
+	GROUP BY group_id
+), stats AS (
+	SELECT p.id, p.name, p.parent_id, p.ids, cardinality(p.ids) - 1 AS depth,
+		COALESCE(c.students, 0) AS direct_students,
+		(SELECT COALESCE(SUM(dc.students), 0)::int FROM paths d JOIN counts dc ON dc.group_id = d.id WHERE p.id = ANY(d.ids)) AS total_students,
+		(SELECT COUNT(*) FROM paths d WHERE d.parent_id = p.id) AS direct_subgroups,
+		(SELECT COUNT(*) - 1 FROM paths d WHERE p.id = ANY(d.ids)) AS total_subgroups
+	FROM paths p
+	LEFT JOIN counts c ON c.group_id = p.id
+)`
+
+// GroupStats computes the statistics of every active group below an active root, parents before their subgroups,
+// or only of the group with the given id
+func (r *GroupRepo) GroupStats(ctx context.Context, id *int) ([]entity.GroupStats, error) {
+	b := r.Builder.
+		Select("id", "parent_id", "name", "depth", "direct_students", "total_students",
+			"direct_subgroups", "total_subgroups", "direct_subgroups = 0", "total_students = 0").
+		Prefix(_statsCTE).
+		From("stats").
+		OrderBy("ids")
+
+	if id != nil {
+		b = b.Where("id = ?", *id)
+	}
+
+	sql, args, err := b.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("GroupRepo - GroupStats - r.Builder: %w", err)
+	}
+
+	rows, err := r.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("GroupRepo - GroupStats - r.Conn.Query: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []entity.GroupStats
+	for rows.Next() {
+		var s entity.GroupStats
+		err := rows.Scan(&s.ID, &s.ParentID, &s.Name, &s.Depth, &s.DirectStudents, &s.TotalStudents,
+			&s.DirectSubgroups, &s.TotalSubgroups, &s.Leaf, &s.Empty)
 		if err != nil {
-			return entity.Group{}, err
+			return nil, fmt.Errorf("GroupRepo - GroupStats - rows.Scan: %w", err)
 		}
+		stats = append(stats, s)
+	}
 
-		group.SubGroups = append(group.SubGroups, fullSubGroup)
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("GroupRepo - GroupStats - rows.Err: %w", err)
 	}
 
-	return group, nil
+	return stats, nil
 }
 
-// UpdateGroup updates an existing group
-func (r *GroupRepo) UpdateGroup(ctx context.Context, group entity.Group) error {
-	sql, args, err := r.Builder.
+// UpdateGroup updates an active group and returns the stored group, a non-zero Version must match the stored one
+func (r *GroupRepo) UpdateGroup(ctx context.Context, group entity.Group) (entity.Group, error) {
+	sql, args, err := whereVersion(r.Builder.
 		Update("groups").
 		Set("name", group.Name).
 		Set("parent_id", group.ParentID).
+		Set("version", squirrel.Expr("version + 1")).
+		Set("updated_at", squirrel.Expr("now()")).
 		Where("id = ?", group.ID).
+		Where(_notDeleted), group.Version).
+		Suffix("RETURNING " + strings.Join(_groupColumns, ", ")).
 		ToSql()
 	if err != nil {
-		return fmt.Errorf("GroupRepo - UpdateGroup - r.Builder: %w", err)
+		return entity.Group{}, fmt.Errorf("GroupRepo - UpdateGroup - r.Builder: %w", err)
+	}
+
+	var updated entity.Group
+	err = r.Conn(ctx).QueryRow(ctx, sql, args...).Scan(groupFields(&updated)...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.Group{}, fmt.Errorf("GroupRepo - UpdateGroup: %w", missedWrite(ctx, r.Postgres, "groups", "group", group.ID))
 	}
 
-	_, err = r.Pool.Exec(ctx, sql, args...)
 	if err != nil {
-		return fmt.Errorf("GroupRepo - UpdateGroup - r.Pool.Exec: %w", err)
+		return entity.Group{}, fmt.Errorf("GroupRepo - UpdateGroup - r.Conn.QueryRow: %w", mapError(err, "group"))
 	}
 
-	return nil
+	return updated, nil
 }
 
-// DeleteGroup deletes a group by ID
-func (r *GroupRepo) DeleteGroup(ctx context.Context, id int) error {
-	sql, args, err := r.Builder.
-		Delete("groups").
+// PatchGroup updates only the fields present in the patch and returns the updated group,
+// a non-zero patch Version must match the stored one
+func (r *GroupRepo) PatchGroup(ctx context.Context, id int, patch entity.GroupPatch) (entity.Group, error) {
+	// Nothing to change
+	if patch.Name == nil && !patch.SetParent {
+		group, err := r.GetGroupByID(ctx, id)
+		if err == nil && patch.Version != 0 && patch.Version != group.Version {
+			return entity.Group{}, fmt.Errorf("GroupRepo - PatchGroup: %w", entity.ErrVersionMismatch)
+		}
+
+		return group, err
+	}
+
+	b := r.Builder.Update("groups").
+		Set("version", squirrel.Expr("version + 1")).
+		Set("updated_at", squirrel.Expr("now()"))
+
+	if patch.Name != nil {
+		b = b.Set("name", *patch.Name)
+	}
+
+	if patch.SetParent {
+		b = b.Set("parent_id", patch.ParentID)
+	}
+
+	sql, args, err := whereVersion(b.Where("id = ?", id).Where(_notDeleted), patch.Version).
+		Suffix("RETURNING " + strings.Join(_groupColumns, ", ")).
+		ToSql()
+	if err != nil {
+		return entity.Group{}, fmt.Errorf("GroupRepo - PatchGroup - r.Builder: %w", err)
+	}
+
+	var group entity.Group
+	err = r.Conn(ctx).QueryRow(ctx, sql, args...).Scan(groupFields(&group)...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.Group{}, fmt.Errorf("GroupRepo - PatchGroup: %w", missedWrite(ctx, r.Postgres, "groups", "group", id))
+	}
+
+	if err != nil {
+		return entity.Group{}, fmt.Errorf("GroupRepo - PatchGroup - r.Conn.QueryRow: %w", mapError(err, "group"))
+	}
+
+	return group, nil
+}
+
+// DeleteGroup soft-deletes a group by ID, a non-zero version must match the stored one.
+// Its subgroups and students are left alone, the use case moves or deletes them first.
+func (r *GroupRepo) DeleteGroup(ctx context.Context, id, version int) error {
+	sql, args, err := whereVersion(r.Builder.
+		Update("groups").
+		Set("deleted_at", squirrel.Expr("now()")).
+		Set("updated_at", squirrel.Expr("now()")).
+		Set("version", squirrel.Expr("version + 1")).
 		Where("id = ?", id).
+		Where(_notDeleted), version).
 		ToSql()
 	if err != nil {
 		return fmt.Errorf("GroupRepo - DeleteGroup - r.Builder: %w", err)
 	}
 
-	_, err = r.Pool.Exec(ctx, sql, args...)
+	tag, err := r.Conn(ctx).Exec(ctx, sql, args...)
 	if err != nil {
-		return fmt.Errorf("GroupRepo - DeleteGroup - r.Pool.Exec: %w", err)
+		return fmt.Errorf("GroupRepo - DeleteGroup - r.Conn.Exec: %w", mapError(err, "group"))
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("GroupRepo - DeleteGroup: %w", missedWrite(ctx, r.Postgres, "groups", "group", id))
 	}
 
 	return nil
 }
 
-// SearchGroups searches for groups by name
-func (r *GroupRepo) SearchGroups(ctx context.Context, query string) ([]entity.Group, error) {
+// DeleteDescendants soft-deletes the active subgroups of a group at any depth together with the active students
+// of the group and its subgroups, the group itself is left alone. The enrollments of the deleted students end.
+// It returns how many subgroups were deleted and the IDs of the deleted students
+func (r *GroupRepo) DeleteDescendants(ctx context.Context, id int) (subgroups int, students []int, err error) {
 	sql, args, err := r.Builder.
-		Select("id", "name", "parent_id").
+		Update("students").
+		Prefix(_subtreeCTE, []int{id}, entity.UnlimitedDepth, entity.UnlimitedDepth).
+		Set("deleted_at", squirrel.Expr("now()")).
+		Set("updated_at", squirrel.Expr("now()")).
+		Set("version", squirrel.Expr("version + 1")).
+		Where("group_id IN (SELECT id FROM tree)").
+		Where(_notDeleted).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		return 0, nil, fmt.Errorf("GroupRepo - DeleteDescendants - r.Builder: %w", err)
+	}
+
+	students, err = r.returnedIDs(ctx, sql, args)
+	if err != nil {
+		return 0, nil, fmt.Errorf("GroupRepo - DeleteDescendants - r.returnedIDs: %w", err)
+	}
+
+	if err = enroll(ctx, r.Postgres, entity.EnrollmentGroupDeleted, students...); err != nil {
+		return 0, nil, fmt.Errorf("GroupRepo - DeleteDescendants - enroll: %w", err)
+	}
+
+	sql, args, err = r.Builder.
+		Update("groups").
+		Prefix(_subtreeCTE, []int{id}, entity.UnlimitedDepth, entity.UnlimitedDepth).
+		Set("deleted_at", squirrel.Expr("now()")).
+		Set("updated_at", squirrel.Expr("now()")).
+		Set("version", squirrel.Expr("version + 1")).
+		Where("id IN (SELECT id FROM tree)").
+		Where("id <> ?", id).
+		Where(_notDeleted).
+		ToSql()
+	if err != nil {
+		return 0, nil, fmt.Errorf("GroupRepo - DeleteDescendants - r.Builder: %w", err)
+	}
+
+	tag, err := r.Conn(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return 0, nil, fmt.Errorf("GroupRepo - DeleteDescendants - r.Conn.Exec: %w", err)
+	}
+
+	return int(tag.RowsAffected()), students, nil
+}
+
+// RestoreGroup undoes the soft delete of a group and returns it,
+// the group cannot be restored under a parent that has been deleted
+func (r *GroupRepo) RestoreGroup(ctx context.Context, id int) (entity.Group, error) {
+	sql, args, err := r.Builder.
+		Update("groups").
+		Set("deleted_at", nil).
+		Set("updated_at", squirrel.Expr("now()")).
+		Set("version", squirrel.Expr("version + 1")).
+		Where("id = ?", id).
+		Where("deleted_at IS NOT NULL").
+		Where("(parent_id IS NULL OR parent_id IN (SELECT p.id FROM groups p WHERE p.deleted_at IS NULL))").
+		Suffix("RETURNING " + strings.Join(_groupColumns, ", ")).
+		ToSql()
+	if err != nil {
+		return entity.Group{}, fmt.Errorf("GroupRepo - RestoreGroup - r.Builder: %w", err)
+	}
+
+	var group entity.Group
+	err = r.Conn(ctx).QueryRow(ctx, sql, args...).Scan(groupFields(&group)...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		if err = missedRestore(ctx, r.Postgres, "groups", "group", id, entity.ErrParentNotFound); err != nil {
+			return entity.Group{}, fmt.Errorf("GroupRepo - RestoreGroup: %w", err)
+		}
+
+		// Not deleted, nothing to restore
+		return r.GetGroupByID(ctx, id)
+	}
+
+	if err != nil {
+		return entity.Group{}, fmt.Errorf("GroupRepo - RestoreGroup - r.Conn.QueryRow: %w", mapError(err, "group"))
+	}
+
+	return group, nil
+}
+
+// SearchGroups searches for groups by name, best matches first
+func (r *GroupRepo) SearchGroups(ctx context.Context, query entity.SearchQuery) ([]entity.GroupHit, error) {
+	tsq := tsQuery(query.Terms, query.Mode != entity.SearchExact)
+	if tsq == "" {
+		return nil, nil
+	}
+
+	b := r.Builder.
+		Select(qualified("g", _groupColumns)...).
+		Column("ts_headline('simple', g.name, q, '"+_headlineOptions+"')").
+		From("groups g").
+		JoinClause("CROSS JOIN to_tsquery('simple', ?) q", tsq).
+		OrderBy("rank DESC", "g.id").
+		Limit(uint64(query.Limit)) //nolint:gosec // limit is validated by the caller
+
+	if query.Mode == entity.SearchFuzzy {
+		match, rank := similarity(query.Terms, "g.name")
+		b = b.Column(rank).Where(match)
+	} else {
+		b = b.Column("ts_rank(g.search, q)::float8 AS rank").Where("g.search @@ q")
+	}
+
+	if !query.IncludeDeleted {
+		b = b.Where("g.deleted_at IS NULL")
+	}
+
+	if query.Scope != nil {
+		b = b.Where(inScope("g.id", query.Scope))
+	}
+
+	hits, err := search(ctx, r.Postgres, b, func(row pgx.CollectableRow) (entity.GroupHit, error) {
+		var h entity.GroupHit
+		err := row.Scan(append(groupFields(&h.Group), &h.Highlight, &h.Rank)...)
+
+		return h, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("GroupRepo - SearchGroups - search: %w", err)
+	}
+
+	return hits, nil
+}
+
+// FindGroups retrieves the active groups with any of the IDs or, ignoring case, any of the names
+func (r *GroupRepo) FindGroups(ctx context.Context, ids []int, names []string) ([]entity.Group, error) {
+	lowered := make([]string, len(names))
+	for i, name := range names {
+		lowered[i] = strings.ToLower(name)
+	}
+
+	sql, args, err := r.Builder.
+		Select(_groupColumns...).
 		From("groups").
-		Where("LOWER(name) LIKE LOWER(?)", "%"+query+"%").
+		Where("(id = ANY(?) OR LOWER(name) = ANY(?))", ids, lowered).
+		Where(_notDeleted).
+		OrderBy("id").
 		ToSql()
 	if err != nil {
-		return nil, fmt.Errorf("GroupRepo - SearchGroups - r.Builder: %w", err)
+		return nil, fmt.Errorf("GroupRepo - FindGroups - r.Builder: %w", err)
 	}
 
-	rows, err := r.Pool.Query(ctx, sql, args...)
+	rows, err := r.Conn(ctx).Query(ctx, sql, args...)
 	if err != nil {
-		return nil, fmt.Errorf("GroupRepo - SearchGroups - r.Pool.Query: %w", err)
+		return nil, fmt.Errorf("GroupRepo - FindGroups - r.Conn.Query: %w", err)
 	}
 	defer rows.Close()
 
 	var groups []entity.Group
 	for rows.Next() {
 		var g entity.Group
-		var parentID *int
-		if err := rows.Scan(&g.ID, &g.Name, &parentID); err != nil {
-			return nil, fmt.Errorf("GroupRepo - SearchGroups - rows.Scan: %w", err)
+		if err := rows.Scan(groupFields(&g)...); err != nil {
+			return nil, fmt.Errorf("GroupRepo - FindGroups - rows.Scan: %w", err)
 		}
-		g.ParentID = parentID
 		groups = append(groups, g)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("GroupRepo - FindGroups - rows.Err: %w", err)
+	}
+
 	return groups, nil
 }
 
-// HasSubgroups checks if a group has any subgroups
+// SubtreeSize counts the active descendants of a group and the active students in its subtree
+func (r *GroupRepo) SubtreeSize(ctx context.Context, id int) (subgroups, students int, err error) {
+	sql, args, err := r.Builder.
+		Select("COUNT(*) - 1").
+		Column("(SELECT COUNT(*) FROM students s WHERE s.group_id IN (SELECT id FROM tree) AND s.deleted_at IS NULL)").
+		Prefix(_subtreeCTE, []int{id}, entity.UnlimitedDepth, entity.UnlimitedDepth).
+		From("tree").
+		ToSql()
+	if err != nil {
+		return 0, 0, fmt.Errorf("GroupRepo - SubtreeSize - r.Builder: %w", err)
+	}
+
+	err = r.Conn(ctx).QueryRow(ctx, sql, args...).Scan(&subgroups, &students)
+	if err != nil {
+		return 0, 0, fmt.Errorf("GroupRepo - SubtreeSize - r.Conn.QueryRow: %w", err)
+	}
+
+	return max(subgroups, 0), students, nil
+}
+
+// MoveStudents moves the active students of a group to another group, regrouping their enrollments,
+// and returns the IDs of the moved students
+func (r *GroupRepo) MoveStudents(ctx context.Context, from, to int) ([]int, error) {
+	sql, args, err := r.Builder.
+		Update("students").
+		Set("group_id", to).
+		Set("version", squirrel.Expr("version + 1")).
+		Set("updated_at", squirrel.Expr("now()")).
+		Where("group_id = ?", from).
+		Where(_notDeleted).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("GroupRepo - MoveStudents - r.Builder: %w", err)
+	}
+
+	studentIDs, err := r.returnedIDs(ctx, sql, args)
+	if err != nil {
+		return nil, fmt.Errorf("GroupRepo - MoveStudents - r.returnedIDs: %w", mapError(err, "student"))
+	}
+
+	if err = enroll(ctx, r.Postgres, entity.EnrollmentRegrouped, studentIDs...); err != nil {
+		return nil, fmt.Errorf("GroupRepo - MoveStudents - enroll: %w", err)
+	}
+
+	return studentIDs, nil
+}
+
+// returnedIDs runs a statement returning the ids of the rows it changed.
+func (r *GroupRepo) returnedIDs(ctx context.Context, sql string, args []any) ([]int, error) {
+	rows, err := r.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("r.Conn.Query: %w", err)
+	}
+
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return nil, fmt.Errorf("pgx.CollectRows: %w", err)
+	}
+
+	return ids, nil
+}
+
+// MoveSubgroups moves the active subgroups of a group under another group and returns how many were moved
+func (r *GroupRepo) MoveSubgroups(ctx context.Context, from, to int) (int, error) {
+	sql, args, err := r.Builder.
+		Update("groups").
+		Set("parent_id", to).
+		Set("version", squirrel.Expr("version + 1")).
+		Set("updated_at", squirrel.Expr("now()")).
+		Where("parent_id = ?", from).
+		Where(_notDeleted).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("GroupRepo - MoveSubgroups - r.Builder: %w", err)
+	}
+
+	tag, err := r.Conn(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return 0, fmt.Errorf("GroupRepo - MoveSubgroups - r.Conn.Exec: %w", mapError(err, "group"))
+	}
+
+	return int(tag.RowsAffected()), nil
+}
+
+// HasSubgroups checks if a group has any active subgroups
 func (r *GroupRepo) HasSubgroups(ctx context.Context, id int) (bool, error) {
 	sql, args, err := r.Builder.
 		Select("COUNT(*)").
 		From("groups").
 		Where("parent_id = ?", id).
+		Where(_notDeleted).
 		ToSql()
 	if err != nil {
 		return false, fmt.Errorf("GroupRepo - HasSubgroups - r.Builder: %w", err)
 	}
 
 	var count int
-	err = r.Pool.QueryRow(ctx, sql, args...).Scan(&count)
+	err = r.Conn(ctx).QueryRow(ctx, sql, args...).Scan(&count)
 	if err != nil {
-		return false, fmt.Errorf("GroupRepo - HasSubgroups - r.Pool.QueryRow: %w", err)
+		return false, fmt.Errorf("GroupRepo - HasSubgroups - r.Conn.QueryRow: %w", err)
 	}
 
 	return count > 0, nil
 }
+
+// HasStudents checks if a group has any active students
+func (r *GroupRepo) HasStudents(ctx context.Context, id int) (bool, error) {
+	sql, args, err := r.Builder.
+		Select("1").
+		From("students").
+		Where("group_id = ?", id).
+		Where(_notDeleted).
+		Prefix("SELECT EXISTS (").
+		Suffix(")").
+		ToSql()
+	if err != nil {
+		return false, fmt.Errorf("GroupRepo - HasStudents - r.Builder: %w", err)
+	}
+
+	var exists bool
+	err = r.Conn(ctx).QueryRow(ctx, sql, args...).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("GroupRepo - HasStudents - r.Conn.QueryRow: %w", err)
+	}
+
+	return exists, nil
+}
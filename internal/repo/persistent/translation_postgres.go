@@ -30,9 +30,9 @@ func (r *TranslationRepo) GetHistory(ctx context.Context) ([]entity.Translation,
 		return nil, fmt.Errorf("TranslationRepo - GetHistory - r.Builder: %w", err)
 	}
 
-	rows, err := r.Pool.Query(ctx, sql)
+	rows, err := r.Conn(ctx).Query(ctx, sql)
 	if err != nil {
-		return nil, fmt.Errorf("TranslationRepo - GetHistory - r.Pool.Query: %w", err)
+		return nil, fmt.Errorf("TranslationRepo - GetHistory - r.Conn.Query: %w", err)
 	}
 	defer rows.Close()
 
@@ -63,9 +63,9 @@ func (r *TranslationRepo) Store(ctx context.Context, t entity.Translation) error
 		return fmt.Errorf("TranslationRepo - Store - r.Builder: %w", err)
 	}
 
-	_, err = r.Pool.Exec(ctx, sql, args...)
+	_, err = r.Conn(ctx).Exec(ctx, sql, args...)
 	if err != nil {
-		return fmt.Errorf("TranslationRepo - Store - r.Pool.Exec: %w", err)
+		return fmt.Errorf("TranslationRepo - Store - r.Conn.Exec: %w", err)
 	}
 
 	return nil
@@ -0,0 +1,46 @@
+package persistent
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/pkg/postgres"
+)
+
+// whereVersion makes the update conditional on the stored row version, zero skips the check.
+func whereVersion(b squirrel.UpdateBuilder, version int) squirrel.UpdateBuilder {
+	if version == 0 {
+		return b
+	}
+
+	return b.Where("version = ?", version)
+}
+
+// missedWrite explains why a conditional write matched no rows:
+// either the row does not exist, has been soft-deleted or its version has changed in the meantime.
+func missedWrite(ctx context.Context, r *postgres.Postgres, table, subject string, id int) error {
+	sql, args, err := r.Builder.
+		Select("1").
+		From(table).
+		Where("id = ?", id).
+		Where(_notDeleted).
+		Prefix("SELECT EXISTS (").
+		Suffix(")").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("missedWrite - r.Builder: %w", err)
+	}
+
+	var exists bool
+	if err = r.Conn(ctx).QueryRow(ctx, sql, args...).Scan(&exists); err != nil {
+		return fmt.Errorf("missedWrite - r.Conn.QueryRow: %w", err)
+	}
+
+	if exists {
+		return entity.ErrVersionMismatch
+	}
+
+	return notFound(subject)
+}
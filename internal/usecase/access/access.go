@@ -0,0 +1,337 @@
+// Package access implements role-based access control scoped to the group hierarchy: the grant use case
+// and the decorators that check the grants of the caller before every student and group use case.
+// A grant on a group covers all its descendants, a grant without a group covers every group.
+package access
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/repo"
+	"github.com/evrone/go-clean-template/internal/usecase"
+)
+
+// policy decides what the caller of a request may do from the grants of the caller.
+// Admins have the admin role on every group without a grant.
+type policy struct {
+	grants repo.GrantRepo
+	groups repo.GroupRepo
+	tx     repo.Transactor
+	admins []string
+}
+
+// caller holds the roles of the caller, global is the role on every group.
+type caller struct {
+	global string
+	groups map[int]string
+}
+
+// caller loads the roles of the principal carried by the context, requests without one are denied.
+func (p policy) caller(ctx context.Context) (caller, error) {
+	principal, ok := entity.PrincipalFrom(ctx)
+	if !ok {
+		return caller{}, entity.ErrAccessDenied
+	}
+
+	c := caller{groups: make(map[int]string)}
+	if slices.Contains(p.admins, principal.Subject) {
+		c.global = entity.RoleAdmin
+	}
+
+	grants, err := p.grants.GetGrants(ctx, principal.Subject)
+	if err != nil {
+		return caller{}, fmt.Errorf("p.grants.GetGrants: %w", err)
+	}
+
+	for _, g := range grants {
+		if g.GroupID == nil {
+			c.global = entity.MaxRole(c.global, g.Role)
+		} else {
+			c.groups[*g.GroupID] = entity.MaxRole(c.groups[*g.GroupID], g.Role)
+		}
+	}
+
+	return c, nil
+}
+
+// Records the caller may not read are reported as missing, the way the use cases report records that do not exist.
+var (
+	errStudentNotFound = entity.NewError(entity.ErrNotFound, "student_not_found", "student not found")
+	errGroupNotFound   = entity.NewError(entity.ErrNotFound, "group_not_found", "group not found")
+)
+
+// check fails with entity.ErrAccessDenied unless the caller has the required role on every given group,
+// where nil stands for the root of the hierarchy and requires the role on every group.
+// Groups that do not exist or have been deleted are outside the hierarchy, only roles on every group cover them.
+func (p policy) check(ctx context.Context, required string, groupIDs ...*int) error {
+	c, err := p.caller(ctx)
+	if err != nil {
+		return err
+	}
+
+	return p.allows(ctx, c, required, groupIDs...)
+}
+
+// checkRead is check for the read role that fails with notFound instead of entity.ErrAccessDenied,
+// so that callers cannot tell the records they may not read from records that do not exist.
+func (p policy) checkRead(ctx context.Context, notFound error, groupIDs ...*int) error {
+	c, err := p.caller(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = p.allows(ctx, c, entity.RoleRead, groupIDs...)
+	if errors.Is(err, entity.ErrAccessDenied) {
+		return notFound
+	}
+
+	return err
+}
+
+// allows is check for roles that have already been loaded.
+func (p policy) allows(ctx context.Context, c caller, required string, groupIDs ...*int) error {
+	if entity.RoleIncludes(c.global, required) {
+		return nil
+	}
+
+	for _, id := range groupIDs {
+		if id == nil {
+			return entity.ErrAccessDenied
+		}
+
+		ancestors, err := p.groups.GetAncestors(ctx, *id)
+		if err != nil {
+			return fmt.Errorf("p.groups.GetAncestors: %w", err)
+		}
+
+		if !slices.ContainsFunc(ancestors, func(g entity.Group) bool {
+			return entity.RoleIncludes(c.groups[g.ID], required)
+		}) {
+			return entity.ErrAccessDenied
+		}
+	}
+
+	return nil
+}
+
+// role returns the role of the caller on a group, the highest role granted on the group or one of its ancestors.
+func (p policy) role(ctx context.Context, c caller, groupID int) (string, error) {
+	if len(c.groups) == 0 {
+		return c.global, nil
+	}
+
+	ancestors, err := p.groups.GetAncestors(ctx, groupID)
+	if err != nil {
+		return "", fmt.Errorf("p.groups.GetAncestors: %w", err)
+	}
+
+	role := c.global
+	for _, g := range ancestors {
+		role = entity.MaxRole(role, c.groups[g.ID])
+	}
+
+	return role, nil
+}
+
+// allowsDeleted fails with entity.ErrAccessDenied when soft-deleted records are asked for by a caller
+// without the admin role on every group. Deleted records have left the hierarchy the grants cover.
+func (p policy) allowsDeleted(ctx context.Context, c caller, includeDeleted bool) error {
+	if !includeDeleted {
+		return nil
+	}
+
+	return p.allows(ctx, c, entity.RoleAdmin, nil)
+}
+
+// scope returns the topmost groups the caller may read, whose subtrees hold everything the caller may see,
+// or nil when the caller may read every group. Including soft-deleted records needs the admin role on every group.
+func (p policy) scope(ctx context.Context, includeDeleted bool) ([]int, error) {
+	c, err := p.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = p.allowsDeleted(ctx, c, includeDeleted); err != nil {
+		return nil, err
+	}
+
+	if entity.RoleIncludes(c.global, entity.RoleRead) {
+		return nil, nil
+	}
+
+	roots := make([]int, 0, len(c.groups))
+
+	for id := range c.groups {
+		ancestors, err := p.groups.GetAncestors(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("p.groups.GetAncestors: %w", err)
+		}
+
+		if len(ancestors) > 0 && !slices.ContainsFunc(ancestors[1:], func(g entity.Group) bool {
+			_, ok := c.groups[g.ID]
+
+			return ok
+		}) {
+			roots = append(roots, id)
+		}
+	}
+
+	slices.Sort(roots)
+
+	return roots, nil
+}
+
+// inScope tells, for groups listed parents before their subgroups, whether a group lies in the scope.
+func inScope(scope []int) func(id int, parentID *int) bool {
+	visible := make(map[int]bool)
+
+	return func(id int, parentID *int) bool {
+		visible[id] = slices.Contains(scope, id) || parentID != nil && visible[*parentID]
+
+		return visible[id]
+	}
+}
+
+// UseCase implements the grant use case interface. Grants on a group are managed by admins of the group.
+type UseCase struct {
+	policy
+}
+
+// New creates a new grant use case, admins are the subjects with the admin role on every group.
+func New(grants repo.GrantRepo, groups repo.GroupRepo, admins []string) *UseCase {
+	return &UseCase{
+		policy: policy{grants: grants, groups: groups, admins: admins},
+	}
+}
+
+// GetGrants retrieves the grants of a subject, or of every subject when subject is empty,
+// leaving out those on groups the caller does not administer.
+func (uc *UseCase) GetGrants(ctx context.Context, subject string) ([]entity.Grant, error) {
+	c, err := uc.caller(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("AccessUseCase - GetGrants - uc.caller: %w", err)
+	}
+
+	grants, err := uc.grants.GetGrants(ctx, subject)
+	if err != nil {
+		return nil, fmt.Errorf("AccessUseCase - GetGrants - uc.grants.GetGrants: %w", err)
+	}
+
+	managed := make([]entity.Grant, 0, len(grants))
+
+	for _, g := range grants {
+		err = uc.allows(ctx, c, entity.RoleAdmin, g.GroupID)
+		if errors.Is(err, entity.ErrAccessDenied) {
+			continue
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("AccessUseCase - GetGrants - uc.allows: %w", err)
+		}
+
+		managed = append(managed, g)
+	}
+
+	return managed, nil
+}
+
+// CreateGrant gives a subject a role on a group.
+func (uc *UseCase) CreateGrant(ctx context.Context, grant entity.Grant) (entity.Grant, error) {
+	if err := uc.check(ctx, entity.RoleAdmin, grant.GroupID); err != nil {
+		return entity.Grant{}, fmt.Errorf("AccessUseCase - CreateGrant - uc.check: %w", err)
+	}
+
+	created, err := uc.grants.CreateGrant(ctx, grant)
+	if err != nil {
+		return entity.Grant{}, fmt.Errorf("AccessUseCase - CreateGrant - uc.grants.CreateGrant: %w", err)
+	}
+
+	return created, nil
+}
+
+// DeleteGrant takes a role away.
+func (uc *UseCase) DeleteGrant(ctx context.Context, id int) error {
+	grant, err := uc.grants.GetGrantByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("AccessUseCase - DeleteGrant - uc.grants.GetGrantByID: %w", err)
+	}
+
+	if err = uc.check(ctx, entity.RoleAdmin, grant.GroupID); err != nil {
+		return fmt.Errorf("AccessUseCase - DeleteGrant - uc.check: %w", err)
+	}
+
+	if err = uc.grants.DeleteGrant(ctx, id); err != nil {
+		return fmt.Errorf("AccessUseCase - DeleteGrant - uc.grants.DeleteGrant: %w", err)
+	}
+
+	return nil
+}
+
+// Roles tells the roles of callers on groups, e.g. to decide which personal data they see.
+type Roles struct {
+	policy
+}
+
+// NewRoles creates a new role lookup, admins are the subjects with the admin role on every group.
+func NewRoles(grants repo.GrantRepo, groups repo.GroupRepo, admins []string) *Roles {
+	return &Roles{
+		policy: policy{grants: grants, groups: groups, admins: admins},
+	}
+}
+
+// RoleOn loads the roles of the caller and returns a function telling the role of the caller on a group,
+// which remembers the groups it has been asked about.
+func (r *Roles) RoleOn(ctx context.Context) (func(groupID int) (string, error), error) {
+	c, err := r.caller(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("AccessRoles - RoleOn - r.caller: %w", err)
+	}
+
+	roles := make(map[int]string)
+
+	return func(groupID int) (string, error) {
+		if role, ok := roles[groupID]; ok {
+			return role, nil
+		}
+
+		role, err := r.role(ctx, c, groupID)
+		if err != nil {
+			return "", fmt.Errorf("AccessRoles - RoleOn - r.role: %w", err)
+		}
+
+		roles[groupID] = role
+
+		return role, nil
+	}, nil
+}
+
+// Audit lets only the admins of every group read the audit log, which covers every group.
+type Audit struct {
+	usecase.Audit
+	policy
+}
+
+// NewAudit wraps an audit use case, admins are the subjects with the admin role on every group.
+func NewAudit(uc usecase.Audit, grants repo.GrantRepo, admins []string) *Audit {
+	return &Audit{
+		Audit:  uc,
+		policy: policy{grants: grants, admins: admins},
+	}
+}
+
+// GetAudit retrieves one page of audit entries if the caller administers every group.
+func (d *Audit) GetAudit(ctx context.Context, filter entity.AuditFilter, page entity.PageRequest) (entity.Page[entity.AuditEntry], error) {
+	if err := d.check(ctx, entity.RoleAdmin, nil); err != nil {
+		return entity.Page[entity.AuditEntry]{}, fmt.Errorf("AccessAudit - GetAudit - d.check: %w", err)
+	}
+
+	entries, err := d.Audit.GetAudit(ctx, filter, page)
+	if err != nil {
+		return entity.Page[entity.AuditEntry]{}, fmt.Errorf("AccessAudit - GetAudit - d.Audit.GetAudit: %w", err)
+	}
+
+	return entries, nil
+}
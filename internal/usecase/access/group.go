@@ -0,0 +1,363 @@
+package access
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/repo"
+	"github.com/evrone/go-clean-template/internal/usecase"
+)
+
+// Group checks the role of the caller on the groups involved before every group use case.
+// Reading needs the read role, creating and editing groups the write role and changing the hierarchy,
+// by deleting, moving or merging groups, the admin role on the group and on where it goes.
+// Root groups count as the children of every group. Lists and searches only hold the groups the caller may read,
+// soft-deleted ones only for the admins of every group. Groups the caller may not read are reported as not found.
+type Group struct {
+	usecase.Group
+	policy
+}
+
+// NewGroup wraps a group use case, admins are the subjects with the admin role on every group.
+func NewGroup(uc usecase.Group, grants repo.GrantRepo, groups repo.GroupRepo, tx repo.Transactor, admins []string) *Group {
+	return &Group{
+		Group:  uc,
+		policy: policy{grants: grants, groups: groups, tx: tx, admins: admins},
+	}
+}
+
+// CreateGroup creates a group under a parent the caller may write to.
+func (d *Group) CreateGroup(ctx context.Context, group entity.Group) (entity.Group, error) {
+	if err := d.check(ctx, entity.RoleWrite, group.ParentID); err != nil {
+		return entity.Group{}, fmt.Errorf("AccessGroup - CreateGroup - d.check: %w", err)
+	}
+
+	created, err := d.Group.CreateGroup(ctx, group)
+	if err != nil {
+		return entity.Group{}, fmt.Errorf("AccessGroup - CreateGroup - d.Group.CreateGroup: %w", err)
+	}
+
+	return created, nil
+}
+
+// GetGroups lists the subgroups of a group the caller may read, or in place of the root groups
+// the topmost groups the caller may read.
+func (d *Group) GetGroups(ctx context.Context, filter entity.GroupFilter, page entity.PageRequest) (entity.Page[entity.Group], error) {
+	if filter.ParentID != nil {
+		c, err := d.caller(ctx)
+		if err != nil {
+			return entity.Page[entity.Group]{}, fmt.Errorf("AccessGroup - GetGroups - d.caller: %w", err)
+		}
+
+		if err = d.allowsDeleted(ctx, c, filter.IncludeDeleted); err != nil {
+			return entity.Page[entity.Group]{}, fmt.Errorf("AccessGroup - GetGroups - d.allowsDeleted: %w", err)
+		}
+
+		err = d.allows(ctx, c, entity.RoleRead, filter.ParentID)
+		if errors.Is(err, entity.ErrAccessDenied) {
+			err = errGroupNotFound
+		}
+
+		if err != nil {
+			return entity.Page[entity.Group]{}, fmt.Errorf("AccessGroup - GetGroups - d.allows: %w", err)
+		}
+	} else {
+		scope, err := d.scope(ctx, filter.IncludeDeleted)
+		if err != nil {
+			return entity.Page[entity.Group]{}, fmt.Errorf("AccessGroup - GetGroups - d.scope: %w", err)
+		}
+
+		filter.Scope = scope
+	}
+
+	groups, err := d.Group.GetGroups(ctx, filter, page)
+	if err != nil {
+		return entity.Page[entity.Group]{}, fmt.Errorf("AccessGroup - GetGroups - d.Group.GetGroups: %w", err)
+	}
+
+	return groups, nil
+}
+
+// GetGroupByID retrieves a group the caller may read.
+func (d *Group) GetGroupByID(ctx context.Context, id int) (entity.Group, error) {
+	if err := d.checkRead(ctx, errGroupNotFound, &id); err != nil {
+		return entity.Group{}, fmt.Errorf("AccessGroup - GetGroupByID - d.checkRead: %w", err)
+	}
+
+	group, err := d.Group.GetGroupByID(ctx, id)
+	if err != nil {
+		return entity.Group{}, fmt.Errorf("AccessGroup - GetGroupByID - d.Group.GetGroupByID: %w", err)
+	}
+
+	return group, nil
+}
+
+// GetGroupWithSubgroups retrieves a group the caller may read with its subgroups.
+func (d *Group) GetGroupWithSubgroups(ctx context.Context, id, maxDepth int) (entity.Group, error) {
+	if err := d.checkRead(ctx, errGroupNotFound, &id); err != nil {
+		return entity.Group{}, fmt.Errorf("AccessGroup - GetGroupWithSubgroups - d.checkRead: %w", err)
+	}
+
+	group, err := d.Group.GetGroupWithSubgroups(ctx, id, maxDepth)
+	if err != nil {
+		return entity.Group{}, fmt.Errorf("AccessGroup - GetGroupWithSubgroups - d.Group.GetGroupWithSubgroups: %w", err)
+	}
+
+	return group, nil
+}
+
+// GetGroupPath retrieves the path of a group the caller may read.
+func (d *Group) GetGroupPath(ctx context.Context, id int) ([]entity.GroupRef, error) {
+	if err := d.checkRead(ctx, errGroupNotFound, &id); err != nil {
+		return nil, fmt.Errorf("AccessGroup - GetGroupPath - d.checkRead: %w", err)
+	}
+
+	path, err := d.Group.GetGroupPath(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("AccessGroup - GetGroupPath - d.Group.GetGroupPath: %w", err)
+	}
+
+	return path, nil
+}
+
+// UpdateGroup updates a group the caller may write to, or administer when the group gets another parent.
+func (d *Group) UpdateGroup(ctx context.Context, group entity.Group) (entity.Group, error) {
+	if err := d.checkChange(ctx, group.ID, group.ParentID, true); err != nil {
+		return entity.Group{}, fmt.Errorf("AccessGroup - UpdateGroup - d.checkChange: %w", err)
+	}
+
+	updated, err := d.Group.UpdateGroup(ctx, group)
+	if err != nil {
+		return entity.Group{}, fmt.Errorf("AccessGroup - UpdateGroup - d.Group.UpdateGroup: %w", err)
+	}
+
+	return updated, nil
+}
+
+// PatchGroup partially updates a group the caller may write to, or administer when the group gets another parent.
+func (d *Group) PatchGroup(ctx context.Context, id int, patch entity.GroupPatch) (entity.Group, error) {
+	if err := d.checkChange(ctx, id, patch.ParentID, patch.SetParent); err != nil {
+		return entity.Group{}, fmt.Errorf("AccessGroup - PatchGroup - d.checkChange: %w", err)
+	}
+
+	patched, err := d.Group.PatchGroup(ctx, id, patch)
+	if err != nil {
+		return entity.Group{}, fmt.Errorf("AccessGroup - PatchGroup - d.Group.PatchGroup: %w", err)
+	}
+
+	return patched, nil
+}
+
+// checkChange checks the role needed to change a group, given the parent it gets when setParent is true.
+func (d *Group) checkChange(ctx context.Context, id int, parentID *int, setParent bool) error {
+	required, groupIDs, err := d.change(ctx, id, parentID, setParent)
+	if err != nil {
+		return err
+	}
+
+	return d.check(ctx, required, groupIDs...)
+}
+
+// change returns the role needed to change a group and the groups it is needed on. Editing a group needs
+// the write role on it, giving it another parent the admin role on the group and on the new parent.
+func (d *Group) change(ctx context.Context, id int, parentID *int, setParent bool) (string, []*int, error) {
+	if !setParent {
+		return entity.RoleWrite, []*int{&id}, nil
+	}
+
+	current, err := d.Group.GetGroupByID(ctx, id)
+	if errors.Is(err, entity.ErrNotFound) {
+		return entity.RoleWrite, []*int{&id}, nil
+	}
+
+	if err != nil {
+		return "", nil, fmt.Errorf("d.Group.GetGroupByID: %w", err)
+	}
+
+	if current.ParentID == nil && parentID == nil || current.ParentID != nil && parentID != nil && *current.ParentID == *parentID {
+		return entity.RoleWrite, []*int{&id}, nil
+	}
+
+	return entity.RoleAdmin, []*int{&id, parentID}, nil
+}
+
+// DeleteGroup deletes a group the caller administers, reassigning its content to a target the caller administers.
+func (d *Group) DeleteGroup(ctx context.Context, id, version int, deletion entity.GroupDeletion) (entity.GroupChange, error) {
+	groupIDs := []*int{&id}
+	if deletion.Mode == entity.DeleteReassign {
+		groupIDs = append(groupIDs, &deletion.Target)
+	}
+
+	if err := d.check(ctx, entity.RoleAdmin, groupIDs...); err != nil {
+		return entity.GroupChange{}, fmt.Errorf("AccessGroup - DeleteGroup - d.check: %w", err)
+	}
+
+	change, err := d.Group.DeleteGroup(ctx, id, version, deletion)
+	if err != nil {
+		return entity.GroupChange{}, fmt.Errorf("AccessGroup - DeleteGroup - d.Group.DeleteGroup: %w", err)
+	}
+
+	return change, nil
+}
+
+// RestoreGroup restores a deleted group the caller administers.
+// Deleted groups are outside the hierarchy until restored, so the restore is undone on denial.
+func (d *Group) RestoreGroup(ctx context.Context, id int) (entity.Group, error) {
+	var restored entity.Group
+
+	err := d.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+
+		restored, err = d.Group.RestoreGroup(ctx, id)
+		if err != nil {
+			return fmt.Errorf("d.Group.RestoreGroup: %w", err)
+		}
+
+		return d.check(ctx, entity.RoleAdmin, &restored.ID)
+	})
+	if err != nil {
+		return entity.Group{}, fmt.Errorf("AccessGroup - RestoreGroup - d.tx.WithinTx: %w", err)
+	}
+
+	return restored, nil
+}
+
+// MoveGroup moves a group the caller administers under a parent the caller administers.
+func (d *Group) MoveGroup(ctx context.Context, id int, parentID *int, version int) (entity.GroupChange, error) {
+	if err := d.check(ctx, entity.RoleAdmin, &id, parentID); err != nil {
+		return entity.GroupChange{}, fmt.Errorf("AccessGroup - MoveGroup - d.check: %w", err)
+	}
+
+	change, err := d.Group.MoveGroup(ctx, id, parentID, version)
+	if err != nil {
+		return entity.GroupChange{}, fmt.Errorf("AccessGroup - MoveGroup - d.Group.MoveGroup: %w", err)
+	}
+
+	return change, nil
+}
+
+// MergeGroup merges a group the caller administers into another one the caller administers.
+func (d *Group) MergeGroup(ctx context.Context, id, targetID, version int) (entity.GroupChange, error) {
+	if err := d.check(ctx, entity.RoleAdmin, &id, &targetID); err != nil {
+		return entity.GroupChange{}, fmt.Errorf("AccessGroup - MergeGroup - d.check: %w", err)
+	}
+
+	change, err := d.Group.MergeGroup(ctx, id, targetID, version)
+	if err != nil {
+		return entity.GroupChange{}, fmt.Errorf("AccessGroup - MergeGroup - d.Group.MergeGroup: %w", err)
+	}
+
+	return change, nil
+}
+
+// BatchGroups applies a batch if the caller has the role needed for every operation.
+func (d *Group) BatchGroups(ctx context.Context, ops []entity.BatchOp[entity.Group], atomic bool) (entity.BatchReport[entity.Group], error) {
+	c, err := d.caller(ctx)
+	if err != nil {
+		return entity.BatchReport[entity.Group]{}, fmt.Errorf("AccessGroup - BatchGroups - d.caller: %w", err)
+	}
+
+	for _, op := range ops {
+		required, groupIDs := entity.RoleWrite, []*int{op.Item.ParentID}
+
+		switch op.Op {
+		case entity.BatchUpdate:
+			required, groupIDs, err = d.change(ctx, op.Item.ID, op.Item.ParentID, true)
+			if err != nil {
+				return entity.BatchReport[entity.Group]{}, fmt.Errorf("AccessGroup - BatchGroups - d.change: %w", err)
+			}
+		case entity.BatchDelete:
+			required, groupIDs = entity.RoleAdmin, []*int{&op.Item.ID}
+		}
+
+		if err = d.allows(ctx, c, required, groupIDs...); err != nil {
+			return entity.BatchReport[entity.Group]{}, fmt.Errorf("AccessGroup - BatchGroups - d.allows: %w", err)
+		}
+	}
+
+	report, err := d.Group.BatchGroups(ctx, ops, atomic)
+	if err != nil {
+		return entity.BatchReport[entity.Group]{}, fmt.Errorf("AccessGroup - BatchGroups - d.Group.BatchGroups: %w", err)
+	}
+
+	return report, nil
+}
+
+// ExportGroups returns the export of the groups the caller may read.
+// The caller is authorized here, before anything is streamed.
+func (d *Group) ExportGroups(ctx context.Context, filter entity.GroupFilter) (entity.Export[entity.GroupPath], error) {
+	scope, err := d.scope(ctx, false)
+	if err != nil {
+		return nil, fmt.Errorf("AccessGroup - ExportGroups - d.scope: %w", err)
+	}
+
+	filter.Scope = scope
+
+	export, err := d.Group.ExportGroups(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("AccessGroup - ExportGroups - d.Group.ExportGroups: %w", err)
+	}
+
+	return export, nil
+}
+
+// GetGroupStats sums up the statistics of the groups the caller may read.
+func (d *Group) GetGroupStats(ctx context.Context) (entity.GroupStatsSummary, error) {
+	scope, err := d.scope(ctx, false)
+	if err != nil {
+		return entity.GroupStatsSummary{}, fmt.Errorf("AccessGroup - GetGroupStats - d.scope: %w", err)
+	}
+
+	stats, err := d.Group.GetGroupStats(ctx)
+	if err != nil {
+		return entity.GroupStatsSummary{}, fmt.Errorf("AccessGroup - GetGroupStats - d.Group.GetGroupStats: %w", err)
+	}
+
+	if scope == nil {
+		return stats, nil
+	}
+
+	visible := inScope(scope)
+	items := make([]entity.GroupStats, 0, len(stats.Items))
+
+	for _, s := range stats.Items {
+		if visible(s.ID, s.ParentID) {
+			items = append(items, s)
+		}
+	}
+
+	return entity.NewGroupStatsSummary(items), nil
+}
+
+// GetGroupStatsByID retrieves the statistics of a group the caller may read.
+func (d *Group) GetGroupStatsByID(ctx context.Context, id int) (entity.GroupStats, error) {
+	if err := d.checkRead(ctx, errGroupNotFound, &id); err != nil {
+		return entity.GroupStats{}, fmt.Errorf("AccessGroup - GetGroupStatsByID - d.checkRead: %w", err)
+	}
+
+	stats, err := d.Group.GetGroupStatsByID(ctx, id)
+	if err != nil {
+		return entity.GroupStats{}, fmt.Errorf("AccessGroup - GetGroupStatsByID - d.Group.GetGroupStatsByID: %w", err)
+	}
+
+	return stats, nil
+}
+
+// SearchGroups searches the groups the caller may read.
+func (d *Group) SearchGroups(ctx context.Context, query entity.SearchQuery) ([]entity.GroupHit, error) {
+	scope, err := d.scope(ctx, query.IncludeDeleted)
+	if err != nil {
+		return nil, fmt.Errorf("AccessGroup - SearchGroups - d.scope: %w", err)
+	}
+
+	query.Scope = scope
+
+	hits, err := d.Group.SearchGroups(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("AccessGroup - SearchGroups - d.Group.SearchGroups: %w", err)
+	}
+
+	return hits, nil
+}
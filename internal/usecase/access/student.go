@@ -0,0 +1,355 @@
+package access
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/repo"
+	"github.com/evrone/go-clean-template/internal/usecase"
+)
+
+// Student checks the role of the caller on the groups of the students before every student use case.
+// Reading needs the read role on the group of a student, changes the write role on its old and new group.
+// Overriding the checks of a status change needs the admin role.
+// Lists and searches only hold the students the caller may read, soft-deleted ones only for the admins of every group.
+// Students the caller may not read are reported as not found.
+type Student struct {
+	usecase.Student
+	policy
+}
+
+// NewStudent wraps a student use case, admins are the subjects with the admin role on every group.
+func NewStudent(uc usecase.Student, grants repo.GrantRepo, groups repo.GroupRepo, tx repo.Transactor, admins []string) *Student {
+	return &Student{
+		Student: uc,
+		policy:  policy{grants: grants, groups: groups, tx: tx, admins: admins},
+	}
+}
+
+// CreateStudent creates a student in a group the caller may write to.
+func (d *Student) CreateStudent(ctx context.Context, student entity.Student) (entity.Student, error) {
+	if err := d.check(ctx, entity.RoleWrite, &student.GroupID); err != nil {
+		return entity.Student{}, fmt.Errorf("AccessStudent - CreateStudent - d.check: %w", err)
+	}
+
+	created, err := d.Student.CreateStudent(ctx, student)
+	if err != nil {
+		return entity.Student{}, fmt.Errorf("AccessStudent - CreateStudent - d.Student.CreateStudent: %w", err)
+	}
+
+	return created, nil
+}
+
+// GetStudents lists the students matching the filter that the caller may read.
+func (d *Student) GetStudents(ctx context.Context, filter entity.StudentFilter, page entity.PageRequest) (entity.Page[entity.Student], error) {
+	scope, err := d.scope(ctx, filter.IncludeDeleted)
+	if err != nil {
+		return entity.Page[entity.Student]{}, fmt.Errorf("AccessStudent - GetStudents - d.scope: %w", err)
+	}
+
+	filter.Scope = scope
+
+	students, err := d.Student.GetStudents(ctx, filter, page)
+	if err != nil {
+		return entity.Page[entity.Student]{}, fmt.Errorf("AccessStudent - GetStudents - d.Student.GetStudents: %w", err)
+	}
+
+	return students, nil
+}
+
+// GetStudentByID retrieves a student the caller may read.
+func (d *Student) GetStudentByID(ctx context.Context, id int) (entity.Student, error) {
+	student, err := d.Student.GetStudentByID(ctx, id)
+	if err != nil {
+		return entity.Student{}, fmt.Errorf("AccessStudent - GetStudentByID - d.Student.GetStudentByID: %w", err)
+	}
+
+	if err = d.checkRead(ctx, errStudentNotFound, &student.GroupID); err != nil {
+		return entity.Student{}, fmt.Errorf("AccessStudent - GetStudentByID - d.checkRead: %w", err)
+	}
+
+	return student, nil
+}
+
+// GetStudentWithPath retrieves a student the caller may read with the path of its group.
+func (d *Student) GetStudentWithPath(ctx context.Context, id int) (entity.Student, error) {
+	student, err := d.Student.GetStudentWithPath(ctx, id)
+	if err != nil {
+		return entity.Student{}, fmt.Errorf("AccessStudent - GetStudentWithPath - d.Student.GetStudentWithPath: %w", err)
+	}
+
+	if err = d.checkRead(ctx, errStudentNotFound, &student.GroupID); err != nil {
+		return entity.Student{}, fmt.Errorf("AccessStudent - GetStudentWithPath - d.checkRead: %w", err)
+	}
+
+	return student, nil
+}
+
+// GetGroupStudents lists the students of a group the caller may read, now or at a past time.
+func (d *Student) GetGroupStudents(ctx context.Context, groupID int, recursive bool, asOf *time.Time, page entity.PageRequest) (entity.Page[entity.Student], error) {
+	if err := d.checkRead(ctx, errGroupNotFound, &groupID); err != nil {
+		return entity.Page[entity.Student]{}, fmt.Errorf("AccessStudent - GetGroupStudents - d.checkRead: %w", err)
+	}
+
+	students, err := d.Student.GetGroupStudents(ctx, groupID, recursive, asOf, page)
+	if err != nil {
+		return entity.Page[entity.Student]{}, fmt.Errorf("AccessStudent - GetGroupStudents - d.Student.GetGroupStudents: %w", err)
+	}
+
+	return students, nil
+}
+
+// GetStudentEnrollments retrieves the group memberships of a student of a group the caller may read.
+func (d *Student) GetStudentEnrollments(ctx context.Context, id int) ([]entity.Enrollment, error) {
+	groupIDs, err := d.studentGroups(ctx, id, nil)
+	if err != nil {
+		return nil, fmt.Errorf("AccessStudent - GetStudentEnrollments - d.studentGroups: %w", err)
+	}
+
+	if err = d.checkRead(ctx, errStudentNotFound, groupIDs...); err != nil {
+		return nil, fmt.Errorf("AccessStudent - GetStudentEnrollments - d.checkRead: %w", err)
+	}
+
+	enrollments, err := d.Student.GetStudentEnrollments(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("AccessStudent - GetStudentEnrollments - d.Student.GetStudentEnrollments: %w", err)
+	}
+
+	return enrollments, nil
+}
+
+// UpdateStudent updates a student whose old and new group the caller may write to.
+func (d *Student) UpdateStudent(ctx context.Context, student entity.Student) (entity.Student, error) {
+	if err := d.checkStudent(ctx, student.ID, &student.GroupID); err != nil {
+		return entity.Student{}, fmt.Errorf("AccessStudent - UpdateStudent - d.checkStudent: %w", err)
+	}
+
+	updated, err := d.Student.UpdateStudent(ctx, student)
+	if err != nil {
+		return entity.Student{}, fmt.Errorf("AccessStudent - UpdateStudent - d.Student.UpdateStudent: %w", err)
+	}
+
+	return updated, nil
+}
+
+// PatchStudent partially updates a student whose old and new group the caller may write to.
+func (d *Student) PatchStudent(ctx context.Context, id int, patch entity.StudentPatch) (entity.Student, error) {
+	if err := d.checkStudent(ctx, id, patch.GroupID); err != nil {
+		return entity.Student{}, fmt.Errorf("AccessStudent - PatchStudent - d.checkStudent: %w", err)
+	}
+
+	patched, err := d.Student.PatchStudent(ctx, id, patch)
+	if err != nil {
+		return entity.Student{}, fmt.Errorf("AccessStudent - PatchStudent - d.Student.PatchStudent: %w", err)
+	}
+
+	return patched, nil
+}
+
+// ChangeStudentStatus changes the status of a student of a group the caller may write to,
+// overriding the transition check needs the admin role on the group.
+func (d *Student) ChangeStudentStatus(ctx context.Context, id int, change entity.StatusChange) (entity.Student, error) {
+	required := entity.RoleWrite
+	if change.Override {
+		required = entity.RoleAdmin
+	}
+
+	groupIDs, err := d.studentGroups(ctx, id, nil)
+	if err != nil {
+		return entity.Student{}, fmt.Errorf("AccessStudent - ChangeStudentStatus - d.studentGroups: %w", err)
+	}
+
+	if err = d.check(ctx, required, groupIDs...); err != nil {
+		return entity.Student{}, fmt.Errorf("AccessStudent - ChangeStudentStatus - d.check: %w", err)
+	}
+
+	changed, err := d.Student.ChangeStudentStatus(ctx, id, change)
+	if err != nil {
+		return entity.Student{}, fmt.Errorf("AccessStudent - ChangeStudentStatus - d.Student.ChangeStudentStatus: %w", err)
+	}
+
+	return changed, nil
+}
+
+// DeleteStudent deletes a student of a group the caller may write to.
+func (d *Student) DeleteStudent(ctx context.Context, id, version int) error {
+	if err := d.checkStudent(ctx, id, nil); err != nil {
+		return fmt.Errorf("AccessStudent - DeleteStudent - d.checkStudent: %w", err)
+	}
+
+	if err := d.Student.DeleteStudent(ctx, id, version); err != nil {
+		return fmt.Errorf("AccessStudent - DeleteStudent - d.Student.DeleteStudent: %w", err)
+	}
+
+	return nil
+}
+
+// checkStudent checks the write role on the group of an active student and on the group it moves to, if any.
+func (d *Student) checkStudent(ctx context.Context, id int, groupID *int) error {
+	groupIDs, err := d.studentGroups(ctx, id, groupID)
+	if err != nil {
+		return err
+	}
+
+	return d.check(ctx, entity.RoleWrite, groupIDs...)
+}
+
+// studentGroups returns the group of an active student and the group it moves to, if any.
+// Students that do not exist have no group, the use case reports them.
+func (d *Student) studentGroups(ctx context.Context, id int, groupID *int) ([]*int, error) {
+	var groupIDs []*int
+	if groupID != nil {
+		groupIDs = append(groupIDs, groupID)
+	}
+
+	student, err := d.Student.GetStudentByID(ctx, id)
+	if errors.Is(err, entity.ErrNotFound) {
+		return groupIDs, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("d.Student.GetStudentByID: %w", err)
+	}
+
+	return append(groupIDs, &student.GroupID), nil
+}
+
+// RestoreStudent restores a deleted student into a group the caller may write to.
+// The group of a deleted student is only known once it has been restored, so the restore is undone on denial.
+func (d *Student) RestoreStudent(ctx context.Context, id int) (entity.Student, error) {
+	var restored entity.Student
+
+	err := d.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+
+		restored, err = d.Student.RestoreStudent(ctx, id)
+		if err != nil {
+			return fmt.Errorf("d.Student.RestoreStudent: %w", err)
+		}
+
+		return d.check(ctx, entity.RoleWrite, &restored.GroupID)
+	})
+	if err != nil {
+		return entity.Student{}, fmt.Errorf("AccessStudent - RestoreStudent - d.tx.WithinTx: %w", err)
+	}
+
+	return restored, nil
+}
+
+// ImportStudents imports a roster into groups the caller may write to. The groups of the rows are resolved
+// and checked before anything is stored, dry runs included, and handed on by ID, so that every row ends up
+// in the group that was checked.
+func (d *Student) ImportStudents(ctx context.Context, rows []entity.StudentImport, dryRun bool) (entity.ImportReport, error) {
+	ids, names := entity.ImportGroupRefs(rows)
+
+	groups, err := d.groups.FindGroups(ctx, ids, names)
+	if err != nil {
+		return entity.ImportReport{}, fmt.Errorf("AccessStudent - ImportStudents - d.groups.FindGroups: %w", err)
+	}
+
+	// Resolved apart from the rows, so that the use case reports the errors of unresolved groups only once
+	refs := make([]entity.StudentImport, len(rows))
+	for i, row := range rows {
+		refs[i].Group = row.Group
+	}
+
+	entity.ResolveImportGroups(refs, groups)
+
+	rows = slices.Clone(rows)
+	groupIDs := make([]*int, 0, len(rows))
+
+	for i := range rows {
+		if len(rows[i].Errors) > 0 || len(refs[i].Errors) > 0 {
+			continue
+		}
+
+		groupIDs = append(groupIDs, &refs[i].Student.GroupID)
+		rows[i].Group = strconv.Itoa(refs[i].Student.GroupID)
+	}
+
+	if err = d.check(ctx, entity.RoleWrite, groupIDs...); err != nil {
+		return entity.ImportReport{}, fmt.Errorf("AccessStudent - ImportStudents - d.check: %w", err)
+	}
+
+	report, err := d.Student.ImportStudents(ctx, rows, dryRun)
+	if err != nil {
+		return entity.ImportReport{}, fmt.Errorf("AccessStudent - ImportStudents - d.Student.ImportStudents: %w", err)
+	}
+
+	return report, nil
+}
+
+// BatchStudents applies a batch if the caller may write to the groups of every student it changes.
+func (d *Student) BatchStudents(ctx context.Context, ops []entity.BatchOp[entity.Student], atomic bool) (entity.BatchReport[entity.Student], error) {
+	groupIDs := make([]*int, 0, len(ops))
+
+	for _, op := range ops {
+		switch op.Op {
+		case entity.BatchCreate:
+			groupIDs = append(groupIDs, &op.Item.GroupID)
+		case entity.BatchUpdate, entity.BatchDelete:
+			var moveTo *int
+			if op.Op == entity.BatchUpdate {
+				moveTo = &op.Item.GroupID
+			}
+
+			changed, err := d.studentGroups(ctx, op.Item.ID, moveTo)
+			if err != nil {
+				return entity.BatchReport[entity.Student]{}, fmt.Errorf("AccessStudent - BatchStudents - d.studentGroups: %w", err)
+			}
+
+			groupIDs = append(groupIDs, changed...)
+		}
+	}
+
+	if err := d.check(ctx, entity.RoleWrite, groupIDs...); err != nil {
+		return entity.BatchReport[entity.Student]{}, fmt.Errorf("AccessStudent - BatchStudents - d.check: %w", err)
+	}
+
+	report, err := d.Student.BatchStudents(ctx, ops, atomic)
+	if err != nil {
+		return entity.BatchReport[entity.Student]{}, fmt.Errorf("AccessStudent - BatchStudents - d.Student.BatchStudents: %w", err)
+	}
+
+	return report, nil
+}
+
+// ExportStudents returns the export of the students matching the filter that the caller may read.
+// The caller is authorized here, before anything is streamed.
+func (d *Student) ExportStudents(ctx context.Context, filter entity.StudentFilter) (entity.Export[entity.Student], error) {
+	scope, err := d.scope(ctx, filter.IncludeDeleted)
+	if err != nil {
+		return nil, fmt.Errorf("AccessStudent - ExportStudents - d.scope: %w", err)
+	}
+
+	filter.Scope = scope
+
+	export, err := d.Student.ExportStudents(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("AccessStudent - ExportStudents - d.Student.ExportStudents: %w", err)
+	}
+
+	return export, nil
+}
+
+// SearchStudents searches the students the caller may read.
+func (d *Student) SearchStudents(ctx context.Context, query entity.SearchQuery) ([]entity.StudentHit, error) {
+	scope, err := d.scope(ctx, query.IncludeDeleted)
+	if err != nil {
+		return nil, fmt.Errorf("AccessStudent - SearchStudents - d.scope: %w", err)
+	}
+
+	query.Scope = scope
+
+	hits, err := d.Student.SearchStudents(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("AccessStudent - SearchStudents - d.Student.SearchStudents: %w", err)
+	}
+
+	return hits, nil
+}
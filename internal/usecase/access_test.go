@@ -0,0 +1,600 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/usecase/access"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// The test hierarchy: university (1) holds physics (3) and chemistry (4), physics holds optics (7).
+var (
+	_university = entity.Group{ID: 1, Name: "University"}
+	_physics    = entity.Group{ID: 3, Name: "Physics", ParentID: intPtr(1)}
+	_chemistry  = entity.Group{ID: 4, Name: "Chemistry", ParentID: intPtr(1)}
+	_optics     = entity.Group{ID: 7, Name: "Optics", ParentID: intPtr(3)}
+)
+
+func secretary() context.Context {
+	return entity.WithPrincipal(context.Background(), entity.Principal{Subject: "secretary", Method: entity.AuthJWT})
+}
+
+func grant(groupID int, role string) entity.Grant {
+	return entity.Grant{Subject: "secretary", GroupID: &groupID, Role: role}
+}
+
+func accessControlledStudents(t *testing.T) (*access.Student, *MockStudent, *MockGrantRepo, *MockGroupRepo) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	students := NewMockStudent(mockCtl)
+	grants := NewMockGrantRepo(mockCtl)
+	groups := NewMockGroupRepo(mockCtl)
+
+	return access.NewStudent(students, grants, groups, transactor(mockCtl), []string{"root"}), students, grants, groups
+}
+
+func accessControlledGroups(t *testing.T) (*access.Group, *MockGroup, *MockGrantRepo, *MockGroupRepo) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	uc := NewMockGroup(mockCtl)
+	grants := NewMockGrantRepo(mockCtl)
+	groups := NewMockGroupRepo(mockCtl)
+
+	return access.NewGroup(uc, grants, groups, transactor(mockCtl), []string{"root"}), uc, grants, groups
+}
+
+func TestAccessGetStudents(t *testing.T) { //nolint:tparallel // data races here
+	t.Parallel()
+
+	decorator, students, grants, groups := accessControlledStudents(t)
+
+	root := entity.WithPrincipal(context.Background(), entity.Principal{Subject: "root", Method: entity.AuthAPIKey})
+	page := entity.PageRequest{Limit: 10, Sort: entity.SortByID}
+	result := entity.Page[entity.Student]{Items: []entity.Student{{ID: 1, Name: "John Doe", GroupID: 7}}, Total: 1}
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		filter entity.StudentFilter
+		mock   func(ctx context.Context)
+		res    entity.Page[entity.Student]
+		err    error
+	}{
+		{
+			name: "scoped to the topmost granted groups",
+			ctx:  secretary(),
+			mock: func(ctx context.Context) {
+				grants.EXPECT().GetGrants(ctx, "secretary").Return([]entity.Grant{
+					grant(3, entity.RoleWrite), grant(7, entity.RoleRead),
+				}, nil)
+				groups.EXPECT().GetAncestors(ctx, 3).Return([]entity.Group{_physics, _university}, nil)
+				groups.EXPECT().GetAncestors(ctx, 7).Return([]entity.Group{_optics, _physics, _university}, nil)
+				students.EXPECT().GetStudents(ctx, entity.StudentFilter{Scope: []int{3}}, page).Return(result, nil)
+			},
+			res: result,
+			err: nil,
+		},
+		{
+			name: "no grants",
+			ctx:  secretary(),
+			mock: func(ctx context.Context) {
+				grants.EXPECT().GetGrants(ctx, "secretary").Return(nil, nil)
+				students.EXPECT().GetStudents(ctx, entity.StudentFilter{Scope: []int{}}, page).
+					Return(entity.Page[entity.Student]{}, nil)
+			},
+			res: entity.Page[entity.Student]{},
+			err: nil,
+		},
+		{
+			name: "admins see every group",
+			ctx:  root,
+			mock: func(ctx context.Context) {
+				grants.EXPECT().GetGrants(ctx, "root").Return(nil, nil)
+				students.EXPECT().GetStudents(ctx, entity.StudentFilter{}, page).Return(result, nil)
+			},
+			res: result,
+			err: nil,
+		},
+		{
+			name:   "deleted students for admins of every group",
+			ctx:    root,
+			filter: entity.StudentFilter{IncludeDeleted: true},
+			mock: func(ctx context.Context) {
+				grants.EXPECT().GetGrants(ctx, "root").Return(nil, nil)
+				students.EXPECT().GetStudents(ctx, entity.StudentFilter{IncludeDeleted: true}, page).Return(result, nil)
+			},
+			res: result,
+			err: nil,
+		},
+		{
+			name:   "deleted students for group admins",
+			ctx:    secretary(),
+			filter: entity.StudentFilter{IncludeDeleted: true},
+			mock: func(ctx context.Context) {
+				grants.EXPECT().GetGrants(ctx, "secretary").Return([]entity.Grant{grant(1, entity.RoleAdmin)}, nil)
+			},
+			res: entity.Page[entity.Student]{},
+			err: entity.ErrAccessDenied,
+		},
+		{
+			name: "anonymous",
+			ctx:  context.Background(),
+			mock: func(context.Context) {},
+			res:  entity.Page[entity.Student]{},
+			err:  entity.ErrAccessDenied,
+		},
+	}
+
+	for _, tc := range tests { //nolint:paralleltest // data races here
+		localTc := tc
+
+		t.Run(localTc.name, func(t *testing.T) {
+			localTc.mock(localTc.ctx)
+
+			res, err := decorator.GetStudents(localTc.ctx, localTc.filter, page)
+
+			require.Equal(t, localTc.res, res)
+			require.ErrorIs(t, err, localTc.err)
+		})
+	}
+}
+
+func TestAccessCreateStudent(t *testing.T) { //nolint:tparallel // data races here
+	t.Parallel()
+
+	decorator, students, grants, groups := accessControlledStudents(t)
+
+	ctx := secretary()
+
+	tests := []struct {
+		name    string
+		student entity.Student
+		mock    func(student entity.Student)
+		res     entity.Student
+		err     error
+	}{
+		{
+			name:    "write role on an ancestor",
+			student: entity.Student{Name: "John Doe", Email: "john@example.com", GroupID: 7},
+			mock: func(student entity.Student) {
+				grants.EXPECT().GetGrants(ctx, "secretary").Return([]entity.Grant{grant(3, entity.RoleWrite)}, nil)
+				groups.EXPECT().GetAncestors(ctx, 7).Return([]entity.Group{_optics, _physics, _university}, nil)
+				students.EXPECT().CreateStudent(ctx, student).Return(entity.Student{ID: 1, Name: "John Doe", GroupID: 7}, nil)
+			},
+			res: entity.Student{ID: 1, Name: "John Doe", GroupID: 7},
+			err: nil,
+		},
+		{
+			name:    "read role only",
+			student: entity.Student{Name: "John Doe", Email: "john@example.com", GroupID: 7},
+			mock: func(entity.Student) {
+				grants.EXPECT().GetGrants(ctx, "secretary").Return([]entity.Grant{grant(7, entity.RoleRead)}, nil)
+				groups.EXPECT().GetAncestors(ctx, 7).Return([]entity.Group{_optics, _physics, _university}, nil)
+			},
+			res: entity.Student{},
+			err: entity.ErrAccessDenied,
+		},
+		{
+			name:    "another subtree",
+			student: entity.Student{Name: "John Doe", Email: "john@example.com", GroupID: 4},
+			mock: func(entity.Student) {
+				grants.EXPECT().GetGrants(ctx, "secretary").Return([]entity.Grant{grant(3, entity.RoleAdmin)}, nil)
+				groups.EXPECT().GetAncestors(ctx, 4).Return([]entity.Group{_chemistry, _university}, nil)
+			},
+			res: entity.Student{},
+			err: entity.ErrAccessDenied,
+		},
+		{
+			name:    "deleted or missing group",
+			student: entity.Student{Name: "John Doe", Email: "john@example.com", GroupID: 9},
+			mock: func(entity.Student) {
+				grants.EXPECT().GetGrants(ctx, "secretary").Return([]entity.Grant{grant(3, entity.RoleWrite)}, nil)
+				groups.EXPECT().GetAncestors(ctx, 9).Return(nil, nil)
+			},
+			res: entity.Student{},
+			err: entity.ErrAccessDenied,
+		},
+	}
+
+	for _, tc := range tests { //nolint:paralleltest // data races here
+		localTc := tc
+
+		t.Run(localTc.name, func(t *testing.T) {
+			localTc.mock(localTc.student)
+
+			res, err := decorator.CreateStudent(ctx, localTc.student)
+
+			require.Equal(t, localTc.res, res)
+			require.ErrorIs(t, err, localTc.err)
+		})
+	}
+}
+
+func TestAccessGetStudentByID(t *testing.T) { //nolint:tparallel // data races here
+	t.Parallel()
+
+	decorator, students, grants, groups := accessControlledStudents(t)
+
+	ctx := secretary()
+	john := entity.Student{ID: 1, Name: "John Doe", GroupID: 7}
+	jane := entity.Student{ID: 2, Name: "Jane Roe", GroupID: 4}
+	orphan := entity.Student{ID: 3, Name: "Ivan Petrov", GroupID: 9}
+
+	tests := []struct {
+		name string
+		id   int
+		mock func()
+		res  entity.Student
+		err  error
+	}{
+		{
+			name: "read role on an ancestor",
+			id:   1,
+			mock: func() {
+				students.EXPECT().GetStudentByID(ctx, 1).Return(john, nil)
+				grants.EXPECT().GetGrants(ctx, "secretary").Return([]entity.Grant{grant(3, entity.RoleRead)}, nil)
+				groups.EXPECT().GetAncestors(ctx, 7).Return([]entity.Group{_optics, _physics, _university}, nil)
+			},
+			res: john,
+			err: nil,
+		},
+		{
+			name: "another subtree looks missing",
+			id:   2,
+			mock: func() {
+				students.EXPECT().GetStudentByID(ctx, 2).Return(jane, nil)
+				grants.EXPECT().GetGrants(ctx, "secretary").Return([]entity.Grant{grant(3, entity.RoleRead)}, nil)
+				groups.EXPECT().GetAncestors(ctx, 4).Return([]entity.Group{_chemistry, _university}, nil)
+			},
+			res: entity.Student{},
+			err: entity.ErrNotFound,
+		},
+		{
+			name: "deleted group",
+			id:   3,
+			mock: func() {
+				students.EXPECT().GetStudentByID(ctx, 3).Return(orphan, nil)
+				grants.EXPECT().GetGrants(ctx, "secretary").Return([]entity.Grant{grant(3, entity.RoleRead)}, nil)
+				groups.EXPECT().GetAncestors(ctx, 9).Return(nil, nil)
+			},
+			res: entity.Student{},
+			err: entity.ErrNotFound,
+		},
+		{
+			name: "missing student",
+			id:   5,
+			mock: func() {
+				students.EXPECT().GetStudentByID(ctx, 5).
+					Return(entity.Student{}, entity.NewError(entity.ErrNotFound, "student_not_found", "student not found"))
+			},
+			res: entity.Student{},
+			err: entity.ErrNotFound,
+		},
+	}
+
+	for _, tc := range tests { //nolint:paralleltest // data races here
+		localTc := tc
+
+		t.Run(localTc.name, func(t *testing.T) {
+			localTc.mock()
+
+			res, err := decorator.GetStudentByID(ctx, localTc.id)
+
+			require.Equal(t, localTc.res, res)
+			require.ErrorIs(t, err, localTc.err)
+			require.NotErrorIs(t, err, entity.ErrAccessDenied)
+		})
+	}
+}
+
+func TestAccessMoveGroup(t *testing.T) { //nolint:tparallel // data races here
+	t.Parallel()
+
+	decorator, uc, grants, groups := accessControlledGroups(t)
+
+	ctx := secretary()
+	moved := entity.GroupChange{Group: entity.Group{ID: 7, Name: "Optics", ParentID: intPtr(3)}, Students: 12}
+
+	tests := []struct {
+		name     string
+		parentID *int
+		mock     func()
+		res      entity.GroupChange
+		err      error
+	}{
+		{
+			name:     "admin role on the group and the new parent",
+			parentID: intPtr(3),
+			mock: func() {
+				grants.EXPECT().GetGrants(ctx, "secretary").Return([]entity.Grant{grant(3, entity.RoleAdmin)}, nil)
+				groups.EXPECT().GetAncestors(ctx, 7).Return([]entity.Group{_optics, _physics, _university}, nil)
+				groups.EXPECT().GetAncestors(ctx, 3).Return([]entity.Group{_physics, _university}, nil)
+				uc.EXPECT().MoveGroup(ctx, 7, intPtr(3), 1).Return(moved, nil)
+			},
+			res: moved,
+			err: nil,
+		},
+		{
+			name:     "write role",
+			parentID: intPtr(3),
+			mock: func() {
+				grants.EXPECT().GetGrants(ctx, "secretary").Return([]entity.Grant{grant(3, entity.RoleWrite)}, nil)
+				groups.EXPECT().GetAncestors(ctx, 7).Return([]entity.Group{_optics, _physics, _university}, nil)
+			},
+			res: entity.GroupChange{},
+			err: entity.ErrAccessDenied,
+		},
+		{
+			name:     "out of the granted subtree",
+			parentID: intPtr(4),
+			mock: func() {
+				grants.EXPECT().GetGrants(ctx, "secretary").Return([]entity.Grant{grant(3, entity.RoleAdmin)}, nil)
+				groups.EXPECT().GetAncestors(ctx, 7).Return([]entity.Group{_optics, _physics, _university}, nil)
+				groups.EXPECT().GetAncestors(ctx, 4).Return([]entity.Group{_chemistry, _university}, nil)
+			},
+			res: entity.GroupChange{},
+			err: entity.ErrAccessDenied,
+		},
+		{
+			name:     "to the root",
+			parentID: nil,
+			mock: func() {
+				grants.EXPECT().GetGrants(ctx, "secretary").Return([]entity.Grant{grant(3, entity.RoleAdmin)}, nil)
+				groups.EXPECT().GetAncestors(ctx, 7).Return([]entity.Group{_optics, _physics, _university}, nil)
+			},
+			res: entity.GroupChange{},
+			err: entity.ErrAccessDenied,
+		},
+	}
+
+	for _, tc := range tests { //nolint:paralleltest // data races here
+		localTc := tc
+
+		t.Run(localTc.name, func(t *testing.T) {
+			localTc.mock()
+
+			res, err := decorator.MoveGroup(ctx, 7, localTc.parentID, 1)
+
+			require.Equal(t, localTc.res, res)
+			require.ErrorIs(t, err, localTc.err)
+		})
+	}
+}
+
+func TestAccessExportStudents(t *testing.T) { //nolint:tparallel // data races here
+	t.Parallel()
+
+	decorator, students, grants, groups := accessControlledStudents(t)
+
+	john := entity.Student{ID: 1, Name: "John Doe", GroupID: 7}
+	export := func(yield func(entity.Student) error) error { return yield(john) }
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		filter entity.StudentFilter
+		mock   func(ctx context.Context)
+		res    []entity.Student
+		err    error
+	}{
+		{
+			name: "scoped to the granted groups",
+			ctx:  secretary(),
+			mock: func(ctx context.Context) {
+				grants.EXPECT().GetGrants(ctx, "secretary").Return([]entity.Grant{grant(3, entity.RoleRead)}, nil)
+				groups.EXPECT().GetAncestors(ctx, 3).Return([]entity.Group{_physics, _university}, nil)
+				students.EXPECT().ExportStudents(ctx, entity.StudentFilter{Scope: []int{3}}).Return(export, nil)
+			},
+			res: []entity.Student{john},
+			err: nil,
+		},
+		{
+			name:   "deleted students for group admins",
+			ctx:    secretary(),
+			filter: entity.StudentFilter{IncludeDeleted: true},
+			mock: func(ctx context.Context) {
+				grants.EXPECT().GetGrants(ctx, "secretary").Return([]entity.Grant{grant(1, entity.RoleAdmin)}, nil)
+			},
+			res: nil,
+			err: entity.ErrAccessDenied,
+		},
+		{
+			name: "anonymous",
+			ctx:  context.Background(),
+			mock: func(context.Context) {},
+			res:  nil,
+			err:  entity.ErrAccessDenied,
+		},
+	}
+
+	for _, tc := range tests { //nolint:paralleltest // data races here
+		localTc := tc
+
+		t.Run(localTc.name, func(t *testing.T) {
+			localTc.mock(localTc.ctx)
+
+			export, err := decorator.ExportStudents(localTc.ctx, localTc.filter)
+
+			require.ErrorIs(t, err, localTc.err)
+
+			if localTc.err != nil {
+				require.Nil(t, export)
+
+				return
+			}
+
+			var res []entity.Student
+
+			require.NoError(t, export(func(s entity.Student) error {
+				res = append(res, s)
+
+				return nil
+			}))
+			require.Equal(t, localTc.res, res)
+		})
+	}
+}
+
+func TestAccessExportGroups(t *testing.T) { //nolint:tparallel // data races here
+	t.Parallel()
+
+	decorator, uc, grants, groups := accessControlledGroups(t)
+
+	physics := entity.GroupPath{Group: _physics, Path: []string{"University", "Physics"}, Levels: 2}
+	export := func(yield func(entity.GroupPath) error) error { return yield(physics) }
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		mock func(ctx context.Context)
+		res  []entity.GroupPath
+		err  error
+	}{
+		{
+			name: "scoped to the granted groups",
+			ctx:  secretary(),
+			mock: func(ctx context.Context) {
+				grants.EXPECT().GetGrants(ctx, "secretary").Return([]entity.Grant{grant(3, entity.RoleRead)}, nil)
+				groups.EXPECT().GetAncestors(ctx, 3).Return([]entity.Group{_physics, _university}, nil)
+				uc.EXPECT().ExportGroups(ctx, entity.GroupFilter{Scope: []int{3}}).Return(export, nil)
+			},
+			res: []entity.GroupPath{physics},
+			err: nil,
+		},
+		{
+			name: "grants not loaded",
+			ctx:  secretary(),
+			mock: func(ctx context.Context) {
+				grants.EXPECT().GetGrants(ctx, "secretary").Return(nil, errInternalServErr)
+			},
+			res: nil,
+			err: errInternalServErr,
+		},
+		{
+			name: "anonymous",
+			ctx:  context.Background(),
+			mock: func(context.Context) {},
+			res:  nil,
+			err:  entity.ErrAccessDenied,
+		},
+	}
+
+	for _, tc := range tests { //nolint:paralleltest // data races here
+		localTc := tc
+
+		t.Run(localTc.name, func(t *testing.T) {
+			localTc.mock(localTc.ctx)
+
+			export, err := decorator.ExportGroups(localTc.ctx, entity.GroupFilter{})
+
+			require.ErrorIs(t, err, localTc.err)
+
+			if localTc.err != nil {
+				require.Nil(t, export)
+
+				return
+			}
+
+			var res []entity.GroupPath
+
+			require.NoError(t, export(func(g entity.GroupPath) error {
+				res = append(res, g)
+
+				return nil
+			}))
+			require.Equal(t, localTc.res, res)
+		})
+	}
+}
+
+func TestAccessImportStudents(t *testing.T) { //nolint:tparallel // data races here
+	t.Parallel()
+
+	decorator, students, grants, groups := accessControlledStudents(t)
+
+	ctx := secretary()
+	ann := entity.Student{Name: "Ann", Email: "ann@example.com"}
+	report := entity.ImportReport{DryRun: true, Total: 2, Failed: 1, Rows: []entity.ImportRow{
+		{Row: 2, Status: entity.ImportValid},
+		{Row: 3, Status: entity.ImportFailed, Errors: []entity.FieldError{{Field: "group", Message: "group does not exist"}}},
+	}}
+
+	tests := []struct {
+		name   string
+		rows   []entity.StudentImport
+		dryRun bool
+		mock   func()
+		res    entity.ImportReport
+		err    error
+	}{
+		{
+			name:   "groups handed on by ID",
+			rows:   []entity.StudentImport{{Row: 2, Student: ann, Group: "optics"}, {Row: 3, Student: ann, Group: "9"}},
+			dryRun: true,
+			mock: func() {
+				groups.EXPECT().FindGroups(ctx, []int{9}, []string{"optics"}).Return([]entity.Group{_optics}, nil)
+				grants.EXPECT().GetGrants(ctx, "secretary").Return([]entity.Grant{grant(3, entity.RoleWrite)}, nil)
+				groups.EXPECT().GetAncestors(ctx, 7).Return([]entity.Group{_optics, _physics, _university}, nil)
+				students.EXPECT().ImportStudents(ctx, []entity.StudentImport{
+					{Row: 2, Student: ann, Group: "7"}, {Row: 3, Student: ann, Group: "9"},
+				}, true).Return(report, nil)
+			},
+			res: report,
+			err: nil,
+		},
+		{
+			name:   "dry run into a group without the write role",
+			rows:   []entity.StudentImport{{Row: 2, Student: ann, Group: "Chemistry"}},
+			dryRun: true,
+			mock: func() {
+				groups.EXPECT().FindGroups(ctx, []int{}, []string{"Chemistry"}).Return([]entity.Group{_chemistry}, nil)
+				grants.EXPECT().GetGrants(ctx, "secretary").Return([]entity.Grant{grant(3, entity.RoleWrite)}, nil)
+				groups.EXPECT().GetAncestors(ctx, 4).Return([]entity.Group{_chemistry, _university}, nil)
+			},
+			res: entity.ImportReport{},
+			err: entity.ErrAccessDenied,
+		},
+		{
+			name: "read role",
+			rows: []entity.StudentImport{{Row: 2, Student: ann, Group: "3"}},
+			mock: func() {
+				groups.EXPECT().FindGroups(ctx, []int{3}, []string{}).Return([]entity.Group{_physics}, nil)
+				grants.EXPECT().GetGrants(ctx, "secretary").Return([]entity.Grant{grant(3, entity.RoleRead)}, nil)
+				groups.EXPECT().GetAncestors(ctx, 3).Return([]entity.Group{_physics, _university}, nil)
+			},
+			res: entity.ImportReport{},
+			err: entity.ErrAccessDenied,
+		},
+		{
+			name: "repo error",
+			rows: []entity.StudentImport{{Row: 2, Student: ann, Group: "3"}},
+			mock: func() {
+				groups.EXPECT().FindGroups(ctx, []int{3}, []string{}).Return(nil, errInternalServErr)
+			},
+			res: entity.ImportReport{},
+			err: errInternalServErr,
+		},
+	}
+
+	for _, tc := range tests { //nolint:paralleltest // data races here
+		localTc := tc
+
+		t.Run(localTc.name, func(t *testing.T) {
+			localTc.mock()
+
+			res, err := decorator.ImportStudents(ctx, localTc.rows, localTc.dryRun)
+
+			require.Equal(t, localTc.res, res)
+			require.ErrorIs(t, err, localTc.err)
+		})
+	}
+}
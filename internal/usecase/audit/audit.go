@@ -0,0 +1,115 @@
+// Package audit implements the audit log use case and the decorators that record
+// every change made through the student and group use cases.
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/repo"
+)
+
+// UseCase implements the audit use case interface.
+type UseCase struct {
+	repo repo.AuditRepo
+}
+
+// New creates a new audit use case.
+func New(r repo.AuditRepo) *UseCase {
+	return &UseCase{
+		repo: r,
+	}
+}
+
+// GetAudit retrieves one page of audit entries matching the filter.
+func (uc *UseCase) GetAudit(ctx context.Context, filter entity.AuditFilter, page entity.PageRequest) (entity.Page[entity.AuditEntry], error) {
+	entries, err := uc.repo.GetAudit(ctx, filter, page)
+	if err != nil {
+		return entity.Page[entity.AuditEntry]{}, fmt.Errorf("AuditUseCase - GetAudit - uc.repo.GetAudit: %w", err)
+	}
+
+	return entries, nil
+}
+
+// recorder stores the audit entries of changes in the transaction that makes the changes.
+type recorder struct {
+	log repo.AuditRepo
+	tx  repo.Transactor
+}
+
+// record runs change in a transaction together with storing the audit entries it returns,
+// so that no change is ever stored without its entries.
+func (r recorder) record(ctx context.Context, change func(ctx context.Context) ([]entity.AuditEntry, error)) error {
+	err := r.tx.WithinTx(ctx, func(ctx context.Context) error {
+		entries, err := change(ctx)
+		if err != nil {
+			return err
+		}
+
+		if err = r.log.StoreAudit(ctx, entries...); err != nil {
+			return fmt.Errorf("r.log.StoreAudit: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("r.tx.WithinTx: %w", err)
+	}
+
+	return nil
+}
+
+// loadBatch loads the items that the update and delete operations of a batch are about to change.
+// Items that do not exist are left out, their operations fail anyway.
+func loadBatch[T any](ctx context.Context, ops []entity.BatchOp[T], id func(T) int,
+	get func(ctx context.Context, id int) (T, error),
+) (map[int]any, error) {
+	before := make(map[int]any)
+
+	for _, op := range ops {
+		if op.Op != entity.BatchUpdate && op.Op != entity.BatchDelete {
+			continue
+		}
+
+		item, err := get(ctx, id(op.Item))
+		if errors.Is(err, entity.ErrNotFound) {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		before[id(op.Item)] = item
+	}
+
+	return before, nil
+}
+
+// batchEntries records the applied operations of a batch, before holds the items loaded by loadBatch.
+func batchEntries[T any](ctx context.Context, kind string, ops []entity.BatchOp[T], report entity.BatchReport[T],
+	before map[int]any, id func(T) int,
+) []entity.AuditEntry {
+	entries := make([]entity.AuditEntry, 0, report.Applied)
+
+	for i, result := range report.Results {
+		if result.Status != entity.BatchApplied {
+			continue
+		}
+
+		switch ops[i].Op {
+		case entity.BatchCreate:
+			entries = append(entries, entity.NewAuditEntry(ctx, entity.AuditCreate, kind, id(*result.Item), nil, *result.Item))
+		case entity.BatchUpdate:
+			itemID := id(*result.Item)
+			entries = append(entries, entity.NewAuditEntry(ctx, entity.AuditUpdate, kind, itemID, before[itemID], *result.Item))
+		case entity.BatchDelete:
+			itemID := id(ops[i].Item)
+			entries = append(entries, entity.NewAuditEntry(ctx, entity.AuditDelete, kind, itemID, before[itemID], nil))
+		}
+	}
+
+	return entries
+}
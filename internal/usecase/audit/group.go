@@ -0,0 +1,265 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/repo"
+	"github.com/evrone/go-clean-template/internal/usecase"
+)
+
+// Group records every change made through a group use case in the audit log, reads pass through.
+// Students and subgroups moved or deleted along with a group are counted in the entry of the group,
+// every such student gets an entry of its own as well.
+type Group struct {
+	usecase.Group
+	recorder
+}
+
+// NewGroup wraps a group use case.
+func NewGroup(uc usecase.Group, log repo.AuditRepo, tx repo.Transactor) *Group {
+	return &Group{
+		Group:    uc,
+		recorder: recorder{log: log, tx: tx},
+	}
+}
+
+func groupID(g entity.Group) int {
+	return g.ID
+}
+
+// CreateGroup creates a group and records it.
+func (d *Group) CreateGroup(ctx context.Context, group entity.Group) (entity.Group, error) {
+	var created entity.Group
+
+	err := d.record(ctx, func(ctx context.Context) ([]entity.AuditEntry, error) {
+		var err error
+
+		created, err = d.Group.CreateGroup(ctx, group)
+		if err != nil {
+			return nil, fmt.Errorf("d.Group.CreateGroup: %w", err)
+		}
+
+		return []entity.AuditEntry{
+			entity.NewAuditEntry(ctx, entity.AuditCreate, entity.AuditGroup, created.ID, nil, created),
+		}, nil
+	})
+	if err != nil {
+		return entity.Group{}, fmt.Errorf("AuditGroup - CreateGroup - d.record: %w", err)
+	}
+
+	return created, nil
+}
+
+// UpdateGroup updates a group and records the changed fields.
+func (d *Group) UpdateGroup(ctx context.Context, group entity.Group) (entity.Group, error) {
+	var updated entity.Group
+
+	err := d.record(ctx, func(ctx context.Context) ([]entity.AuditEntry, error) {
+		before, err := d.Group.GetGroupByID(ctx, group.ID)
+		if err != nil {
+			return nil, fmt.Errorf("d.Group.GetGroupByID: %w", err)
+		}
+
+		updated, err = d.Group.UpdateGroup(ctx, group)
+		if err != nil {
+			return nil, fmt.Errorf("d.Group.UpdateGroup: %w", err)
+		}
+
+		return []entity.AuditEntry{
+			entity.NewAuditEntry(ctx, entity.AuditUpdate, entity.AuditGroup, group.ID, before, updated),
+		}, nil
+	})
+	if err != nil {
+		return entity.Group{}, fmt.Errorf("AuditGroup - UpdateGroup - d.record: %w", err)
+	}
+
+	return updated, nil
+}
+
+// PatchGroup partially updates a group and records the changed fields.
+func (d *Group) PatchGroup(ctx context.Context, id int, patch entity.GroupPatch) (entity.Group, error) {
+	var patched entity.Group
+
+	err := d.record(ctx, func(ctx context.Context) ([]entity.AuditEntry, error) {
+		before, err := d.Group.GetGroupByID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("d.Group.GetGroupByID: %w", err)
+		}
+
+		patched, err = d.Group.PatchGroup(ctx, id, patch)
+		if err != nil {
+			return nil, fmt.Errorf("d.Group.PatchGroup: %w", err)
+		}
+
+		return []entity.AuditEntry{
+			entity.NewAuditEntry(ctx, entity.AuditUpdate, entity.AuditGroup, id, before, patched),
+		}, nil
+	})
+	if err != nil {
+		return entity.Group{}, fmt.Errorf("AuditGroup - PatchGroup - d.record: %w", err)
+	}
+
+	return patched, nil
+}
+
+// DeleteGroup deletes a group and records its last state. Deleting with reassignment is recorded as a merge.
+func (d *Group) DeleteGroup(ctx context.Context, id, version int, deletion entity.GroupDeletion) (entity.GroupChange, error) {
+	var change entity.GroupChange
+
+	err := d.record(ctx, func(ctx context.Context) ([]entity.AuditEntry, error) {
+		before, err := d.Group.GetGroupByID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("d.Group.GetGroupByID: %w", err)
+		}
+
+		change, err = d.Group.DeleteGroup(ctx, id, version, deletion)
+		if err != nil {
+			return nil, fmt.Errorf("d.Group.DeleteGroup: %w", err)
+		}
+
+		switch deletion.Mode {
+		case entity.DeleteReassign:
+			return mergeEntries(ctx, before, change), nil
+		case entity.DeleteCascade:
+			entries := []entity.AuditEntry{
+				entity.NewAuditEntry(ctx, entity.AuditDelete, entity.AuditGroup, id, before, map[string]int{
+					"students":  change.Students,
+					"subgroups": change.Subgroups,
+				}),
+			}
+
+			for _, studentID := range change.StudentIDs {
+				entries = append(entries, entity.NewAuditEntry(ctx, entity.AuditDelete, entity.AuditStudent, studentID,
+					nil, map[string]int{"deleted_with_group": id}))
+			}
+
+			return entries, nil
+		default:
+			return []entity.AuditEntry{
+				entity.NewAuditEntry(ctx, entity.AuditDelete, entity.AuditGroup, id, before, nil),
+			}, nil
+		}
+	})
+	if err != nil {
+		return entity.GroupChange{}, fmt.Errorf("AuditGroup - DeleteGroup - d.record: %w", err)
+	}
+
+	return change, nil
+}
+
+// RestoreGroup restores a deleted group and records it.
+func (d *Group) RestoreGroup(ctx context.Context, id int) (entity.Group, error) {
+	var restored entity.Group
+
+	err := d.record(ctx, func(ctx context.Context) ([]entity.AuditEntry, error) {
+		var err error
+
+		restored, err = d.Group.RestoreGroup(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("d.Group.RestoreGroup: %w", err)
+		}
+
+		return []entity.AuditEntry{
+			entity.NewAuditEntry(ctx, entity.AuditRestore, entity.AuditGroup, id, nil, restored),
+		}, nil
+	})
+	if err != nil {
+		return entity.Group{}, fmt.Errorf("AuditGroup - RestoreGroup - d.record: %w", err)
+	}
+
+	return restored, nil
+}
+
+// MoveGroup moves a group and records its new parent.
+func (d *Group) MoveGroup(ctx context.Context, id int, parentID *int, version int) (entity.GroupChange, error) {
+	var change entity.GroupChange
+
+	err := d.record(ctx, func(ctx context.Context) ([]entity.AuditEntry, error) {
+		before, err := d.Group.GetGroupByID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("d.Group.GetGroupByID: %w", err)
+		}
+
+		change, err = d.Group.MoveGroup(ctx, id, parentID, version)
+		if err != nil {
+			return nil, fmt.Errorf("d.Group.MoveGroup: %w", err)
+		}
+
+		return []entity.AuditEntry{
+			entity.NewAuditEntry(ctx, entity.AuditMove, entity.AuditGroup, id, before, change.Group),
+		}, nil
+	})
+	if err != nil {
+		return entity.GroupChange{}, fmt.Errorf("AuditGroup - MoveGroup - d.record: %w", err)
+	}
+
+	return change, nil
+}
+
+// MergeGroup merges a group into another one and records the merge.
+func (d *Group) MergeGroup(ctx context.Context, id, targetID, version int) (entity.GroupChange, error) {
+	var change entity.GroupChange
+
+	err := d.record(ctx, func(ctx context.Context) ([]entity.AuditEntry, error) {
+		before, err := d.Group.GetGroupByID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("d.Group.GetGroupByID: %w", err)
+		}
+
+		change, err = d.Group.MergeGroup(ctx, id, targetID, version)
+		if err != nil {
+			return nil, fmt.Errorf("d.Group.MergeGroup: %w", err)
+		}
+
+		return mergeEntries(ctx, before, change), nil
+	})
+	if err != nil {
+		return entity.GroupChange{}, fmt.Errorf("AuditGroup - MergeGroup - d.record: %w", err)
+	}
+
+	return change, nil
+}
+
+// mergeEntries records a group merged into change.Group and every student moved along.
+func mergeEntries(ctx context.Context, merged entity.Group, change entity.GroupChange) []entity.AuditEntry {
+	entries := []entity.AuditEntry{
+		entity.NewAuditEntry(ctx, entity.AuditMerge, entity.AuditGroup, merged.ID, merged, map[string]int{
+			"merged_into": change.Group.ID,
+			"students":    change.Students,
+			"subgroups":   change.Subgroups,
+		}),
+	}
+
+	for _, studentID := range change.StudentIDs {
+		entries = append(entries, entity.NewAuditEntry(ctx, entity.AuditUpdate, entity.AuditStudent, studentID,
+			map[string]int{"group_id": merged.ID}, map[string]int{"group_id": change.Group.ID}))
+	}
+
+	return entries
+}
+
+// BatchGroups applies a batch and records every applied operation.
+func (d *Group) BatchGroups(ctx context.Context, ops []entity.BatchOp[entity.Group], atomic bool) (entity.BatchReport[entity.Group], error) {
+	var report entity.BatchReport[entity.Group]
+
+	err := d.record(ctx, func(ctx context.Context) ([]entity.AuditEntry, error) {
+		before, err := loadBatch(ctx, ops, groupID, d.Group.GetGroupByID)
+		if err != nil {
+			return nil, fmt.Errorf("loadBatch: %w", err)
+		}
+
+		report, err = d.Group.BatchGroups(ctx, ops, atomic)
+		if err != nil {
+			return nil, fmt.Errorf("d.Group.BatchGroups: %w", err)
+		}
+
+		return batchEntries(ctx, entity.AuditGroup, ops, report, before, groupID), nil
+	})
+	if err != nil {
+		return entity.BatchReport[entity.Group]{}, fmt.Errorf("AuditGroup - BatchGroups - d.record: %w", err)
+	}
+
+	return report, nil
+}
@@ -0,0 +1,244 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/repo"
+	"github.com/evrone/go-clean-template/internal/usecase"
+)
+
+// Student records every change made through a student use case in the audit log, reads pass through.
+type Student struct {
+	usecase.Student
+	recorder
+}
+
+// NewStudent wraps a student use case.
+func NewStudent(uc usecase.Student, log repo.AuditRepo, tx repo.Transactor) *Student {
+	return &Student{
+		Student:  uc,
+		recorder: recorder{log: log, tx: tx},
+	}
+}
+
+func studentID(s entity.Student) int {
+	return s.ID
+}
+
+// CreateStudent creates a student and records it.
+func (d *Student) CreateStudent(ctx context.Context, student entity.Student) (entity.Student, error) {
+	var created entity.Student
+
+	err := d.record(ctx, func(ctx context.Context) ([]entity.AuditEntry, error) {
+		var err error
+
+		created, err = d.Student.CreateStudent(ctx, student)
+		if err != nil {
+			return nil, fmt.Errorf("d.Student.CreateStudent: %w", err)
+		}
+
+		return []entity.AuditEntry{
+			entity.NewAuditEntry(ctx, entity.AuditCreate, entity.AuditStudent, created.ID, nil, created),
+		}, nil
+	})
+	if err != nil {
+		return entity.Student{}, fmt.Errorf("AuditStudent - CreateStudent - d.record: %w", err)
+	}
+
+	return created, nil
+}
+
+// UpdateStudent updates a student and records the changed fields.
+func (d *Student) UpdateStudent(ctx context.Context, student entity.Student) (entity.Student, error) {
+	var updated entity.Student
+
+	err := d.record(ctx, func(ctx context.Context) ([]entity.AuditEntry, error) {
+		before, err := d.Student.GetStudentByID(ctx, student.ID)
+		if err != nil {
+			return nil, fmt.Errorf("d.Student.GetStudentByID: %w", err)
+		}
+
+		updated, err = d.Student.UpdateStudent(ctx, student)
+		if err != nil {
+			return nil, fmt.Errorf("d.Student.UpdateStudent: %w", err)
+		}
+
+		return []entity.AuditEntry{
+			entity.NewAuditEntry(ctx, entity.AuditUpdate, entity.AuditStudent, student.ID, before, updated),
+		}, nil
+	})
+	if err != nil {
+		return entity.Student{}, fmt.Errorf("AuditStudent - UpdateStudent - d.record: %w", err)
+	}
+
+	return updated, nil
+}
+
+// PatchStudent partially updates a student and records the changed fields.
+func (d *Student) PatchStudent(ctx context.Context, id int, patch entity.StudentPatch) (entity.Student, error) {
+	var patched entity.Student
+
+	err := d.record(ctx, func(ctx context.Context) ([]entity.AuditEntry, error) {
+		before, err := d.Student.GetStudentByID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("d.Student.GetStudentByID: %w", err)
+		}
+
+		patched, err = d.Student.PatchStudent(ctx, id, patch)
+		if err != nil {
+			return nil, fmt.Errorf("d.Student.PatchStudent: %w", err)
+		}
+
+		return []entity.AuditEntry{
+			entity.NewAuditEntry(ctx, entity.AuditUpdate, entity.AuditStudent, id, before, patched),
+		}, nil
+	})
+	if err != nil {
+		return entity.Student{}, fmt.Errorf("AuditStudent - PatchStudent - d.record: %w", err)
+	}
+
+	return patched, nil
+}
+
+// ChangeStudentStatus changes the status of a student and records it.
+func (d *Student) ChangeStudentStatus(ctx context.Context, id int, change entity.StatusChange) (entity.Student, error) {
+	var changed entity.Student
+
+	err := d.record(ctx, func(ctx context.Context) ([]entity.AuditEntry, error) {
+		before, err := d.Student.GetStudentByID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("d.Student.GetStudentByID: %w", err)
+		}
+
+		changed, err = d.Student.ChangeStudentStatus(ctx, id, change)
+		if err != nil {
+			return nil, fmt.Errorf("d.Student.ChangeStudentStatus: %w", err)
+		}
+
+		return []entity.AuditEntry{
+			entity.NewAuditEntry(ctx, entity.AuditUpdate, entity.AuditStudent, id, before, changed),
+		}, nil
+	})
+	if err != nil {
+		return entity.Student{}, fmt.Errorf("AuditStudent - ChangeStudentStatus - d.record: %w", err)
+	}
+
+	return changed, nil
+}
+
+// DeleteStudent deletes a student and records its last state.
+func (d *Student) DeleteStudent(ctx context.Context, id, version int) error {
+	err := d.record(ctx, func(ctx context.Context) ([]entity.AuditEntry, error) {
+		before, err := d.Student.GetStudentByID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("d.Student.GetStudentByID: %w", err)
+		}
+
+		if err = d.Student.DeleteStudent(ctx, id, version); err != nil {
+			return nil, fmt.Errorf("d.Student.DeleteStudent: %w", err)
+		}
+
+		return []entity.AuditEntry{
+			entity.NewAuditEntry(ctx, entity.AuditDelete, entity.AuditStudent, id, before, nil),
+		}, nil
+	})
+	if err != nil {
+		return fmt.Errorf("AuditStudent - DeleteStudent - d.record: %w", err)
+	}
+
+	return nil
+}
+
+// RestoreStudent restores a deleted student and records it.
+func (d *Student) RestoreStudent(ctx context.Context, id int) (entity.Student, error) {
+	var restored entity.Student
+
+	err := d.record(ctx, func(ctx context.Context) ([]entity.AuditEntry, error) {
+		var err error
+
+		restored, err = d.Student.RestoreStudent(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("d.Student.RestoreStudent: %w", err)
+		}
+
+		return []entity.AuditEntry{
+			entity.NewAuditEntry(ctx, entity.AuditRestore, entity.AuditStudent, id, nil, restored),
+		}, nil
+	})
+	if err != nil {
+		return entity.Student{}, fmt.Errorf("AuditStudent - RestoreStudent - d.record: %w", err)
+	}
+
+	return restored, nil
+}
+
+// ImportStudents imports a roster and records every created student, dry runs are not recorded.
+func (d *Student) ImportStudents(ctx context.Context, rows []entity.StudentImport, dryRun bool) (entity.ImportReport, error) {
+	if dryRun {
+		report, err := d.Student.ImportStudents(ctx, rows, dryRun)
+		if err != nil {
+			return entity.ImportReport{}, fmt.Errorf("AuditStudent - ImportStudents - d.Student.ImportStudents: %w", err)
+		}
+
+		return report, nil
+	}
+
+	var report entity.ImportReport
+
+	err := d.record(ctx, func(ctx context.Context) ([]entity.AuditEntry, error) {
+		var err error
+
+		report, err = d.Student.ImportStudents(ctx, rows, dryRun)
+		if err != nil {
+			return nil, fmt.Errorf("d.Student.ImportStudents: %w", err)
+		}
+
+		entries := make([]entity.AuditEntry, 0, report.Created)
+
+		for _, row := range report.Rows {
+			if row.Status != entity.ImportCreated {
+				continue
+			}
+
+			created, err := d.Student.GetStudentByID(ctx, row.StudentID)
+			if err != nil {
+				return nil, fmt.Errorf("d.Student.GetStudentByID: %w", err)
+			}
+
+			entries = append(entries, entity.NewAuditEntry(ctx, entity.AuditCreate, entity.AuditStudent, created.ID, nil, created))
+		}
+
+		return entries, nil
+	})
+	if err != nil {
+		return entity.ImportReport{}, fmt.Errorf("AuditStudent - ImportStudents - d.record: %w", err)
+	}
+
+	return report, nil
+}
+
+// BatchStudents applies a batch and records every applied operation.
+func (d *Student) BatchStudents(ctx context.Context, ops []entity.BatchOp[entity.Student], atomic bool) (entity.BatchReport[entity.Student], error) {
+	var report entity.BatchReport[entity.Student]
+
+	err := d.record(ctx, func(ctx context.Context) ([]entity.AuditEntry, error) {
+		before, err := loadBatch(ctx, ops, studentID, d.Student.GetStudentByID)
+		if err != nil {
+			return nil, fmt.Errorf("loadBatch: %w", err)
+		}
+
+		report, err = d.Student.BatchStudents(ctx, ops, atomic)
+		if err != nil {
+			return nil, fmt.Errorf("d.Student.BatchStudents: %w", err)
+		}
+
+		return batchEntries(ctx, entity.AuditStudent, ops, report, before, studentID), nil
+	})
+	if err != nil {
+		return entity.BatchReport[entity.Student]{}, fmt.Errorf("AuditStudent - BatchStudents - d.record: %w", err)
+	}
+
+	return report, nil
+}
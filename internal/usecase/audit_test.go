@@ -0,0 +1,283 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/usecase/audit"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func auditedStudents(t *testing.T) (*audit.Student, *MockStudent, *MockAuditRepo) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	students := NewMockStudent(mockCtl)
+	log := NewMockAuditRepo(mockCtl)
+
+	return audit.NewStudent(students, log, transactor(mockCtl)), students, log
+}
+
+func TestAuditPatchStudent(t *testing.T) { //nolint:tparallel // data races here
+	t.Parallel()
+
+	decorator, students, log := auditedStudents(t)
+
+	ctx := entity.WithRequestID(entity.WithPrincipal(context.Background(), entity.Principal{Subject: "admin"}), "req-1")
+	before := entity.Student{ID: 1, Name: "John Doe", GroupID: 2, Version: 1}
+	after := entity.Student{ID: 1, Name: "John Doe", GroupID: 3, Version: 2}
+	patch := entity.StudentPatch{GroupID: intPtr(3), Version: 1}
+
+	tests := []struct {
+		name string
+		mock func()
+		res  entity.Student
+		err  error
+	}{
+		{
+			name: "group changed",
+			mock: func() {
+				students.EXPECT().GetStudentByID(ctx, 1).Return(before, nil)
+				students.EXPECT().PatchStudent(ctx, 1, patch).Return(after, nil)
+				log.EXPECT().StoreAudit(ctx, entity.AuditEntry{
+					Actor:     "admin",
+					Action:    entity.AuditUpdate,
+					Entity:    entity.AuditStudent,
+					EntityID:  1,
+					Diff:      map[string]entity.AuditChange{"group_id": {Before: 2.0, After: 3.0}},
+					RequestID: "req-1",
+				}).Return(nil)
+			},
+			res: after,
+			err: nil,
+		},
+		{
+			name: "personal data masked",
+			mock: func() {
+				contact := before
+				contact.Email, contact.Phone = "john@example.com", "+4915112345678"
+				changed := after
+				changed.Email, changed.Phone = "jdoe@example.com", "+4915187654321"
+				changed.DateOfBirth = &entity.Date{Time: time.Date(2005, 3, 1, 0, 0, 0, 0, time.UTC)}
+
+				students.EXPECT().GetStudentByID(ctx, 1).Return(contact, nil)
+				students.EXPECT().PatchStudent(ctx, 1, patch).Return(changed, nil)
+				log.EXPECT().StoreAudit(ctx, entity.AuditEntry{
+					Actor:    "admin",
+					Action:   entity.AuditUpdate,
+					Entity:   entity.AuditStudent,
+					EntityID: 1,
+					Diff: map[string]entity.AuditChange{
+						"group_id":      {Before: 2.0, After: 3.0},
+						"email":         {Before: "j***@example.com", After: "j***@example.com"},
+						"phone":         {Before: "+49********678", After: "+49********321"},
+						"date_of_birth": {Before: nil, After: nil},
+					},
+					RequestID: "req-1",
+				}).Return(nil)
+			},
+			res: entity.Student{
+				ID: 1, Name: "John Doe", GroupID: 3, Version: 2, Email: "jdoe@example.com", Phone: "+4915187654321",
+				DateOfBirth: &entity.Date{Time: time.Date(2005, 3, 1, 0, 0, 0, 0, time.UTC)},
+			},
+			err: nil,
+		},
+		{
+			name: "stale version",
+			mock: func() {
+				students.EXPECT().GetStudentByID(ctx, 1).Return(before, nil)
+				students.EXPECT().PatchStudent(ctx, 1, patch).Return(entity.Student{}, entity.ErrVersionMismatch)
+			},
+			res: entity.Student{},
+			err: entity.ErrVersionMismatch,
+		},
+		{
+			name: "log error",
+			mock: func() {
+				students.EXPECT().GetStudentByID(ctx, 1).Return(before, nil)
+				students.EXPECT().PatchStudent(ctx, 1, patch).Return(after, nil)
+				log.EXPECT().StoreAudit(ctx, gomock.Any()).Return(errInternalServErr)
+			},
+			res: entity.Student{},
+			err: errInternalServErr,
+		},
+	}
+
+	for _, tc := range tests { //nolint:paralleltest // data races here
+		localTc := tc
+
+		t.Run(localTc.name, func(t *testing.T) {
+			localTc.mock()
+
+			res, err := decorator.PatchStudent(ctx, 1, patch)
+
+			require.Equal(t, localTc.res, res)
+			require.ErrorIs(t, err, localTc.err)
+		})
+	}
+}
+
+func TestAuditBatchStudents(t *testing.T) { //nolint:tparallel // data races here
+	t.Parallel()
+
+	decorator, students, log := auditedStudents(t)
+
+	ctx := context.Background()
+	ops := []entity.BatchOp[entity.Student]{
+		{Op: entity.BatchCreate, Item: entity.Student{Name: "Jane Roe", GroupID: 2}},
+		{Op: entity.BatchDelete, Item: entity.Student{ID: 1, Version: 1}},
+		{Op: entity.BatchDelete, Item: entity.Student{ID: 5, Version: 1}},
+	}
+	created := entity.Student{ID: 7, Name: "Jane Roe", GroupID: 2, Status: entity.StudentActive}
+	deleted := entity.Student{ID: 1, Name: "John Doe", GroupID: 2, Status: entity.StudentActive}
+
+	students.EXPECT().GetStudentByID(ctx, 1).Return(deleted, nil)
+	students.EXPECT().GetStudentByID(ctx, 5).Return(entity.Student{}, entity.ErrNotFound)
+	students.EXPECT().BatchStudents(ctx, ops, false).Return(entity.BatchReport[entity.Student]{
+		Total:   3,
+		Applied: 2,
+		Failed:  1,
+		Results: []entity.BatchResult[entity.Student]{
+			{Op: entity.BatchCreate, Status: entity.BatchApplied, Item: &created},
+			{Op: entity.BatchDelete, Status: entity.BatchApplied},
+			{Op: entity.BatchDelete, Status: entity.BatchFailed, Err: entity.ErrNotFound},
+		},
+	}, nil)
+	log.EXPECT().StoreAudit(ctx,
+		entity.AuditEntry{
+			Actor:    entity.AnonymousActor,
+			Action:   entity.AuditCreate,
+			Entity:   entity.AuditStudent,
+			EntityID: 7,
+			Diff: map[string]entity.AuditChange{
+				"id":       {After: 7.0},
+				"name":     {After: "Jane Roe"},
+				"group_id": {After: 2.0},
+				"status":   {After: entity.StudentActive},
+			},
+		},
+		entity.AuditEntry{
+			Actor:    entity.AnonymousActor,
+			Action:   entity.AuditDelete,
+			Entity:   entity.AuditStudent,
+			EntityID: 1,
+			Diff: map[string]entity.AuditChange{
+				"id":       {Before: 1.0},
+				"name":     {Before: "John Doe"},
+				"group_id": {Before: 2.0},
+				"status":   {Before: entity.StudentActive},
+			},
+		},
+	).Return(nil)
+
+	report, err := decorator.BatchStudents(ctx, ops, false)
+
+	require.NoError(t, err)
+	require.Equal(t, 2, report.Applied)
+}
+
+func TestAuditGroupStudents(t *testing.T) { //nolint:tparallel // data races here
+	t.Parallel()
+
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	groups := NewMockGroup(mockCtl)
+	log := NewMockAuditRepo(mockCtl)
+	decorator := audit.NewGroup(groups, log, transactor(mockCtl))
+
+	ctx := context.Background()
+	merged := entity.Group{ID: 3, Name: "Class A", Version: 1}
+	change := entity.GroupChange{Group: entity.Group{ID: 4, Name: "Class B"}, Students: 2, StudentIDs: []int{7, 8}}
+
+	tests := []struct {
+		name     string
+		mock     func()
+		run      func() (entity.GroupChange, error)
+		students []entity.AuditEntry
+	}{
+		{
+			name: "merge",
+			mock: func() {
+				groups.EXPECT().MergeGroup(ctx, 3, 4, 1).Return(change, nil)
+			},
+			run: func() (entity.GroupChange, error) {
+				return decorator.MergeGroup(ctx, 3, 4, 1)
+			},
+			students: []entity.AuditEntry{
+				{
+					Actor: entity.AnonymousActor, Action: entity.AuditUpdate, Entity: entity.AuditStudent, EntityID: 7,
+					Diff: map[string]entity.AuditChange{"group_id": {Before: 3.0, After: 4.0}},
+				},
+				{
+					Actor: entity.AnonymousActor, Action: entity.AuditUpdate, Entity: entity.AuditStudent, EntityID: 8,
+					Diff: map[string]entity.AuditChange{"group_id": {Before: 3.0, After: 4.0}},
+				},
+			},
+		},
+		{
+			name: "reassign",
+			mock: func() {
+				groups.EXPECT().DeleteGroup(ctx, 3, 1, entity.GroupDeletion{Mode: entity.DeleteReassign, Target: 4}).
+					Return(change, nil)
+			},
+			run: func() (entity.GroupChange, error) {
+				return decorator.DeleteGroup(ctx, 3, 1, entity.GroupDeletion{Mode: entity.DeleteReassign, Target: 4})
+			},
+			students: []entity.AuditEntry{
+				{
+					Actor: entity.AnonymousActor, Action: entity.AuditUpdate, Entity: entity.AuditStudent, EntityID: 7,
+					Diff: map[string]entity.AuditChange{"group_id": {Before: 3.0, After: 4.0}},
+				},
+				{
+					Actor: entity.AnonymousActor, Action: entity.AuditUpdate, Entity: entity.AuditStudent, EntityID: 8,
+					Diff: map[string]entity.AuditChange{"group_id": {Before: 3.0, After: 4.0}},
+				},
+			},
+		},
+		{
+			name: "cascade",
+			mock: func() {
+				groups.EXPECT().DeleteGroup(ctx, 3, 1, entity.GroupDeletion{Mode: entity.DeleteCascade}).
+					Return(entity.GroupChange{Students: 1, StudentIDs: []int{7}}, nil)
+			},
+			run: func() (entity.GroupChange, error) {
+				return decorator.DeleteGroup(ctx, 3, 1, entity.GroupDeletion{Mode: entity.DeleteCascade})
+			},
+			students: []entity.AuditEntry{
+				{
+					Actor: entity.AnonymousActor, Action: entity.AuditDelete, Entity: entity.AuditStudent, EntityID: 7,
+					Diff: map[string]entity.AuditChange{"deleted_with_group": {After: 3.0}},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests { //nolint:paralleltest // data races here
+		localTc := tc
+
+		t.Run(localTc.name, func(t *testing.T) {
+			var stored []entity.AuditEntry
+
+			groups.EXPECT().GetGroupByID(ctx, 3).Return(merged, nil)
+			localTc.mock()
+			log.EXPECT().StoreAudit(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, entries ...entity.AuditEntry) error {
+				stored = entries
+
+				return nil
+			})
+
+			_, err := localTc.run()
+
+			require.NoError(t, err)
+			require.Len(t, stored, len(localTc.students)+1)
+			require.Equal(t, entity.AuditGroup, stored[0].Entity)
+			require.Equal(t, localTc.students, stored[1:])
+		})
+	}
+}
@@ -0,0 +1,65 @@
+// Package batch runs batches of operations through the single-item use cases in one transaction,
+// so that every operation of a batch obeys the same rules as when it is applied on its own.
+package batch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/repo"
+)
+
+// errRollback undoes the transaction of a batch that is not to be committed.
+var errRollback = errors.New("rollback")
+
+// Run applies the operations in a single transaction, each under its own savepoint so that a failing
+// operation neither aborts the transaction nor hides the errors of the operations after it, and reports
+// the outcome of each. An atomic batch is committed only when every operation succeeds, otherwise the failed
+// operations are skipped and the others committed. Operations failing with a domain error are reported as failed,
+// any other error aborts the batch.
+func Run[T any](ctx context.Context, tx repo.Transactor, ops []entity.BatchOp[T], atomic bool,
+	apply func(ctx context.Context, op entity.BatchOp[T]) (T, error),
+) (entity.BatchReport[T], error) {
+	items := make([]T, len(ops))
+	opErrs := make([]error, len(ops))
+
+	err := tx.WithinTx(ctx, func(ctx context.Context) error {
+		failed := false
+
+		for i, op := range ops {
+			err := tx.WithinTx(ctx, func(ctx context.Context) error {
+				item, err := apply(ctx, op)
+				items[i] = item
+
+				return err
+			})
+
+			var domainErr *entity.Error
+			if errors.As(err, &domainErr) {
+				var zero T
+				items[i] = zero
+				opErrs[i] = domainErr
+				failed = true
+
+				continue
+			}
+
+			if err != nil {
+				return fmt.Errorf("apply: %w", err)
+			}
+		}
+
+		if failed && atomic {
+			return errRollback
+		}
+
+		return nil
+	})
+	if err != nil && !errors.Is(err, errRollback) {
+		return entity.BatchReport[T]{}, fmt.Errorf("tx.WithinTx: %w", err)
+	}
+
+	return entity.NewBatchReport(ops, items, opErrs, atomic), nil
+}
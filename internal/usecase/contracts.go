@@ -3,6 +3,7 @@ package usecase
 
 import (
 	"context"
+	"time"
 
 	"github.com/evrone/go-clean-template/internal/entity"
 )
@@ -18,20 +19,58 @@ type (
 	// Student -.
 	Student interface {
 		CreateStudent(ctx context.Context, student entity.Student) (entity.Student, error)
-		GetStudents(ctx context.Context) ([]entity.Student, error)
+		GetStudents(ctx context.Context, filter entity.StudentFilter, page entity.PageRequest) (entity.Page[entity.Student], error)
 		GetStudentByID(ctx context.Context, id int) (entity.Student, error)
-		UpdateStudent(ctx context.Context, student entity.Student) error
-		DeleteStudent(ctx context.Context, id int) error
-		SearchStudents(ctx context.Context, query string) ([]entity.Student, error)
+		GetStudentWithPath(ctx context.Context, id int) (entity.Student, error)
+		GetGroupStudents(ctx context.Context, groupID int, recursive bool, asOf *time.Time, page entity.PageRequest) (entity.Page[entity.Student], error)
+		GetStudentEnrollments(ctx context.Context, id int) ([]entity.Enrollment, error)
+		UpdateStudent(ctx context.Context, student entity.Student) (entity.Student, error)
+		PatchStudent(ctx context.Context, id int, patch entity.StudentPatch) (entity.Student, error)
+		ChangeStudentStatus(ctx context.Context, id int, change entity.StatusChange) (entity.Student, error)
+		DeleteStudent(ctx context.Context, id, version int) error
+		RestoreStudent(ctx context.Context, id int) (entity.Student, error)
+		ImportStudents(ctx context.Context, rows []entity.StudentImport, dryRun bool) (entity.ImportReport, error)
+		BatchStudents(ctx context.Context, ops []entity.BatchOp[entity.Student], atomic bool) (entity.BatchReport[entity.Student], error)
+		ExportStudents(ctx context.Context, filter entity.StudentFilter) (entity.Export[entity.Student], error)
+		SearchStudents(ctx context.Context, query entity.SearchQuery) ([]entity.StudentHit, error)
 	}
 
 	// Group -.
 	Group interface {
 		CreateGroup(ctx context.Context, group entity.Group) (entity.Group, error)
-		GetGroups(ctx context.Context) ([]entity.Group, error)
+		GetGroups(ctx context.Context, filter entity.GroupFilter, page entity.PageRequest) (entity.Page[entity.Group], error)
 		GetGroupByID(ctx context.Context, id int) (entity.Group, error)
-		UpdateGroup(ctx context.Context, group entity.Group) error
-		DeleteGroup(ctx context.Context, id int) error
-		SearchGroups(ctx context.Context, query string) ([]entity.Group, error)
+		GetGroupWithSubgroups(ctx context.Context, id, maxDepth int) (entity.Group, error)
+		GetGroupPath(ctx context.Context, id int) ([]entity.GroupRef, error)
+		UpdateGroup(ctx context.Context, group entity.Group) (entity.Group, error)
+		PatchGroup(ctx context.Context, id int, patch entity.GroupPatch) (entity.Group, error)
+		DeleteGroup(ctx context.Context, id, version int, deletion entity.GroupDeletion) (entity.GroupChange, error)
+		RestoreGroup(ctx context.Context, id int) (entity.Group, error)
+		MoveGroup(ctx context.Context, id int, parentID *int, version int) (entity.GroupChange, error)
+		MergeGroup(ctx context.Context, id, targetID, version int) (entity.GroupChange, error)
+		BatchGroups(ctx context.Context, ops []entity.BatchOp[entity.Group], atomic bool) (entity.BatchReport[entity.Group], error)
+		ExportGroups(ctx context.Context, filter entity.GroupFilter) (entity.Export[entity.GroupPath], error)
+		GetGroupStats(ctx context.Context) (entity.GroupStatsSummary, error)
+		GetGroupStatsByID(ctx context.Context, id int) (entity.GroupStats, error)
+		SearchGroups(ctx context.Context, query entity.SearchQuery) ([]entity.GroupHit, error)
+	}
+
+	// Audit -.
+	Audit interface {
+		GetAudit(ctx context.Context, filter entity.AuditFilter, page entity.PageRequest) (entity.Page[entity.AuditEntry], error)
+	}
+
+	// Access -.
+	Access interface {
+		GetGrants(ctx context.Context, subject string) ([]entity.Grant, error)
+		CreateGrant(ctx context.Context, grant entity.Grant) (entity.Grant, error)
+		DeleteGrant(ctx context.Context, id int) error
+	}
+
+	// Privacy -.
+	Privacy interface {
+		StudentView(ctx context.Context, endpoint string) (func(entity.Student) (string, error), error)
+		StudentMask(ctx context.Context, endpoint string) (func(*entity.Student) error, error)
+		GetStudentContact(ctx context.Context, id int) (entity.StudentContact, error)
 	}
 )
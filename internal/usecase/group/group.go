@@ -3,38 +3,61 @@ package group
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/evrone/go-clean-template/internal/entity"
 	"github.com/evrone/go-clean-template/internal/repo"
+	"github.com/evrone/go-clean-template/internal/usecase/batch"
+	"github.com/evrone/go-clean-template/pkg/translit"
 )
 
+// errNotFound reports a missing or deleted group the way the repository does.
+var errNotFound = entity.NewError(entity.ErrNotFound, "group_not_found", "group not found")
+
 // UseCase implements the group use case interface.
 type UseCase struct {
 	repo repo.GroupRepo
+	tx   repo.Transactor
 }
 
 // New creates a new group use case.
-func New(r repo.GroupRepo) *UseCase {
+func New(r repo.GroupRepo, tx repo.Transactor) *UseCase {
 	return &UseCase{
 		repo: r,
+		tx:   tx,
 	}
 }
 
-// CreateGroup creates a new group.
+// CreateGroup creates a new group, checking the parent in the same transaction.
 func (uc *UseCase) CreateGroup(ctx context.Context, group entity.Group) (entity.Group, error) {
-	g, err := uc.repo.CreateGroup(ctx, group)
+	var created entity.Group
+
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.checkParent(ctx, group); err != nil {
+			return fmt.Errorf("uc.checkParent: %w", err)
+		}
+
+		g, err := uc.repo.CreateGroup(ctx, group)
+		if err != nil {
+			return fmt.Errorf("uc.repo.CreateGroup: %w", err)
+		}
+
+		created = g
+
+		return nil
+	})
 	if err != nil {
-		return entity.Group{}, fmt.Errorf("GroupUseCase - CreateGroup - uc.repo.CreateGroup: %w", err)
+		return entity.Group{}, fmt.Errorf("GroupUseCase - CreateGroup - uc.tx.WithinTx: %w", err)
 	}
 
-	return g, nil
+	return created, nil
 }
 
-// GetGroups retrieves all groups with their subgroups.
-func (uc *UseCase) GetGroups(ctx context.Context) ([]entity.Group, error) {
-	groups, err := uc.repo.GetGroups(ctx)
+// GetGroups retrieves one page of top-level groups with their subgroups.
+func (uc *UseCase) GetGroups(ctx context.Context, filter entity.GroupFilter, page entity.PageRequest) (entity.Page[entity.Group], error) {
+	groups, err := uc.repo.GetGroups(ctx, filter, page)
 	if err != nil {
-		return nil, fmt.Errorf("GroupUseCase - GetGroups - uc.repo.GetGroups: %w", err)
+		return entity.Page[entity.Group]{}, fmt.Errorf("GroupUseCase - GetGroups - uc.repo.GetGroups: %w", err)
 	}
 
 	return groups, nil
@@ -50,38 +73,331 @@ func (uc *UseCase) GetGroupByID(ctx context.Context, id int) (entity.Group, erro
 	return group, nil
 }
 
-// UpdateGroup updates an existing group.
-func (uc *UseCase) UpdateGroup(ctx context.Context, group entity.Group) error {
-	err := uc.repo.UpdateGroup(ctx, group)
+// GetGroupWithSubgroups retrieves a group with its subgroups down to maxDepth levels.
+func (uc *UseCase) GetGroupWithSubgroups(ctx context.Context, id, maxDepth int) (entity.Group, error) {
+	group, err := uc.repo.GetGroupWithSubgroups(ctx, id, maxDepth)
 	if err != nil {
-		return fmt.Errorf("GroupUseCase - UpdateGroup - uc.repo.UpdateGroup: %w", err)
+		return entity.Group{}, fmt.Errorf("GroupUseCase - GetGroupWithSubgroups - uc.repo.GetGroupWithSubgroups: %w", err)
 	}
 
-	return nil
+	return group, nil
+}
+
+// GetGroupPath retrieves the path from the root group down to an active group.
+func (uc *UseCase) GetGroupPath(ctx context.Context, id int) ([]entity.GroupRef, error) {
+	ancestors, err := uc.repo.GetAncestors(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("GroupUseCase - GetGroupPath - uc.repo.GetAncestors: %w", err)
+	}
+
+	if len(ancestors) == 0 {
+		return nil, errNotFound
+	}
+
+	return entity.NewPath(ancestors), nil
+}
+
+// UpdateGroup updates an existing group and returns the stored group, checking the parent in the same transaction.
+func (uc *UseCase) UpdateGroup(ctx context.Context, group entity.Group) (entity.Group, error) {
+	var updated entity.Group
+
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.checkParent(ctx, group); err != nil {
+			return fmt.Errorf("uc.checkParent: %w", err)
+		}
+
+		g, err := uc.repo.UpdateGroup(ctx, group)
+		if err != nil {
+			return fmt.Errorf("uc.repo.UpdateGroup: %w", err)
+		}
+
+		updated = g
+
+		return nil
+	})
+	if err != nil {
+		return entity.Group{}, fmt.Errorf("GroupUseCase - UpdateGroup - uc.tx.WithinTx: %w", err)
+	}
+
+	return updated, nil
 }
 
-// DeleteGroup deletes a group by ID.
-func (uc *UseCase) DeleteGroup(ctx context.Context, id int) error {
-	// Check if the group has subgroups
+// PatchGroup applies a partial update to a group, checking a new parent in the same transaction.
+func (uc *UseCase) PatchGroup(ctx context.Context, id int, patch entity.GroupPatch) (entity.Group, error) {
+	var group entity.Group
+
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if patch.SetParent {
+			if err := uc.checkParent(ctx, entity.Group{ID: id, ParentID: patch.ParentID}); err != nil {
+				return fmt.Errorf("uc.checkParent: %w", err)
+			}
+		}
+
+		g, err := uc.repo.PatchGroup(ctx, id, patch)
+		if err != nil {
+			return fmt.Errorf("uc.repo.PatchGroup: %w", err)
+		}
+
+		group = g
+
+		return nil
+	})
+	if err != nil {
+		return entity.Group{}, fmt.Errorf("GroupUseCase - PatchGroup - uc.tx.WithinTx: %w", err)
+	}
+
+	return group, nil
+}
+
+// DeleteGroup soft-deletes a group by ID, a non-zero version must match the stored one.
+// Restrict mode refuses groups with active subgroups or students, cascade mode deletes them along with
+// the group and reassign mode moves them to the target group first. The result counts them.
+func (uc *UseCase) DeleteGroup(ctx context.Context, id, version int, deletion entity.GroupDeletion) (entity.GroupChange, error) {
+	if deletion.Mode == entity.DeleteReassign {
+		change, err := uc.MergeGroup(ctx, id, deletion.Target, version)
+		if err != nil {
+			return entity.GroupChange{}, fmt.Errorf("GroupUseCase - DeleteGroup - uc.MergeGroup: %w", err)
+		}
+
+		return change, nil
+	}
+
+	var change entity.GroupChange
+
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if deletion.Mode == entity.DeleteCascade {
+			change.Subgroups, change.StudentIDs, err = uc.repo.DeleteDescendants(ctx, id)
+			if err != nil {
+				return fmt.Errorf("uc.repo.DeleteDescendants: %w", err)
+			}
+
+			change.Students = len(change.StudentIDs)
+		} else if err = uc.checkEmpty(ctx, id); err != nil {
+			return fmt.Errorf("uc.checkEmpty: %w", err)
+		}
+
+		if err = uc.repo.DeleteGroup(ctx, id, version); err != nil {
+			return fmt.Errorf("uc.repo.DeleteGroup: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.GroupChange{}, fmt.Errorf("GroupUseCase - DeleteGroup - uc.tx.WithinTx: %w", err)
+	}
+
+	return change, nil
+}
+
+// checkEmpty makes sure a group has neither active subgroups nor active students.
+func (uc *UseCase) checkEmpty(ctx context.Context, id int) error {
 	hasSubgroups, err := uc.repo.HasSubgroups(ctx, id)
 	if err != nil {
-		return fmt.Errorf("GroupUseCase - DeleteGroup - uc.repo.HasSubgroups: %w", err)
+		return fmt.Errorf("uc.repo.HasSubgroups: %w", err)
 	}
 
 	if hasSubgroups {
-		return fmt.Errorf("cannot delete group with subgroups")
+		return entity.ErrGroupHasSubgroups
 	}
 
-	err = uc.repo.DeleteGroup(ctx, id)
+	hasStudents, err := uc.repo.HasStudents(ctx, id)
 	if err != nil {
-		return fmt.Errorf("GroupUseCase - DeleteGroup - uc.repo.DeleteGroup: %w", err)
+		return fmt.Errorf("uc.repo.HasStudents: %w", err)
+	}
+
+	if hasStudents {
+		return entity.ErrGroupHasStudents
 	}
 
 	return nil
 }
 
-// SearchGroups searches for groups by name.
-func (uc *UseCase) SearchGroups(ctx context.Context, query string) ([]entity.Group, error) {
+// RestoreGroup restores a soft-deleted group.
+func (uc *UseCase) RestoreGroup(ctx context.Context, id int) (entity.Group, error) {
+	group, err := uc.repo.RestoreGroup(ctx, id)
+	if err != nil {
+		return entity.Group{}, fmt.Errorf("GroupUseCase - RestoreGroup - uc.repo.RestoreGroup: %w", err)
+	}
+
+	return group, nil
+}
+
+// MoveGroup moves a group with its whole subtree under a new parent, a nil parent makes it a root group.
+// A non-zero version must match the stored one.
+func (uc *UseCase) MoveGroup(ctx context.Context, id int, parentID *int, version int) (entity.GroupChange, error) {
+	var change entity.GroupChange
+
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.checkParent(ctx, entity.Group{ID: id, ParentID: parentID}); err != nil {
+			return fmt.Errorf("uc.checkParent: %w", err)
+		}
+
+		group, err := uc.repo.PatchGroup(ctx, id, entity.GroupPatch{ParentID: parentID, SetParent: true, Version: version})
+		if err != nil {
+			return fmt.Errorf("uc.repo.PatchGroup: %w", err)
+		}
+
+		subgroups, students, err := uc.repo.SubtreeSize(ctx, id)
+		if err != nil {
+			return fmt.Errorf("uc.repo.SubtreeSize: %w", err)
+		}
+
+		change = entity.GroupChange{Group: group, Students: students, Subgroups: subgroups}
+
+		return nil
+	})
+	if err != nil {
+		return entity.GroupChange{}, fmt.Errorf("GroupUseCase - MoveGroup - uc.tx.WithinTx: %w", err)
+	}
+
+	return change, nil
+}
+
+// MergeGroup moves the students and subgroups of a group into the target group and deletes it.
+// A non-zero version must match the stored version of the merged group.
+func (uc *UseCase) MergeGroup(ctx context.Context, id, targetID, version int) (entity.GroupChange, error) {
+	if id == targetID {
+		return entity.GroupChange{}, fmt.Errorf("GroupUseCase - MergeGroup: %w", entity.ErrMergeCycle)
+	}
+
+	var change entity.GroupChange
+
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		// Locked like in checkParent, so that no group can be moved under the merged group or the target
+		// into a cycle until the merge is committed
+		if err := uc.repo.LockTree(ctx); err != nil {
+			return fmt.Errorf("uc.repo.LockTree: %w", err)
+		}
+
+		// The target group and its ancestors, the merged group must not be among them
+		ancestors, err := uc.repo.GetAncestors(ctx, targetID)
+		if err != nil {
+			return fmt.Errorf("uc.repo.GetAncestors: %w", err)
+		}
+
+		if len(ancestors) == 0 {
+			return entity.ErrTargetNotFound
+		}
+
+		for _, a := range ancestors {
+			if a.ID == id {
+				return entity.ErrMergeCycle
+			}
+		}
+
+		students, err := uc.repo.MoveStudents(ctx, id, targetID)
+		if err != nil {
+			return fmt.Errorf("uc.repo.MoveStudents: %w", err)
+		}
+
+		subgroups, err := uc.repo.MoveSubgroups(ctx, id, targetID)
+		if err != nil {
+			return fmt.Errorf("uc.repo.MoveSubgroups: %w", err)
+		}
+
+		if err = uc.repo.DeleteGroup(ctx, id, version); err != nil {
+			return fmt.Errorf("uc.repo.DeleteGroup: %w", err)
+		}
+
+		target, err := uc.repo.GetGroupByID(ctx, targetID)
+		if err != nil {
+			return fmt.Errorf("uc.repo.GetGroupByID: %w", err)
+		}
+
+		change = entity.GroupChange{Group: target, Students: len(students), Subgroups: subgroups, StudentIDs: students}
+
+		return nil
+	})
+	if err != nil {
+		return entity.GroupChange{}, fmt.Errorf("GroupUseCase - MergeGroup - uc.tx.WithinTx: %w", err)
+	}
+
+	return change, nil
+}
+
+// BatchGroups applies the operations in a single transaction and reports the outcome of each.
+// Every operation sees the groups as left by the operations before it and goes through the same checks
+// as a single create, update or restrict mode delete.
+// Atomic batches are committed only when every operation succeeds, otherwise the failed operations are skipped.
+func (uc *UseCase) BatchGroups(ctx context.Context, ops []entity.BatchOp[entity.Group], atomic bool) (entity.BatchReport[entity.Group], error) {
+	report, err := batch.Run(ctx, uc.tx, ops, atomic, uc.applyBatchOp)
+	if err != nil {
+		return entity.BatchReport[entity.Group]{}, fmt.Errorf("GroupUseCase - BatchGroups - batch.Run: %w", err)
+	}
+
+	return report, nil
+}
+
+// applyBatchOp applies a single batch operation and returns the stored group of creates and updates.
+func (uc *UseCase) applyBatchOp(ctx context.Context, op entity.BatchOp[entity.Group]) (entity.Group, error) {
+	switch op.Op {
+	case entity.BatchCreate:
+		group, err := uc.CreateGroup(ctx, op.Item)
+		if err != nil {
+			return entity.Group{}, fmt.Errorf("uc.CreateGroup: %w", err)
+		}
+
+		return group, nil
+	case entity.BatchUpdate:
+		group, err := uc.UpdateGroup(ctx, op.Item)
+		if err != nil {
+			return entity.Group{}, fmt.Errorf("uc.UpdateGroup: %w", err)
+		}
+
+		return group, nil
+	case entity.BatchDelete:
+		_, err := uc.DeleteGroup(ctx, op.Item.ID, op.Item.Version, entity.GroupDeletion{Mode: entity.DeleteRestrict})
+		if err != nil {
+			return entity.Group{}, fmt.Errorf("uc.DeleteGroup: %w", err)
+		}
+
+		return entity.Group{}, nil
+	default:
+		return entity.Group{}, entity.ErrUnknownOperation
+	}
+}
+
+// ExportGroups returns the export of the groups of the filter, parents before their subgroups.
+func (uc *UseCase) ExportGroups(ctx context.Context, filter entity.GroupFilter) (entity.Export[entity.GroupPath], error) {
+	return func(yield func(entity.GroupPath) error) error {
+		if err := uc.repo.ExportGroups(ctx, filter, yield); err != nil {
+			return fmt.Errorf("GroupUseCase - ExportGroups - uc.repo.ExportGroups: %w", err)
+		}
+
+		return nil
+	}, nil
+}
+
+// GetGroupStats computes the statistics of every active group and sums them up.
+func (uc *UseCase) GetGroupStats(ctx context.Context) (entity.GroupStatsSummary, error) {
+	stats, err := uc.repo.GroupStats(ctx, nil)
+	if err != nil {
+		return entity.GroupStatsSummary{}, fmt.Errorf("GroupUseCase - GetGroupStats - uc.repo.GroupStats: %w", err)
+	}
+
+	return entity.NewGroupStatsSummary(stats), nil
+}
+
+// GetGroupStatsByID computes the statistics of an active group.
+func (uc *UseCase) GetGroupStatsByID(ctx context.Context, id int) (entity.GroupStats, error) {
+	stats, err := uc.repo.GroupStats(ctx, &id)
+	if err != nil {
+		return entity.GroupStats{}, fmt.Errorf("GroupUseCase - GetGroupStatsByID - uc.repo.GroupStats: %w", err)
+	}
+
+	if len(stats) == 0 {
+		return entity.GroupStats{}, errNotFound
+	}
+
+	return stats[0], nil
+}
+
+// SearchGroups searches for groups by name,
+// matching the query in both Cyrillic and Latin spelling.
+func (uc *UseCase) SearchGroups(ctx context.Context, query entity.SearchQuery) ([]entity.GroupHit, error) {
+	query.Terms = translit.Variants(strings.TrimSpace(query.Text))
+
 	groups, err := uc.repo.SearchGroups(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("GroupUseCase - SearchGroups - uc.repo.SearchGroups: %w", err)
@@ -89,3 +405,42 @@ func (uc *UseCase) SearchGroups(ctx context.Context, query string) ([]entity.Gro
 
 	return groups, nil
 }
+
+// checkParent verifies that the parent of the group exists and that the group
+// is neither its own parent nor an ancestor of it. The tree stays locked until the transaction ends,
+// so that concurrent moves cannot pass the check against each other and commit a cycle.
+func (uc *UseCase) checkParent(ctx context.Context, group entity.Group) error {
+	if group.ParentID == nil {
+		return nil
+	}
+
+	if *group.ParentID == group.ID {
+		return entity.ErrGroupCycle
+	}
+
+	if err := uc.repo.LockTree(ctx); err != nil {
+		return fmt.Errorf("uc.repo.LockTree: %w", err)
+	}
+
+	ancestors, err := uc.repo.GetAncestors(ctx, *group.ParentID)
+	if err != nil {
+		return fmt.Errorf("uc.repo.GetAncestors: %w", err)
+	}
+
+	if len(ancestors) == 0 {
+		return entity.ErrParentNotFound
+	}
+
+	// A new group has no subgroups yet, so it cannot be among the ancestors
+	if group.ID == 0 {
+		return nil
+	}
+
+	for _, a := range ancestors {
+		if a.ID == group.ID {
+			return entity.ErrGroupCycle
+		}
+	}
+
+	return nil
+}
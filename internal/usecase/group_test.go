@@ -0,0 +1,511 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/usecase/group"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func groupUseCase(t *testing.T) (*group.UseCase, *MockGroupRepo) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	repo := NewMockGroupRepo(mockCtl)
+
+	useCase := group.New(repo, transactor(mockCtl))
+
+	return useCase, repo
+}
+
+// transactor returns a transactor that runs the functions it is given in place.
+func transactor(mockCtl *gomock.Controller) *MockTransactor {
+	tx := NewMockTransactor(mockCtl)
+	tx.EXPECT().WithinTx(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		}).
+		AnyTimes()
+
+	return tx
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestUpdateGroup(t *testing.T) { //nolint:tparallel // data races here
+	t.Parallel()
+
+	groups, repo := groupUseCase(t)
+
+	tests := []struct {
+		name  string
+		group entity.Group
+		mock  func()
+		res   entity.Group
+		err   error
+	}{
+		{
+			name:  "root group",
+			group: entity.Group{ID: 5, Name: "root"},
+			mock: func() {
+				repo.EXPECT().UpdateGroup(context.Background(), entity.Group{ID: 5, Name: "root"}).
+					Return(entity.Group{ID: 5, Name: "root", Version: 2}, nil)
+			},
+			res: entity.Group{ID: 5, Name: "root", Version: 2},
+			err: nil,
+		},
+		{
+			name:  "own parent",
+			group: entity.Group{ID: 5, ParentID: intPtr(5)},
+			mock:  func() {},
+			err:   entity.ErrGroupCycle,
+		},
+		{
+			name:  "parent is a descendant",
+			group: entity.Group{ID: 5, ParentID: intPtr(7)},
+			mock: func() {
+				repo.EXPECT().LockTree(context.Background()).Return(nil)
+				repo.EXPECT().GetAncestors(context.Background(), 7).Return([]entity.Group{
+					{ID: 7, ParentID: intPtr(6)},
+					{ID: 6, ParentID: intPtr(5)},
+					{ID: 5, ParentID: intPtr(1)},
+					{ID: 1},
+				}, nil)
+			},
+			err: entity.ErrGroupCycle,
+		},
+		{
+			name:  "parent does not exist",
+			group: entity.Group{ID: 5, ParentID: intPtr(42)},
+			mock: func() {
+				repo.EXPECT().LockTree(context.Background()).Return(nil)
+				repo.EXPECT().GetAncestors(context.Background(), 42).Return(nil, nil)
+			},
+			err: entity.ErrParentNotFound,
+		},
+		{
+			name:  "valid parent",
+			group: entity.Group{ID: 5, ParentID: intPtr(2)},
+			mock: func() {
+				repo.EXPECT().LockTree(context.Background()).Return(nil)
+				repo.EXPECT().GetAncestors(context.Background(), 2).Return([]entity.Group{
+					{ID: 2, ParentID: intPtr(1)},
+					{ID: 1},
+				}, nil)
+				repo.EXPECT().UpdateGroup(context.Background(), entity.Group{ID: 5, ParentID: intPtr(2)}).
+					Return(entity.Group{ID: 5, ParentID: intPtr(2), Version: 2}, nil)
+			},
+			res: entity.Group{ID: 5, ParentID: intPtr(2), Version: 2},
+			err: nil,
+		},
+		{
+			name:  "tree not locked",
+			group: entity.Group{ID: 5, ParentID: intPtr(2)},
+			mock: func() {
+				repo.EXPECT().LockTree(context.Background()).Return(errInternalServErr)
+			},
+			err: errInternalServErr,
+		},
+		{
+			name:  "repo error",
+			group: entity.Group{ID: 5, ParentID: intPtr(2)},
+			mock: func() {
+				repo.EXPECT().LockTree(context.Background()).Return(nil)
+				repo.EXPECT().GetAncestors(context.Background(), 2).Return(nil, errInternalServErr)
+			},
+			err: errInternalServErr,
+		},
+	}
+
+	for _, tc := range tests { //nolint:paralleltest // data races here
+		localTc := tc
+
+		t.Run(localTc.name, func(t *testing.T) {
+			localTc.mock()
+
+			res, err := groups.UpdateGroup(context.Background(), localTc.group)
+
+			require.Equal(t, localTc.res, res)
+			require.ErrorIs(t, err, localTc.err)
+		})
+	}
+}
+
+func TestGetGroupPath(t *testing.T) { //nolint:tparallel // data races here
+	t.Parallel()
+
+	groups, repo := groupUseCase(t)
+
+	tests := []struct {
+		name string
+		mock func()
+		res  []entity.GroupRef
+		err  error
+	}{
+		{
+			name: "nested group",
+			mock: func() {
+				repo.EXPECT().GetAncestors(context.Background(), 3).Return([]entity.Group{
+					{ID: 3, Name: "Group", ParentID: intPtr(2)},
+					{ID: 2, Name: "Faculty", ParentID: intPtr(1)},
+					{ID: 1, Name: "University"},
+				}, nil)
+			},
+			res: []entity.GroupRef{{ID: 1, Name: "University"}, {ID: 2, Name: "Faculty"}, {ID: 3, Name: "Group"}},
+			err: nil,
+		},
+		{
+			name: "missing group",
+			mock: func() {
+				repo.EXPECT().GetAncestors(context.Background(), 3).Return(nil, nil)
+			},
+			res: nil,
+			err: entity.ErrNotFound,
+		},
+	}
+
+	for _, tc := range tests { //nolint:paralleltest // data races here
+		localTc := tc
+
+		t.Run(localTc.name, func(t *testing.T) {
+			localTc.mock()
+
+			res, err := groups.GetGroupPath(context.Background(), 3)
+
+			require.Equal(t, localTc.res, res)
+			require.ErrorIs(t, err, localTc.err)
+		})
+	}
+}
+
+func TestGetGroupStats(t *testing.T) { //nolint:tparallel // data races here
+	t.Parallel()
+
+	groups, repo := groupUseCase(t)
+
+	items := []entity.GroupStats{
+		{ID: 1, Name: "University", DirectStudents: 2, TotalStudents: 12, DirectSubgroups: 2, TotalSubgroups: 3},
+		{ID: 2, ParentID: intPtr(1), Name: "Physics", Depth: 1, TotalStudents: 10, DirectSubgroups: 1, TotalSubgroups: 1},
+		{ID: 4, ParentID: intPtr(2), Name: "P-101", Depth: 2, DirectStudents: 10, TotalStudents: 10, Leaf: true},
+		{ID: 3, ParentID: intPtr(1), Name: "Chemistry", Depth: 1, Leaf: true, Empty: true},
+		{ID: 5, Name: "Archive", Leaf: true, Empty: true},
+	}
+
+	tests := []struct {
+		name string
+		mock func()
+		res  entity.GroupStatsSummary
+		err  error
+	}{
+		{
+			name: "summary",
+			mock: func() {
+				repo.EXPECT().GroupStats(context.Background(), nil).Return(items, nil)
+			},
+			res: entity.GroupStatsSummary{Groups: 5, Students: 12, MaxDepth: 2, LeafGroups: 3, EmptyGroups: 2, Items: items},
+			err: nil,
+		},
+		{
+			name: "repo error",
+			mock: func() {
+				repo.EXPECT().GroupStats(context.Background(), nil).Return(nil, errInternalServErr)
+			},
+			res: entity.GroupStatsSummary{},
+			err: errInternalServErr,
+		},
+	}
+
+	for _, tc := range tests { //nolint:paralleltest // data races here
+		localTc := tc
+
+		t.Run(localTc.name, func(t *testing.T) {
+			localTc.mock()
+
+			res, err := groups.GetGroupStats(context.Background())
+
+			require.Equal(t, localTc.res, res)
+			require.ErrorIs(t, err, localTc.err)
+		})
+	}
+}
+
+func TestMergeGroup(t *testing.T) { //nolint:tparallel // data races here
+	t.Parallel()
+
+	groups, repo := groupUseCase(t)
+
+	tests := []struct {
+		name   string
+		id     int
+		target int
+		mock   func()
+		res    entity.GroupChange
+		err    error
+	}{
+		{
+			name:   "into sibling",
+			id:     3,
+			target: 4,
+			mock: func() {
+				repo.EXPECT().LockTree(context.Background()).Return(nil)
+				repo.EXPECT().GetAncestors(context.Background(), 4).Return([]entity.Group{{ID: 4, ParentID: intPtr(1)}, {ID: 1}}, nil)
+				repo.EXPECT().MoveStudents(context.Background(), 3, 4).Return([]int{7, 8}, nil)
+				repo.EXPECT().MoveSubgroups(context.Background(), 3, 4).Return(2, nil)
+				repo.EXPECT().DeleteGroup(context.Background(), 3, 7).Return(nil)
+				repo.EXPECT().GetGroupByID(context.Background(), 4).Return(entity.Group{ID: 4, Name: "CS"}, nil)
+			},
+			res: entity.GroupChange{Group: entity.Group{ID: 4, Name: "CS"}, Students: 2, Subgroups: 2, StudentIDs: []int{7, 8}},
+			err: nil,
+		},
+		{
+			name:   "into itself",
+			id:     3,
+			target: 3,
+			mock:   func() {},
+			res:    entity.GroupChange{},
+			err:    entity.ErrMergeCycle,
+		},
+		{
+			name:   "into subgroup",
+			id:     1,
+			target: 4,
+			mock: func() {
+				repo.EXPECT().LockTree(context.Background()).Return(nil)
+				repo.EXPECT().GetAncestors(context.Background(), 4).Return([]entity.Group{{ID: 4, ParentID: intPtr(1)}, {ID: 1}}, nil)
+			},
+			res: entity.GroupChange{},
+			err: entity.ErrMergeCycle,
+		},
+		{
+			name:   "tree not locked",
+			id:     3,
+			target: 4,
+			mock: func() {
+				repo.EXPECT().LockTree(context.Background()).Return(errInternalServErr)
+			},
+			res: entity.GroupChange{},
+			err: errInternalServErr,
+		},
+		{
+			name:   "missing target",
+			id:     3,
+			target: 42,
+			mock: func() {
+				repo.EXPECT().LockTree(context.Background()).Return(nil)
+				repo.EXPECT().GetAncestors(context.Background(), 42).Return(nil, nil)
+			},
+			res: entity.GroupChange{},
+			err: entity.ErrTargetNotFound,
+		},
+		{
+			name:   "stale version",
+			id:     3,
+			target: 4,
+			mock: func() {
+				repo.EXPECT().LockTree(context.Background()).Return(nil)
+				repo.EXPECT().GetAncestors(context.Background(), 4).Return([]entity.Group{{ID: 4}}, nil)
+				repo.EXPECT().MoveStudents(context.Background(), 3, 4).Return([]int{7, 8}, nil)
+				repo.EXPECT().MoveSubgroups(context.Background(), 3, 4).Return(0, nil)
+				repo.EXPECT().DeleteGroup(context.Background(), 3, 7).Return(entity.ErrVersionMismatch)
+			},
+			res: entity.GroupChange{},
+			err: entity.ErrVersionMismatch,
+		},
+	}
+
+	for _, tc := range tests { //nolint:paralleltest // data races here
+		localTc := tc
+
+		t.Run(localTc.name, func(t *testing.T) {
+			localTc.mock()
+
+			res, err := groups.MergeGroup(context.Background(), localTc.id, localTc.target, 7)
+
+			require.Equal(t, localTc.res, res)
+			require.ErrorIs(t, err, localTc.err)
+		})
+	}
+}
+
+func TestDeleteGroup(t *testing.T) { //nolint:tparallel // data races here
+	t.Parallel()
+
+	groups, repo := groupUseCase(t)
+
+	tests := []struct {
+		name     string
+		deletion entity.GroupDeletion
+		mock     func()
+		res      entity.GroupChange
+		err      error
+	}{
+		{
+			name:     "restrict empty",
+			deletion: entity.GroupDeletion{Mode: entity.DeleteRestrict},
+			mock: func() {
+				repo.EXPECT().HasSubgroups(context.Background(), 3).Return(false, nil)
+				repo.EXPECT().HasStudents(context.Background(), 3).Return(false, nil)
+				repo.EXPECT().DeleteGroup(context.Background(), 3, 7).Return(nil)
+			},
+			res: entity.GroupChange{},
+			err: nil,
+		},
+		{
+			name:     "restrict with subgroups",
+			deletion: entity.GroupDeletion{Mode: entity.DeleteRestrict},
+			mock: func() {
+				repo.EXPECT().HasSubgroups(context.Background(), 3).Return(true, nil)
+			},
+			res: entity.GroupChange{},
+			err: entity.ErrGroupHasSubgroups,
+		},
+		{
+			name:     "restrict with students",
+			deletion: entity.GroupDeletion{Mode: entity.DeleteRestrict},
+			mock: func() {
+				repo.EXPECT().HasSubgroups(context.Background(), 3).Return(false, nil)
+				repo.EXPECT().HasStudents(context.Background(), 3).Return(true, nil)
+			},
+			res: entity.GroupChange{},
+			err: entity.ErrGroupHasStudents,
+		},
+		{
+			name:     "cascade",
+			deletion: entity.GroupDeletion{Mode: entity.DeleteCascade},
+			mock: func() {
+				repo.EXPECT().DeleteDescendants(context.Background(), 3).Return(2, []int{7, 8}, nil)
+				repo.EXPECT().DeleteGroup(context.Background(), 3, 7).Return(nil)
+			},
+			res: entity.GroupChange{Students: 2, Subgroups: 2, StudentIDs: []int{7, 8}},
+			err: nil,
+		},
+		{
+			name:     "cascade stale version",
+			deletion: entity.GroupDeletion{Mode: entity.DeleteCascade},
+			mock: func() {
+				repo.EXPECT().DeleteDescendants(context.Background(), 3).Return(2, []int{7, 8}, nil)
+				repo.EXPECT().DeleteGroup(context.Background(), 3, 7).Return(entity.ErrVersionMismatch)
+			},
+			res: entity.GroupChange{},
+			err: entity.ErrVersionMismatch,
+		},
+		{
+			name:     "reassign",
+			deletion: entity.GroupDeletion{Mode: entity.DeleteReassign, Target: 4},
+			mock: func() {
+				repo.EXPECT().LockTree(context.Background()).Return(nil)
+				repo.EXPECT().GetAncestors(context.Background(), 4).Return([]entity.Group{{ID: 4}}, nil)
+				repo.EXPECT().MoveStudents(context.Background(), 3, 4).Return([]int{7, 8}, nil)
+				repo.EXPECT().MoveSubgroups(context.Background(), 3, 4).Return(2, nil)
+				repo.EXPECT().DeleteGroup(context.Background(), 3, 7).Return(nil)
+				repo.EXPECT().GetGroupByID(context.Background(), 4).Return(entity.Group{ID: 4, Name: "CS"}, nil)
+			},
+			res: entity.GroupChange{Group: entity.Group{ID: 4, Name: "CS"}, Students: 2, Subgroups: 2, StudentIDs: []int{7, 8}},
+			err: nil,
+		},
+	}
+
+	for _, tc := range tests { //nolint:paralleltest // data races here
+		localTc := tc
+
+		t.Run(localTc.name, func(t *testing.T) {
+			localTc.mock()
+
+			res, err := groups.DeleteGroup(context.Background(), 3, 7, localTc.deletion)
+
+			require.Equal(t, localTc.res, res)
+			require.ErrorIs(t, err, localTc.err)
+		})
+	}
+}
+
+func TestBatchGroups(t *testing.T) { //nolint:tparallel // data races here
+	t.Parallel()
+
+	groups, repo := groupUseCase(t)
+
+	ctx := context.Background()
+	ops := []entity.BatchOp[entity.Group]{
+		{Op: entity.BatchCreate, Item: entity.Group{Name: "Physics", ParentID: intPtr(1)}},
+		{Op: entity.BatchDelete, Item: entity.Group{ID: 2, Version: 3}},
+	}
+	physics := entity.Group{ID: 5, Name: "Physics", ParentID: intPtr(1), Version: 1}
+
+	tests := []struct {
+		name   string
+		atomic bool
+		mock   func()
+		res    entity.BatchReport[entity.Group]
+		err    error
+	}{
+		{
+			name:   "all applied",
+			atomic: true,
+			mock: func() {
+				repo.EXPECT().LockTree(ctx).Return(nil)
+				repo.EXPECT().GetAncestors(ctx, 1).Return([]entity.Group{{ID: 1}}, nil)
+				repo.EXPECT().CreateGroup(ctx, ops[0].Item).Return(physics, nil)
+				repo.EXPECT().HasSubgroups(ctx, 2).Return(false, nil)
+				repo.EXPECT().HasStudents(ctx, 2).Return(false, nil)
+				repo.EXPECT().DeleteGroup(ctx, 2, 3).Return(nil)
+			},
+			res: entity.BatchReport[entity.Group]{Atomic: true, Total: 2, Applied: 2, Results: []entity.BatchResult[entity.Group]{
+				{Op: entity.BatchCreate, Status: entity.BatchApplied, Item: &physics},
+				{Op: entity.BatchDelete, Status: entity.BatchApplied},
+			}},
+			err: nil,
+		},
+		{
+			name:   "delete restricted like a single delete",
+			atomic: true,
+			mock: func() {
+				repo.EXPECT().LockTree(ctx).Return(nil)
+				repo.EXPECT().GetAncestors(ctx, 1).Return([]entity.Group{{ID: 1}}, nil)
+				repo.EXPECT().CreateGroup(ctx, ops[0].Item).Return(physics, nil)
+				repo.EXPECT().HasSubgroups(ctx, 2).Return(false, nil)
+				repo.EXPECT().HasStudents(ctx, 2).Return(true, nil)
+			},
+			res: entity.BatchReport[entity.Group]{Atomic: true, Total: 2, Failed: 1, Results: []entity.BatchResult[entity.Group]{
+				{Op: entity.BatchCreate, Status: entity.BatchRolledBack},
+				{Op: entity.BatchDelete, Status: entity.BatchFailed, Err: entity.ErrGroupHasStudents},
+			}},
+			err: nil,
+		},
+		{
+			name:   "missing parent",
+			atomic: false,
+			mock: func() {
+				repo.EXPECT().LockTree(ctx).Return(nil)
+				repo.EXPECT().GetAncestors(ctx, 1).Return(nil, nil)
+				repo.EXPECT().HasSubgroups(ctx, 2).Return(false, nil)
+				repo.EXPECT().HasStudents(ctx, 2).Return(false, nil)
+				repo.EXPECT().DeleteGroup(ctx, 2, 3).Return(nil)
+			},
+			res: entity.BatchReport[entity.Group]{Total: 2, Applied: 1, Failed: 1, Results: []entity.BatchResult[entity.Group]{
+				{Op: entity.BatchCreate, Status: entity.BatchFailed, Err: entity.ErrParentNotFound},
+				{Op: entity.BatchDelete, Status: entity.BatchApplied},
+			}},
+			err: nil,
+		},
+	}
+
+	for _, tc := range tests { //nolint:paralleltest // data races here
+		localTc := tc
+
+		t.Run(localTc.name, func(t *testing.T) {
+			localTc.mock()
+
+			res, err := groups.BatchGroups(ctx, ops, localTc.atomic)
+
+			require.Equal(t, localTc.res, res)
+			require.ErrorIs(t, err, localTc.err)
+		})
+	}
+}
@@ -1,9 +1,9 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: ./internal/repo/contracts.go
+// Source: contracts.go
 //
 // Generated by this command:
 //
-//	mockgen -source ./internal/repo/contracts.go -package usecase_test
+//	mockgen -source=contracts.go -destination=../usecase/mocks_repo_test.go -package=usecase_test
 //
 
 // Package usecase_test is a generated GoMock package.
@@ -108,3 +108,677 @@ func (mr *MockTranslationWebAPIMockRecorder) Translate(arg0 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Translate", reflect.TypeOf((*MockTranslationWebAPI)(nil).Translate), arg0)
 }
+
+// MockTransactor is a mock of Transactor interface.
+type MockTransactor struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactorMockRecorder
+	isgomock struct{}
+}
+
+// MockTransactorMockRecorder is the mock recorder for MockTransactor.
+type MockTransactorMockRecorder struct {
+	mock *MockTransactor
+}
+
+// NewMockTransactor creates a new mock instance.
+func NewMockTransactor(ctrl *gomock.Controller) *MockTransactor {
+	mock := &MockTransactor{ctrl: ctrl}
+	mock.recorder = &MockTransactorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransactor) EXPECT() *MockTransactorMockRecorder {
+	return m.recorder
+}
+
+// WithinTx mocks base method.
+func (m *MockTransactor) WithinTx(ctx context.Context, fn func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithinTx", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithinTx indicates an expected call of WithinTx.
+func (mr *MockTransactorMockRecorder) WithinTx(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithinTx", reflect.TypeOf((*MockTransactor)(nil).WithinTx), ctx, fn)
+}
+
+// MockStudentRepo is a mock of StudentRepo interface.
+type MockStudentRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockStudentRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockStudentRepoMockRecorder is the mock recorder for MockStudentRepo.
+type MockStudentRepoMockRecorder struct {
+	mock *MockStudentRepo
+}
+
+// NewMockStudentRepo creates a new mock instance.
+func NewMockStudentRepo(ctrl *gomock.Controller) *MockStudentRepo {
+	mock := &MockStudentRepo{ctrl: ctrl}
+	mock.recorder = &MockStudentRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStudentRepo) EXPECT() *MockStudentRepoMockRecorder {
+	return m.recorder
+}
+
+// CreateStudent mocks base method.
+func (m *MockStudentRepo) CreateStudent(ctx context.Context, student entity.Student) (entity.Student, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateStudent", ctx, student)
+	ret0, _ := ret[0].(entity.Student)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateStudent indicates an expected call of CreateStudent.
+func (mr *MockStudentRepoMockRecorder) CreateStudent(ctx, student any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateStudent", reflect.TypeOf((*MockStudentRepo)(nil).CreateStudent), ctx, student)
+}
+
+// DeleteStudent mocks base method.
+func (m *MockStudentRepo) DeleteStudent(ctx context.Context, id, version int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteStudent", ctx, id, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteStudent indicates an expected call of DeleteStudent.
+func (mr *MockStudentRepoMockRecorder) DeleteStudent(ctx, id, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteStudent", reflect.TypeOf((*MockStudentRepo)(nil).DeleteStudent), ctx, id, version)
+}
+
+// ExportStudents mocks base method.
+func (m *MockStudentRepo) ExportStudents(ctx context.Context, filter entity.StudentFilter, yield func(entity.Student) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportStudents", ctx, filter, yield)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportStudents indicates an expected call of ExportStudents.
+func (mr *MockStudentRepoMockRecorder) ExportStudents(ctx, filter, yield any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportStudents", reflect.TypeOf((*MockStudentRepo)(nil).ExportStudents), ctx, filter, yield)
+}
+
+// GetEnrollments mocks base method.
+func (m *MockStudentRepo) GetEnrollments(ctx context.Context, studentID int) ([]entity.Enrollment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEnrollments", ctx, studentID)
+	ret0, _ := ret[0].([]entity.Enrollment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEnrollments indicates an expected call of GetEnrollments.
+func (mr *MockStudentRepoMockRecorder) GetEnrollments(ctx, studentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEnrollments", reflect.TypeOf((*MockStudentRepo)(nil).GetEnrollments), ctx, studentID)
+}
+
+// GetStudentByID mocks base method.
+func (m *MockStudentRepo) GetStudentByID(ctx context.Context, id int) (entity.Student, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStudentByID", ctx, id)
+	ret0, _ := ret[0].(entity.Student)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStudentByID indicates an expected call of GetStudentByID.
+func (mr *MockStudentRepoMockRecorder) GetStudentByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStudentByID", reflect.TypeOf((*MockStudentRepo)(nil).GetStudentByID), ctx, id)
+}
+
+// GetStudents mocks base method.
+func (m *MockStudentRepo) GetStudents(ctx context.Context, filter entity.StudentFilter, page entity.PageRequest) (entity.Page[entity.Student], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStudents", ctx, filter, page)
+	ret0, _ := ret[0].(entity.Page[entity.Student])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStudents indicates an expected call of GetStudents.
+func (mr *MockStudentRepoMockRecorder) GetStudents(ctx, filter, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStudents", reflect.TypeOf((*MockStudentRepo)(nil).GetStudents), ctx, filter, page)
+}
+
+// PatchStudent mocks base method.
+func (m *MockStudentRepo) PatchStudent(ctx context.Context, id int, patch entity.StudentPatch) (entity.Student, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchStudent", ctx, id, patch)
+	ret0, _ := ret[0].(entity.Student)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchStudent indicates an expected call of PatchStudent.
+func (mr *MockStudentRepoMockRecorder) PatchStudent(ctx, id, patch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchStudent", reflect.TypeOf((*MockStudentRepo)(nil).PatchStudent), ctx, id, patch)
+}
+
+// RestoreStudent mocks base method.
+func (m *MockStudentRepo) RestoreStudent(ctx context.Context, id int) (entity.Student, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreStudent", ctx, id)
+	ret0, _ := ret[0].(entity.Student)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreStudent indicates an expected call of RestoreStudent.
+func (mr *MockStudentRepoMockRecorder) RestoreStudent(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreStudent", reflect.TypeOf((*MockStudentRepo)(nil).RestoreStudent), ctx, id)
+}
+
+// SearchStudents mocks base method.
+func (m *MockStudentRepo) SearchStudents(ctx context.Context, query entity.SearchQuery) ([]entity.StudentHit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchStudents", ctx, query)
+	ret0, _ := ret[0].([]entity.StudentHit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchStudents indicates an expected call of SearchStudents.
+func (mr *MockStudentRepoMockRecorder) SearchStudents(ctx, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchStudents", reflect.TypeOf((*MockStudentRepo)(nil).SearchStudents), ctx, query)
+}
+
+// UpdateStudent mocks base method.
+func (m *MockStudentRepo) UpdateStudent(ctx context.Context, student entity.Student) (entity.Student, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStudent", ctx, student)
+	ret0, _ := ret[0].(entity.Student)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateStudent indicates an expected call of UpdateStudent.
+func (mr *MockStudentRepoMockRecorder) UpdateStudent(ctx, student any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStudent", reflect.TypeOf((*MockStudentRepo)(nil).UpdateStudent), ctx, student)
+}
+
+// MockAuditRepo is a mock of AuditRepo interface.
+type MockAuditRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockAuditRepoMockRecorder is the mock recorder for MockAuditRepo.
+type MockAuditRepoMockRecorder struct {
+	mock *MockAuditRepo
+}
+
+// NewMockAuditRepo creates a new mock instance.
+func NewMockAuditRepo(ctrl *gomock.Controller) *MockAuditRepo {
+	mock := &MockAuditRepo{ctrl: ctrl}
+	mock.recorder = &MockAuditRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditRepo) EXPECT() *MockAuditRepoMockRecorder {
+	return m.recorder
+}
+
+// GetAudit mocks base method.
+func (m *MockAuditRepo) GetAudit(ctx context.Context, filter entity.AuditFilter, page entity.PageRequest) (entity.Page[entity.AuditEntry], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAudit", ctx, filter, page)
+	ret0, _ := ret[0].(entity.Page[entity.AuditEntry])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAudit indicates an expected call of GetAudit.
+func (mr *MockAuditRepoMockRecorder) GetAudit(ctx, filter, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAudit", reflect.TypeOf((*MockAuditRepo)(nil).GetAudit), ctx, filter, page)
+}
+
+// StoreAudit mocks base method.
+func (m *MockAuditRepo) StoreAudit(ctx context.Context, entries ...entity.AuditEntry) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range entries {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "StoreAudit", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StoreAudit indicates an expected call of StoreAudit.
+func (mr *MockAuditRepoMockRecorder) StoreAudit(ctx any, entries ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, entries...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreAudit", reflect.TypeOf((*MockAuditRepo)(nil).StoreAudit), varargs...)
+}
+
+// MockGrantRepo is a mock of GrantRepo interface.
+type MockGrantRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockGrantRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockGrantRepoMockRecorder is the mock recorder for MockGrantRepo.
+type MockGrantRepoMockRecorder struct {
+	mock *MockGrantRepo
+}
+
+// NewMockGrantRepo creates a new mock instance.
+func NewMockGrantRepo(ctrl *gomock.Controller) *MockGrantRepo {
+	mock := &MockGrantRepo{ctrl: ctrl}
+	mock.recorder = &MockGrantRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGrantRepo) EXPECT() *MockGrantRepoMockRecorder {
+	return m.recorder
+}
+
+// CreateGrant mocks base method.
+func (m *MockGrantRepo) CreateGrant(ctx context.Context, grant entity.Grant) (entity.Grant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateGrant", ctx, grant)
+	ret0, _ := ret[0].(entity.Grant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateGrant indicates an expected call of CreateGrant.
+func (mr *MockGrantRepoMockRecorder) CreateGrant(ctx, grant any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGrant", reflect.TypeOf((*MockGrantRepo)(nil).CreateGrant), ctx, grant)
+}
+
+// DeleteGrant mocks base method.
+func (m *MockGrantRepo) DeleteGrant(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteGrant", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteGrant indicates an expected call of DeleteGrant.
+func (mr *MockGrantRepoMockRecorder) DeleteGrant(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGrant", reflect.TypeOf((*MockGrantRepo)(nil).DeleteGrant), ctx, id)
+}
+
+// GetGrantByID mocks base method.
+func (m *MockGrantRepo) GetGrantByID(ctx context.Context, id int) (entity.Grant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGrantByID", ctx, id)
+	ret0, _ := ret[0].(entity.Grant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGrantByID indicates an expected call of GetGrantByID.
+func (mr *MockGrantRepoMockRecorder) GetGrantByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGrantByID", reflect.TypeOf((*MockGrantRepo)(nil).GetGrantByID), ctx, id)
+}
+
+// GetGrants mocks base method.
+func (m *MockGrantRepo) GetGrants(ctx context.Context, subject string) ([]entity.Grant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGrants", ctx, subject)
+	ret0, _ := ret[0].([]entity.Grant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGrants indicates an expected call of GetGrants.
+func (mr *MockGrantRepoMockRecorder) GetGrants(ctx, subject any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGrants", reflect.TypeOf((*MockGrantRepo)(nil).GetGrants), ctx, subject)
+}
+
+// MockGroupRepo is a mock of GroupRepo interface.
+type MockGroupRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockGroupRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockGroupRepoMockRecorder is the mock recorder for MockGroupRepo.
+type MockGroupRepoMockRecorder struct {
+	mock *MockGroupRepo
+}
+
+// NewMockGroupRepo creates a new mock instance.
+func NewMockGroupRepo(ctrl *gomock.Controller) *MockGroupRepo {
+	mock := &MockGroupRepo{ctrl: ctrl}
+	mock.recorder = &MockGroupRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGroupRepo) EXPECT() *MockGroupRepoMockRecorder {
+	return m.recorder
+}
+
+// CreateGroup mocks base method.
+func (m *MockGroupRepo) CreateGroup(ctx context.Context, group entity.Group) (entity.Group, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateGroup", ctx, group)
+	ret0, _ := ret[0].(entity.Group)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateGroup indicates an expected call of CreateGroup.
+func (mr *MockGroupRepoMockRecorder) CreateGroup(ctx, group any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGroup", reflect.TypeOf((*MockGroupRepo)(nil).CreateGroup), ctx, group)
+}
+
+// DeleteDescendants mocks base method.
+func (m *MockGroupRepo) DeleteDescendants(ctx context.Context, id int) (int, []int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDescendants", ctx, id)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].([]int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// DeleteDescendants indicates an expected call of DeleteDescendants.
+func (mr *MockGroupRepoMockRecorder) DeleteDescendants(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDescendants", reflect.TypeOf((*MockGroupRepo)(nil).DeleteDescendants), ctx, id)
+}
+
+// DeleteGroup mocks base method.
+func (m *MockGroupRepo) DeleteGroup(ctx context.Context, id, version int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteGroup", ctx, id, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteGroup indicates an expected call of DeleteGroup.
+func (mr *MockGroupRepoMockRecorder) DeleteGroup(ctx, id, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGroup", reflect.TypeOf((*MockGroupRepo)(nil).DeleteGroup), ctx, id, version)
+}
+
+// ExportGroups mocks base method.
+func (m *MockGroupRepo) ExportGroups(ctx context.Context, filter entity.GroupFilter, yield func(entity.GroupPath) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportGroups", ctx, filter, yield)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportGroups indicates an expected call of ExportGroups.
+func (mr *MockGroupRepoMockRecorder) ExportGroups(ctx, filter, yield any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportGroups", reflect.TypeOf((*MockGroupRepo)(nil).ExportGroups), ctx, filter, yield)
+}
+
+// FindGroups mocks base method.
+func (m *MockGroupRepo) FindGroups(ctx context.Context, ids []int, names []string) ([]entity.Group, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindGroups", ctx, ids, names)
+	ret0, _ := ret[0].([]entity.Group)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindGroups indicates an expected call of FindGroups.
+func (mr *MockGroupRepoMockRecorder) FindGroups(ctx, ids, names any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindGroups", reflect.TypeOf((*MockGroupRepo)(nil).FindGroups), ctx, ids, names)
+}
+
+// GetAncestors mocks base method.
+func (m *MockGroupRepo) GetAncestors(ctx context.Context, id int) ([]entity.Group, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAncestors", ctx, id)
+	ret0, _ := ret[0].([]entity.Group)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAncestors indicates an expected call of GetAncestors.
+func (mr *MockGroupRepoMockRecorder) GetAncestors(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAncestors", reflect.TypeOf((*MockGroupRepo)(nil).GetAncestors), ctx, id)
+}
+
+// GetGroupByID mocks base method.
+func (m *MockGroupRepo) GetGroupByID(ctx context.Context, id int) (entity.Group, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGroupByID", ctx, id)
+	ret0, _ := ret[0].(entity.Group)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGroupByID indicates an expected call of GetGroupByID.
+func (mr *MockGroupRepoMockRecorder) GetGroupByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupByID", reflect.TypeOf((*MockGroupRepo)(nil).GetGroupByID), ctx, id)
+}
+
+// GetGroupWithSubgroups mocks base method.
+func (m *MockGroupRepo) GetGroupWithSubgroups(ctx context.Context, id, maxDepth int) (entity.Group, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGroupWithSubgroups", ctx, id, maxDepth)
+	ret0, _ := ret[0].(entity.Group)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGroupWithSubgroups indicates an expected call of GetGroupWithSubgroups.
+func (mr *MockGroupRepoMockRecorder) GetGroupWithSubgroups(ctx, id, maxDepth any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupWithSubgroups", reflect.TypeOf((*MockGroupRepo)(nil).GetGroupWithSubgroups), ctx, id, maxDepth)
+}
+
+// GetGroups mocks base method.
+func (m *MockGroupRepo) GetGroups(ctx context.Context, filter entity.GroupFilter, page entity.PageRequest) (entity.Page[entity.Group], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGroups", ctx, filter, page)
+	ret0, _ := ret[0].(entity.Page[entity.Group])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGroups indicates an expected call of GetGroups.
+func (mr *MockGroupRepoMockRecorder) GetGroups(ctx, filter, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroups", reflect.TypeOf((*MockGroupRepo)(nil).GetGroups), ctx, filter, page)
+}
+
+// GroupStats mocks base method.
+func (m *MockGroupRepo) GroupStats(ctx context.Context, id *int) ([]entity.GroupStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GroupStats", ctx, id)
+	ret0, _ := ret[0].([]entity.GroupStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GroupStats indicates an expected call of GroupStats.
+func (mr *MockGroupRepoMockRecorder) GroupStats(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GroupStats", reflect.TypeOf((*MockGroupRepo)(nil).GroupStats), ctx, id)
+}
+
+// HasStudents mocks base method.
+func (m *MockGroupRepo) HasStudents(ctx context.Context, id int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasStudents", ctx, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasStudents indicates an expected call of HasStudents.
+func (mr *MockGroupRepoMockRecorder) HasStudents(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasStudents", reflect.TypeOf((*MockGroupRepo)(nil).HasStudents), ctx, id)
+}
+
+// HasSubgroups mocks base method.
+func (m *MockGroupRepo) HasSubgroups(ctx context.Context, id int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasSubgroups", ctx, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasSubgroups indicates an expected call of HasSubgroups.
+func (mr *MockGroupRepoMockRecorder) HasSubgroups(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasSubgroups", reflect.TypeOf((*MockGroupRepo)(nil).HasSubgroups), ctx, id)
+}
+
+// LockTree mocks base method.
+func (m *MockGroupRepo) LockTree(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockTree", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockTree indicates an expected call of LockTree.
+func (mr *MockGroupRepoMockRecorder) LockTree(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockTree", reflect.TypeOf((*MockGroupRepo)(nil).LockTree), ctx)
+}
+
+// MoveStudents mocks base method.
+func (m *MockGroupRepo) MoveStudents(ctx context.Context, from, to int) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveStudents", ctx, from, to)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MoveStudents indicates an expected call of MoveStudents.
+func (mr *MockGroupRepoMockRecorder) MoveStudents(ctx, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveStudents", reflect.TypeOf((*MockGroupRepo)(nil).MoveStudents), ctx, from, to)
+}
+
+// MoveSubgroups mocks base method.
+func (m *MockGroupRepo) MoveSubgroups(ctx context.Context, from, to int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveSubgroups", ctx, from, to)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MoveSubgroups indicates an expected call of MoveSubgroups.
+func (mr *MockGroupRepoMockRecorder) MoveSubgroups(ctx, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveSubgroups", reflect.TypeOf((*MockGroupRepo)(nil).MoveSubgroups), ctx, from, to)
+}
+
+// PatchGroup mocks base method.
+func (m *MockGroupRepo) PatchGroup(ctx context.Context, id int, patch entity.GroupPatch) (entity.Group, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchGroup", ctx, id, patch)
+	ret0, _ := ret[0].(entity.Group)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchGroup indicates an expected call of PatchGroup.
+func (mr *MockGroupRepoMockRecorder) PatchGroup(ctx, id, patch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchGroup", reflect.TypeOf((*MockGroupRepo)(nil).PatchGroup), ctx, id, patch)
+}
+
+// RestoreGroup mocks base method.
+func (m *MockGroupRepo) RestoreGroup(ctx context.Context, id int) (entity.Group, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreGroup", ctx, id)
+	ret0, _ := ret[0].(entity.Group)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreGroup indicates an expected call of RestoreGroup.
+func (mr *MockGroupRepoMockRecorder) RestoreGroup(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreGroup", reflect.TypeOf((*MockGroupRepo)(nil).RestoreGroup), ctx, id)
+}
+
+// SearchGroups mocks base method.
+func (m *MockGroupRepo) SearchGroups(ctx context.Context, query entity.SearchQuery) ([]entity.GroupHit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchGroups", ctx, query)
+	ret0, _ := ret[0].([]entity.GroupHit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchGroups indicates an expected call of SearchGroups.
+func (mr *MockGroupRepoMockRecorder) SearchGroups(ctx, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchGroups", reflect.TypeOf((*MockGroupRepo)(nil).SearchGroups), ctx, query)
+}
+
+// SubtreeSize mocks base method.
+func (m *MockGroupRepo) SubtreeSize(ctx context.Context, id int) (int, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubtreeSize", ctx, id)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SubtreeSize indicates an expected call of SubtreeSize.
+func (mr *MockGroupRepoMockRecorder) SubtreeSize(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubtreeSize", reflect.TypeOf((*MockGroupRepo)(nil).SubtreeSize), ctx, id)
+}
+
+// UpdateGroup mocks base method.
+func (m *MockGroupRepo) UpdateGroup(ctx context.Context, group entity.Group) (entity.Group, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateGroup", ctx, group)
+	ret0, _ := ret[0].(entity.Group)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateGroup indicates an expected call of UpdateGroup.
+func (mr *MockGroupRepoMockRecorder) UpdateGroup(ctx, group any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGroup", reflect.TypeOf((*MockGroupRepo)(nil).UpdateGroup), ctx, group)
+}
@@ -1,9 +1,9 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: ./internal/usecase/contracts.go
+// Source: contracts.go
 //
 // Generated by this command:
 //
-//	mockgen -source ./internal/usecase/contracts.go -package usecase_test
+//	mockgen -source=contracts.go -destination=./mocks_usecase_test.go -package=usecase_test
 //
 
 // Package usecase_test is a generated GoMock package.
@@ -12,6 +12,7 @@ package usecase_test
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	entity "github.com/evrone/go-clean-template/internal/entity"
 	gomock "go.uber.org/mock/gomock"
@@ -70,3 +71,691 @@ func (mr *MockTranslationMockRecorder) Translate(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Translate", reflect.TypeOf((*MockTranslation)(nil).Translate), arg0, arg1)
 }
+
+// MockStudent is a mock of Student interface.
+type MockStudent struct {
+	ctrl     *gomock.Controller
+	recorder *MockStudentMockRecorder
+	isgomock struct{}
+}
+
+// MockStudentMockRecorder is the mock recorder for MockStudent.
+type MockStudentMockRecorder struct {
+	mock *MockStudent
+}
+
+// NewMockStudent creates a new mock instance.
+func NewMockStudent(ctrl *gomock.Controller) *MockStudent {
+	mock := &MockStudent{ctrl: ctrl}
+	mock.recorder = &MockStudentMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStudent) EXPECT() *MockStudentMockRecorder {
+	return m.recorder
+}
+
+// BatchStudents mocks base method.
+func (m *MockStudent) BatchStudents(ctx context.Context, ops []entity.BatchOp[entity.Student], atomic bool) (entity.BatchReport[entity.Student], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchStudents", ctx, ops, atomic)
+	ret0, _ := ret[0].(entity.BatchReport[entity.Student])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchStudents indicates an expected call of BatchStudents.
+func (mr *MockStudentMockRecorder) BatchStudents(ctx, ops, atomic any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchStudents", reflect.TypeOf((*MockStudent)(nil).BatchStudents), ctx, ops, atomic)
+}
+
+// ChangeStudentStatus mocks base method.
+func (m *MockStudent) ChangeStudentStatus(ctx context.Context, id int, change entity.StatusChange) (entity.Student, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeStudentStatus", ctx, id, change)
+	ret0, _ := ret[0].(entity.Student)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangeStudentStatus indicates an expected call of ChangeStudentStatus.
+func (mr *MockStudentMockRecorder) ChangeStudentStatus(ctx, id, change any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeStudentStatus", reflect.TypeOf((*MockStudent)(nil).ChangeStudentStatus), ctx, id, change)
+}
+
+// CreateStudent mocks base method.
+func (m *MockStudent) CreateStudent(ctx context.Context, student entity.Student) (entity.Student, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateStudent", ctx, student)
+	ret0, _ := ret[0].(entity.Student)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateStudent indicates an expected call of CreateStudent.
+func (mr *MockStudentMockRecorder) CreateStudent(ctx, student any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateStudent", reflect.TypeOf((*MockStudent)(nil).CreateStudent), ctx, student)
+}
+
+// DeleteStudent mocks base method.
+func (m *MockStudent) DeleteStudent(ctx context.Context, id, version int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteStudent", ctx, id, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteStudent indicates an expected call of DeleteStudent.
+func (mr *MockStudentMockRecorder) DeleteStudent(ctx, id, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteStudent", reflect.TypeOf((*MockStudent)(nil).DeleteStudent), ctx, id, version)
+}
+
+// ExportStudents mocks base method.
+func (m *MockStudent) ExportStudents(ctx context.Context, filter entity.StudentFilter) (entity.Export[entity.Student], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportStudents", ctx, filter)
+	ret0, _ := ret[0].(entity.Export[entity.Student])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportStudents indicates an expected call of ExportStudents.
+func (mr *MockStudentMockRecorder) ExportStudents(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportStudents", reflect.TypeOf((*MockStudent)(nil).ExportStudents), ctx, filter)
+}
+
+// GetGroupStudents mocks base method.
+func (m *MockStudent) GetGroupStudents(ctx context.Context, groupID int, recursive bool, asOf *time.Time, page entity.PageRequest) (entity.Page[entity.Student], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGroupStudents", ctx, groupID, recursive, asOf, page)
+	ret0, _ := ret[0].(entity.Page[entity.Student])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGroupStudents indicates an expected call of GetGroupStudents.
+func (mr *MockStudentMockRecorder) GetGroupStudents(ctx, groupID, recursive, asOf, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupStudents", reflect.TypeOf((*MockStudent)(nil).GetGroupStudents), ctx, groupID, recursive, asOf, page)
+}
+
+// GetStudentByID mocks base method.
+func (m *MockStudent) GetStudentByID(ctx context.Context, id int) (entity.Student, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStudentByID", ctx, id)
+	ret0, _ := ret[0].(entity.Student)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStudentByID indicates an expected call of GetStudentByID.
+func (mr *MockStudentMockRecorder) GetStudentByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStudentByID", reflect.TypeOf((*MockStudent)(nil).GetStudentByID), ctx, id)
+}
+
+// GetStudentEnrollments mocks base method.
+func (m *MockStudent) GetStudentEnrollments(ctx context.Context, id int) ([]entity.Enrollment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStudentEnrollments", ctx, id)
+	ret0, _ := ret[0].([]entity.Enrollment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStudentEnrollments indicates an expected call of GetStudentEnrollments.
+func (mr *MockStudentMockRecorder) GetStudentEnrollments(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStudentEnrollments", reflect.TypeOf((*MockStudent)(nil).GetStudentEnrollments), ctx, id)
+}
+
+// GetStudentWithPath mocks base method.
+func (m *MockStudent) GetStudentWithPath(ctx context.Context, id int) (entity.Student, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStudentWithPath", ctx, id)
+	ret0, _ := ret[0].(entity.Student)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStudentWithPath indicates an expected call of GetStudentWithPath.
+func (mr *MockStudentMockRecorder) GetStudentWithPath(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStudentWithPath", reflect.TypeOf((*MockStudent)(nil).GetStudentWithPath), ctx, id)
+}
+
+// GetStudents mocks base method.
+func (m *MockStudent) GetStudents(ctx context.Context, filter entity.StudentFilter, page entity.PageRequest) (entity.Page[entity.Student], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStudents", ctx, filter, page)
+	ret0, _ := ret[0].(entity.Page[entity.Student])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStudents indicates an expected call of GetStudents.
+func (mr *MockStudentMockRecorder) GetStudents(ctx, filter, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStudents", reflect.TypeOf((*MockStudent)(nil).GetStudents), ctx, filter, page)
+}
+
+// ImportStudents mocks base method.
+func (m *MockStudent) ImportStudents(ctx context.Context, rows []entity.StudentImport, dryRun bool) (entity.ImportReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportStudents", ctx, rows, dryRun)
+	ret0, _ := ret[0].(entity.ImportReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportStudents indicates an expected call of ImportStudents.
+func (mr *MockStudentMockRecorder) ImportStudents(ctx, rows, dryRun any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportStudents", reflect.TypeOf((*MockStudent)(nil).ImportStudents), ctx, rows, dryRun)
+}
+
+// PatchStudent mocks base method.
+func (m *MockStudent) PatchStudent(ctx context.Context, id int, patch entity.StudentPatch) (entity.Student, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchStudent", ctx, id, patch)
+	ret0, _ := ret[0].(entity.Student)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchStudent indicates an expected call of PatchStudent.
+func (mr *MockStudentMockRecorder) PatchStudent(ctx, id, patch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchStudent", reflect.TypeOf((*MockStudent)(nil).PatchStudent), ctx, id, patch)
+}
+
+// RestoreStudent mocks base method.
+func (m *MockStudent) RestoreStudent(ctx context.Context, id int) (entity.Student, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreStudent", ctx, id)
+	ret0, _ := ret[0].(entity.Student)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreStudent indicates an expected call of RestoreStudent.
+func (mr *MockStudentMockRecorder) RestoreStudent(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreStudent", reflect.TypeOf((*MockStudent)(nil).RestoreStudent), ctx, id)
+}
+
+// SearchStudents mocks base method.
+func (m *MockStudent) SearchStudents(ctx context.Context, query entity.SearchQuery) ([]entity.StudentHit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchStudents", ctx, query)
+	ret0, _ := ret[0].([]entity.StudentHit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchStudents indicates an expected call of SearchStudents.
+func (mr *MockStudentMockRecorder) SearchStudents(ctx, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchStudents", reflect.TypeOf((*MockStudent)(nil).SearchStudents), ctx, query)
+}
+
+// UpdateStudent mocks base method.
+func (m *MockStudent) UpdateStudent(ctx context.Context, student entity.Student) (entity.Student, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStudent", ctx, student)
+	ret0, _ := ret[0].(entity.Student)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateStudent indicates an expected call of UpdateStudent.
+func (mr *MockStudentMockRecorder) UpdateStudent(ctx, student any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStudent", reflect.TypeOf((*MockStudent)(nil).UpdateStudent), ctx, student)
+}
+
+// MockGroup is a mock of Group interface.
+type MockGroup struct {
+	ctrl     *gomock.Controller
+	recorder *MockGroupMockRecorder
+	isgomock struct{}
+}
+
+// MockGroupMockRecorder is the mock recorder for MockGroup.
+type MockGroupMockRecorder struct {
+	mock *MockGroup
+}
+
+// NewMockGroup creates a new mock instance.
+func NewMockGroup(ctrl *gomock.Controller) *MockGroup {
+	mock := &MockGroup{ctrl: ctrl}
+	mock.recorder = &MockGroupMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGroup) EXPECT() *MockGroupMockRecorder {
+	return m.recorder
+}
+
+// BatchGroups mocks base method.
+func (m *MockGroup) BatchGroups(ctx context.Context, ops []entity.BatchOp[entity.Group], atomic bool) (entity.BatchReport[entity.Group], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchGroups", ctx, ops, atomic)
+	ret0, _ := ret[0].(entity.BatchReport[entity.Group])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchGroups indicates an expected call of BatchGroups.
+func (mr *MockGroupMockRecorder) BatchGroups(ctx, ops, atomic any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchGroups", reflect.TypeOf((*MockGroup)(nil).BatchGroups), ctx, ops, atomic)
+}
+
+// CreateGroup mocks base method.
+func (m *MockGroup) CreateGroup(ctx context.Context, group entity.Group) (entity.Group, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateGroup", ctx, group)
+	ret0, _ := ret[0].(entity.Group)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateGroup indicates an expected call of CreateGroup.
+func (mr *MockGroupMockRecorder) CreateGroup(ctx, group any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGroup", reflect.TypeOf((*MockGroup)(nil).CreateGroup), ctx, group)
+}
+
+// DeleteGroup mocks base method.
+func (m *MockGroup) DeleteGroup(ctx context.Context, id, version int, deletion entity.GroupDeletion) (entity.GroupChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteGroup", ctx, id, version, deletion)
+	ret0, _ := ret[0].(entity.GroupChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteGroup indicates an expected call of DeleteGroup.
+func (mr *MockGroupMockRecorder) DeleteGroup(ctx, id, version, deletion any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGroup", reflect.TypeOf((*MockGroup)(nil).DeleteGroup), ctx, id, version, deletion)
+}
+
+// ExportGroups mocks base method.
+func (m *MockGroup) ExportGroups(ctx context.Context, filter entity.GroupFilter) (entity.Export[entity.GroupPath], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportGroups", ctx, filter)
+	ret0, _ := ret[0].(entity.Export[entity.GroupPath])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportGroups indicates an expected call of ExportGroups.
+func (mr *MockGroupMockRecorder) ExportGroups(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportGroups", reflect.TypeOf((*MockGroup)(nil).ExportGroups), ctx, filter)
+}
+
+// GetGroupByID mocks base method.
+func (m *MockGroup) GetGroupByID(ctx context.Context, id int) (entity.Group, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGroupByID", ctx, id)
+	ret0, _ := ret[0].(entity.Group)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGroupByID indicates an expected call of GetGroupByID.
+func (mr *MockGroupMockRecorder) GetGroupByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupByID", reflect.TypeOf((*MockGroup)(nil).GetGroupByID), ctx, id)
+}
+
+// GetGroupPath mocks base method.
+func (m *MockGroup) GetGroupPath(ctx context.Context, id int) ([]entity.GroupRef, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGroupPath", ctx, id)
+	ret0, _ := ret[0].([]entity.GroupRef)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGroupPath indicates an expected call of GetGroupPath.
+func (mr *MockGroupMockRecorder) GetGroupPath(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupPath", reflect.TypeOf((*MockGroup)(nil).GetGroupPath), ctx, id)
+}
+
+// GetGroupStats mocks base method.
+func (m *MockGroup) GetGroupStats(ctx context.Context) (entity.GroupStatsSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGroupStats", ctx)
+	ret0, _ := ret[0].(entity.GroupStatsSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGroupStats indicates an expected call of GetGroupStats.
+func (mr *MockGroupMockRecorder) GetGroupStats(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupStats", reflect.TypeOf((*MockGroup)(nil).GetGroupStats), ctx)
+}
+
+// GetGroupStatsByID mocks base method.
+func (m *MockGroup) GetGroupStatsByID(ctx context.Context, id int) (entity.GroupStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGroupStatsByID", ctx, id)
+	ret0, _ := ret[0].(entity.GroupStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGroupStatsByID indicates an expected call of GetGroupStatsByID.
+func (mr *MockGroupMockRecorder) GetGroupStatsByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupStatsByID", reflect.TypeOf((*MockGroup)(nil).GetGroupStatsByID), ctx, id)
+}
+
+// GetGroupWithSubgroups mocks base method.
+func (m *MockGroup) GetGroupWithSubgroups(ctx context.Context, id, maxDepth int) (entity.Group, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGroupWithSubgroups", ctx, id, maxDepth)
+	ret0, _ := ret[0].(entity.Group)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGroupWithSubgroups indicates an expected call of GetGroupWithSubgroups.
+func (mr *MockGroupMockRecorder) GetGroupWithSubgroups(ctx, id, maxDepth any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupWithSubgroups", reflect.TypeOf((*MockGroup)(nil).GetGroupWithSubgroups), ctx, id, maxDepth)
+}
+
+// GetGroups mocks base method.
+func (m *MockGroup) GetGroups(ctx context.Context, filter entity.GroupFilter, page entity.PageRequest) (entity.Page[entity.Group], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGroups", ctx, filter, page)
+	ret0, _ := ret[0].(entity.Page[entity.Group])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGroups indicates an expected call of GetGroups.
+func (mr *MockGroupMockRecorder) GetGroups(ctx, filter, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroups", reflect.TypeOf((*MockGroup)(nil).GetGroups), ctx, filter, page)
+}
+
+// MergeGroup mocks base method.
+func (m *MockGroup) MergeGroup(ctx context.Context, id, targetID, version int) (entity.GroupChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeGroup", ctx, id, targetID, version)
+	ret0, _ := ret[0].(entity.GroupChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MergeGroup indicates an expected call of MergeGroup.
+func (mr *MockGroupMockRecorder) MergeGroup(ctx, id, targetID, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeGroup", reflect.TypeOf((*MockGroup)(nil).MergeGroup), ctx, id, targetID, version)
+}
+
+// MoveGroup mocks base method.
+func (m *MockGroup) MoveGroup(ctx context.Context, id int, parentID *int, version int) (entity.GroupChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveGroup", ctx, id, parentID, version)
+	ret0, _ := ret[0].(entity.GroupChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MoveGroup indicates an expected call of MoveGroup.
+func (mr *MockGroupMockRecorder) MoveGroup(ctx, id, parentID, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveGroup", reflect.TypeOf((*MockGroup)(nil).MoveGroup), ctx, id, parentID, version)
+}
+
+// PatchGroup mocks base method.
+func (m *MockGroup) PatchGroup(ctx context.Context, id int, patch entity.GroupPatch) (entity.Group, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchGroup", ctx, id, patch)
+	ret0, _ := ret[0].(entity.Group)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchGroup indicates an expected call of PatchGroup.
+func (mr *MockGroupMockRecorder) PatchGroup(ctx, id, patch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchGroup", reflect.TypeOf((*MockGroup)(nil).PatchGroup), ctx, id, patch)
+}
+
+// RestoreGroup mocks base method.
+func (m *MockGroup) RestoreGroup(ctx context.Context, id int) (entity.Group, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreGroup", ctx, id)
+	ret0, _ := ret[0].(entity.Group)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreGroup indicates an expected call of RestoreGroup.
+func (mr *MockGroupMockRecorder) RestoreGroup(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreGroup", reflect.TypeOf((*MockGroup)(nil).RestoreGroup), ctx, id)
+}
+
+// SearchGroups mocks base method.
+func (m *MockGroup) SearchGroups(ctx context.Context, query entity.SearchQuery) ([]entity.GroupHit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchGroups", ctx, query)
+	ret0, _ := ret[0].([]entity.GroupHit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchGroups indicates an expected call of SearchGroups.
+func (mr *MockGroupMockRecorder) SearchGroups(ctx, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchGroups", reflect.TypeOf((*MockGroup)(nil).SearchGroups), ctx, query)
+}
+
+// UpdateGroup mocks base method.
+func (m *MockGroup) UpdateGroup(ctx context.Context, group entity.Group) (entity.Group, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateGroup", ctx, group)
+	ret0, _ := ret[0].(entity.Group)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateGroup indicates an expected call of UpdateGroup.
+func (mr *MockGroupMockRecorder) UpdateGroup(ctx, group any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGroup", reflect.TypeOf((*MockGroup)(nil).UpdateGroup), ctx, group)
+}
+
+// MockAudit is a mock of Audit interface.
+type MockAudit struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditMockRecorder
+	isgomock struct{}
+}
+
+// MockAuditMockRecorder is the mock recorder for MockAudit.
+type MockAuditMockRecorder struct {
+	mock *MockAudit
+}
+
+// NewMockAudit creates a new mock instance.
+func NewMockAudit(ctrl *gomock.Controller) *MockAudit {
+	mock := &MockAudit{ctrl: ctrl}
+	mock.recorder = &MockAuditMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAudit) EXPECT() *MockAuditMockRecorder {
+	return m.recorder
+}
+
+// GetAudit mocks base method.
+func (m *MockAudit) GetAudit(ctx context.Context, filter entity.AuditFilter, page entity.PageRequest) (entity.Page[entity.AuditEntry], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAudit", ctx, filter, page)
+	ret0, _ := ret[0].(entity.Page[entity.AuditEntry])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAudit indicates an expected call of GetAudit.
+func (mr *MockAuditMockRecorder) GetAudit(ctx, filter, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAudit", reflect.TypeOf((*MockAudit)(nil).GetAudit), ctx, filter, page)
+}
+
+// MockAccess is a mock of Access interface.
+type MockAccess struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccessMockRecorder
+	isgomock struct{}
+}
+
+// MockAccessMockRecorder is the mock recorder for MockAccess.
+type MockAccessMockRecorder struct {
+	mock *MockAccess
+}
+
+// NewMockAccess creates a new mock instance.
+func NewMockAccess(ctrl *gomock.Controller) *MockAccess {
+	mock := &MockAccess{ctrl: ctrl}
+	mock.recorder = &MockAccessMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccess) EXPECT() *MockAccessMockRecorder {
+	return m.recorder
+}
+
+// CreateGrant mocks base method.
+func (m *MockAccess) CreateGrant(ctx context.Context, grant entity.Grant) (entity.Grant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateGrant", ctx, grant)
+	ret0, _ := ret[0].(entity.Grant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateGrant indicates an expected call of CreateGrant.
+func (mr *MockAccessMockRecorder) CreateGrant(ctx, grant any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGrant", reflect.TypeOf((*MockAccess)(nil).CreateGrant), ctx, grant)
+}
+
+// DeleteGrant mocks base method.
+func (m *MockAccess) DeleteGrant(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteGrant", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteGrant indicates an expected call of DeleteGrant.
+func (mr *MockAccessMockRecorder) DeleteGrant(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGrant", reflect.TypeOf((*MockAccess)(nil).DeleteGrant), ctx, id)
+}
+
+// GetGrants mocks base method.
+func (m *MockAccess) GetGrants(ctx context.Context, subject string) ([]entity.Grant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGrants", ctx, subject)
+	ret0, _ := ret[0].([]entity.Grant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGrants indicates an expected call of GetGrants.
+func (mr *MockAccessMockRecorder) GetGrants(ctx, subject any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGrants", reflect.TypeOf((*MockAccess)(nil).GetGrants), ctx, subject)
+}
+
+// MockPrivacy is a mock of Privacy interface.
+type MockPrivacy struct {
+	ctrl     *gomock.Controller
+	recorder *MockPrivacyMockRecorder
+	isgomock struct{}
+}
+
+// MockPrivacyMockRecorder is the mock recorder for MockPrivacy.
+type MockPrivacyMockRecorder struct {
+	mock *MockPrivacy
+}
+
+// NewMockPrivacy creates a new mock instance.
+func NewMockPrivacy(ctrl *gomock.Controller) *MockPrivacy {
+	mock := &MockPrivacy{ctrl: ctrl}
+	mock.recorder = &MockPrivacyMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPrivacy) EXPECT() *MockPrivacyMockRecorder {
+	return m.recorder
+}
+
+// GetStudentContact mocks base method.
+func (m *MockPrivacy) GetStudentContact(ctx context.Context, id int) (entity.StudentContact, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStudentContact", ctx, id)
+	ret0, _ := ret[0].(entity.StudentContact)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStudentContact indicates an expected call of GetStudentContact.
+func (mr *MockPrivacyMockRecorder) GetStudentContact(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStudentContact", reflect.TypeOf((*MockPrivacy)(nil).GetStudentContact), ctx, id)
+}
+
+// StudentMask mocks base method.
+func (m *MockPrivacy) StudentMask(ctx context.Context, endpoint string) (func(*entity.Student) error, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StudentMask", ctx, endpoint)
+	ret0, _ := ret[0].(func(*entity.Student) error)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StudentMask indicates an expected call of StudentMask.
+func (mr *MockPrivacyMockRecorder) StudentMask(ctx, endpoint any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StudentMask", reflect.TypeOf((*MockPrivacy)(nil).StudentMask), ctx, endpoint)
+}
+
+// StudentView mocks base method.
+func (m *MockPrivacy) StudentView(ctx context.Context, endpoint string) (func(entity.Student) (string, error), error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StudentView", ctx, endpoint)
+	ret0, _ := ret[0].(func(entity.Student) (string, error))
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StudentView indicates an expected call of StudentView.
+func (mr *MockPrivacyMockRecorder) StudentView(ctx, endpoint any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StudentView", reflect.TypeOf((*MockPrivacy)(nil).StudentView), ctx, endpoint)
+}
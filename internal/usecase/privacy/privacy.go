@@ -0,0 +1,116 @@
+// Package privacy implements the response policy for the personal data of students:
+// which callers see the emails and phone numbers of students hidden, masked or in full.
+package privacy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/usecase"
+)
+
+// Roles tells the roles of the caller of a request on groups, see access.Roles.
+type Roles interface {
+	RoleOn(ctx context.Context) (func(groupID int) (string, error), error)
+}
+
+// UseCase implements the privacy use case interface. The view of a caller on the personal data of a student
+// follows from the role of the caller on the group of the student. Without roles, as when access control
+// is disabled, every caller has the admin role.
+type UseCase struct {
+	students usecase.Student
+	roles    Roles
+	policy   entity.PIIPolicy
+}
+
+// New creates a new privacy use case reading contact details through the student use case.
+func New(students usecase.Student, roles Roles, policy entity.PIIPolicy) *UseCase {
+	return &UseCase{
+		students: students,
+		roles:    roles,
+		policy:   policy,
+	}
+}
+
+// roleOn returns a function telling the role of the caller on a group.
+func (uc *UseCase) roleOn(ctx context.Context) (func(groupID int) (string, error), error) {
+	if uc.roles == nil {
+		return func(int) (string, error) { return entity.RoleAdmin, nil }, nil
+	}
+
+	return uc.roles.RoleOn(ctx)
+}
+
+// StudentView returns a function telling the view of the personal data of a student
+// the policy gives the caller on endpoints of the given kind.
+func (uc *UseCase) StudentView(ctx context.Context, endpoint string) (func(entity.Student) (string, error), error) {
+	roleOn, err := uc.roleOn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("PrivacyUseCase - StudentView - uc.roleOn: %w", err)
+	}
+
+	return func(s entity.Student) (string, error) {
+		role, err := roleOn(s.GroupID)
+		if err != nil {
+			return "", fmt.Errorf("PrivacyUseCase - StudentView - roleOn: %w", err)
+		}
+
+		return uc.policy.View(endpoint, role), nil
+	}, nil
+}
+
+// StudentMask returns a function that hides or masks the personal data of a student
+// as the policy says for the caller on endpoints of the given kind.
+func (uc *UseCase) StudentMask(ctx context.Context, endpoint string) (func(*entity.Student) error, error) {
+	view, err := uc.StudentView(ctx, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("PrivacyUseCase - StudentMask - uc.StudentView: %w", err)
+	}
+
+	return func(s *entity.Student) error {
+		v, err := view(*s)
+		if err != nil {
+			return fmt.Errorf("PrivacyUseCase - StudentMask - view: %w", err)
+		}
+
+		entity.MaskStudent(s, v)
+
+		return nil
+	}, nil
+}
+
+// GetStudentContact retrieves the contact details of a student in full,
+// if the caller has the contact role of the policy on the group of the student.
+func (uc *UseCase) GetStudentContact(ctx context.Context, id int) (entity.StudentContact, error) {
+	student, err := uc.students.GetStudentByID(ctx, id)
+	if err != nil {
+		return entity.StudentContact{}, fmt.Errorf("PrivacyUseCase - GetStudentContact - uc.students.GetStudentByID: %w", err)
+	}
+
+	roleOn, err := uc.roleOn(ctx)
+	if err != nil {
+		return entity.StudentContact{}, fmt.Errorf("PrivacyUseCase - GetStudentContact - uc.roleOn: %w", err)
+	}
+
+	role, err := roleOn(student.GroupID)
+	if err != nil {
+		return entity.StudentContact{}, fmt.Errorf("PrivacyUseCase - GetStudentContact - roleOn: %w", err)
+	}
+
+	if !entity.RoleIncludes(role, uc.policy.ContactRole) {
+		return entity.StudentContact{}, fmt.Errorf("PrivacyUseCase - GetStudentContact: %w", entity.ErrAccessDenied)
+	}
+
+	guardians := student.Guardians
+	if guardians == nil {
+		guardians = []entity.Guardian{}
+	}
+
+	return entity.StudentContact{
+		ID:        student.ID,
+		Email:     student.Email,
+		Phone:     student.Phone,
+		Guardians: guardians,
+	}, nil
+}
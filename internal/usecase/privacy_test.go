@@ -0,0 +1,186 @@
+package usecase_test
+
+import (
+	"testing"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/usecase/access"
+	"github.com/evrone/go-clean-template/internal/usecase/privacy"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var _piiPolicy = entity.PIIPolicy{ //nolint:gochecknoglobals // test policy
+	Detail:      map[string]string{entity.RoleWrite: entity.PIIMasked, entity.RoleAdmin: entity.PIIFull},
+	List:        map[string]string{entity.RoleAdmin: entity.PIIMasked},
+	ContactRole: entity.RoleWrite,
+}
+
+func privateStudents(t *testing.T) (*privacy.UseCase, *MockStudent, *MockGrantRepo, *MockGroupRepo) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	students := NewMockStudent(mockCtl)
+	grants := NewMockGrantRepo(mockCtl)
+	groups := NewMockGroupRepo(mockCtl)
+
+	roles := access.NewRoles(grants, groups, []string{"root"})
+
+	return privacy.New(students, roles, _piiPolicy), students, grants, groups
+}
+
+func TestStudentMask(t *testing.T) { //nolint:tparallel // data races here
+	t.Parallel()
+
+	uc, _, grants, groups := privateStudents(t)
+
+	ctx := secretary()
+	john := entity.Student{
+		ID:        1,
+		Name:      "John Doe",
+		Email:     "john@example.com",
+		Phone:     "+4915112345678",
+		GroupID:   7,
+		Guardians: []entity.Guardian{{Name: "Jane Doe", Relationship: "mother", Email: "jane@example.com"}},
+	}
+
+	tests := []struct {
+		name     string
+		endpoint string
+		mock     func()
+		res      entity.Student
+	}{
+		{
+			name:     "admin role on a detail endpoint",
+			endpoint: entity.PIIDetail,
+			mock: func() {
+				grants.EXPECT().GetGrants(ctx, "secretary").Return([]entity.Grant{grant(3, entity.RoleAdmin)}, nil)
+				groups.EXPECT().GetAncestors(ctx, 7).Return([]entity.Group{_optics, _physics, _university}, nil)
+			},
+			res: john,
+		},
+		{
+			name:     "write role on a detail endpoint",
+			endpoint: entity.PIIDetail,
+			mock: func() {
+				grants.EXPECT().GetGrants(ctx, "secretary").Return([]entity.Grant{
+					grant(3, entity.RoleRead), grant(7, entity.RoleWrite),
+				}, nil)
+				groups.EXPECT().GetAncestors(ctx, 7).Return([]entity.Group{_optics, _physics, _university}, nil)
+			},
+			res: entity.Student{
+				ID:        1,
+				Name:      "John Doe",
+				Email:     "j***@example.com",
+				Phone:     "+49********678",
+				GroupID:   7,
+				Guardians: []entity.Guardian{{Name: "Jane Doe", Relationship: "mother", Email: "j***@example.com"}},
+			},
+		},
+		{
+			name:     "write role on a list endpoint",
+			endpoint: entity.PIIList,
+			mock: func() {
+				grants.EXPECT().GetGrants(ctx, "secretary").Return([]entity.Grant{grant(3, entity.RoleWrite)}, nil)
+				groups.EXPECT().GetAncestors(ctx, 7).Return([]entity.Group{_optics, _physics, _university}, nil)
+			},
+			res: entity.Student{
+				ID:        1,
+				Name:      "John Doe",
+				GroupID:   7,
+				Guardians: []entity.Guardian{{Name: "Jane Doe", Relationship: "mother"}},
+			},
+		},
+		{
+			name:     "endpoint without views",
+			endpoint: entity.PIIExport,
+			mock: func() {
+				grants.EXPECT().GetGrants(ctx, "secretary").Return([]entity.Grant{grant(3, entity.RoleAdmin)}, nil)
+				groups.EXPECT().GetAncestors(ctx, 7).Return([]entity.Group{_optics, _physics, _university}, nil)
+			},
+			res: entity.Student{
+				ID:        1,
+				Name:      "John Doe",
+				GroupID:   7,
+				Guardians: []entity.Guardian{{Name: "Jane Doe", Relationship: "mother"}},
+			},
+		},
+	}
+
+	for _, tc := range tests { //nolint:paralleltest // data races here
+		localTc := tc
+
+		t.Run(localTc.name, func(t *testing.T) {
+			localTc.mock()
+
+			mask, err := uc.StudentMask(ctx, localTc.endpoint)
+			require.NoError(t, err)
+
+			student := john
+			require.NoError(t, mask(&student))
+
+			require.Equal(t, localTc.res, student)
+			require.Equal(t, "jane@example.com", john.Guardians[0].Email)
+		})
+	}
+}
+
+func TestGetStudentContact(t *testing.T) { //nolint:tparallel // data races here
+	t.Parallel()
+
+	uc, students, grants, groups := privateStudents(t)
+
+	ctx := secretary()
+	john := entity.Student{ID: 1, Name: "John Doe", Email: "john@example.com", Phone: "+4915112345678", GroupID: 7}
+
+	tests := []struct {
+		name string
+		mock func()
+		res  entity.StudentContact
+		err  error
+	}{
+		{
+			name: "contact role",
+			mock: func() {
+				students.EXPECT().GetStudentByID(ctx, 1).Return(john, nil)
+				grants.EXPECT().GetGrants(ctx, "secretary").Return([]entity.Grant{grant(3, entity.RoleWrite)}, nil)
+				groups.EXPECT().GetAncestors(ctx, 7).Return([]entity.Group{_optics, _physics, _university}, nil)
+			},
+			res: entity.StudentContact{ID: 1, Email: "john@example.com", Phone: "+4915112345678", Guardians: []entity.Guardian{}},
+			err: nil,
+		},
+		{
+			name: "read role",
+			mock: func() {
+				students.EXPECT().GetStudentByID(ctx, 1).Return(john, nil)
+				grants.EXPECT().GetGrants(ctx, "secretary").Return([]entity.Grant{grant(3, entity.RoleRead)}, nil)
+				groups.EXPECT().GetAncestors(ctx, 7).Return([]entity.Group{_optics, _physics, _university}, nil)
+			},
+			res: entity.StudentContact{},
+			err: entity.ErrAccessDenied,
+		},
+		{
+			name: "not found",
+			mock: func() {
+				students.EXPECT().GetStudentByID(ctx, 1).Return(entity.Student{}, entity.ErrNotFound)
+			},
+			res: entity.StudentContact{},
+			err: entity.ErrNotFound,
+		},
+	}
+
+	for _, tc := range tests { //nolint:paralleltest // data races here
+		localTc := tc
+
+		t.Run(localTc.name, func(t *testing.T) {
+			localTc.mock()
+
+			res, err := uc.GetStudentContact(ctx, 1)
+
+			require.Equal(t, localTc.res, res)
+			require.ErrorIs(t, err, localTc.err)
+		})
+	}
+}
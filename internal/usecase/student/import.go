@@ -0,0 +1,125 @@
+package student
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/usecase/batch"
+)
+
+// errNotCommitted undoes the students of an import that is not to be stored.
+var errNotCommitted = errors.New("not committed")
+
+// ImportStudents stores the students of a roster in a single transaction and reports the outcome
+// of every row. Each row is stored under its own savepoint, so that a row rejected by the database
+// does not hide the errors of the rows after it. Rows are kept only when none of them fails and dryRun is not set.
+func (uc *UseCase) ImportStudents(ctx context.Context, rows []entity.StudentImport, dryRun bool) (entity.ImportReport, error) {
+	rows = slices.Clone(rows)
+
+	if err := uc.resolveGroups(ctx, rows); err != nil {
+		return entity.ImportReport{}, fmt.Errorf("StudentUseCase - ImportStudents - uc.resolveGroups: %w", err)
+	}
+
+	report := entity.ImportReport{
+		DryRun: dryRun,
+		Total:  len(rows),
+		Rows:   make([]entity.ImportRow, len(rows)),
+	}
+
+	ops := make([]entity.BatchOp[entity.Student], 0, len(rows))
+	stored := make([]int, 0, len(rows)) // row index of each student
+
+	for i, row := range rows {
+		report.Rows[i] = entity.ImportRow{Row: row.Row, Status: entity.ImportValid, Errors: row.Errors}
+
+		if len(row.Errors) > 0 {
+			report.Rows[i].Status = entity.ImportFailed
+			report.Failed++
+
+			continue
+		}
+
+		ops = append(ops, entity.BatchOp[entity.Student]{Op: entity.BatchCreate, Item: row.Student})
+		stored = append(stored, i)
+	}
+
+	// Rows that passed the checks still go through the database to report constraint violations
+	commit := !dryRun && report.Failed == 0
+
+	var created entity.BatchReport[entity.Student]
+
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+
+		created, err = batch.Run(ctx, uc.tx, ops, true, uc.createImported)
+		if err != nil {
+			return fmt.Errorf("batch.Run: %w", err)
+		}
+
+		if !commit {
+			return errNotCommitted
+		}
+
+		return nil
+	})
+	if err != nil && !errors.Is(err, errNotCommitted) {
+		return entity.ImportReport{}, fmt.Errorf("StudentUseCase - ImportStudents - uc.tx.WithinTx: %w", err)
+	}
+
+	for j, result := range created.Results {
+		i := stored[j]
+
+		switch result.Status {
+		case entity.BatchFailed:
+			report.Rows[i].Status = entity.ImportFailed
+			report.Rows[i].Errors = importErrors(result.Err)
+			report.Failed++
+		case entity.BatchApplied:
+			if commit {
+				report.Rows[i].Status = entity.ImportCreated
+				report.Rows[i].StudentID = result.Item.ID
+				report.Created++
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// createImported stores a student of an import, whose group has been resolved already.
+func (uc *UseCase) createImported(ctx context.Context, op entity.BatchOp[entity.Student]) (entity.Student, error) {
+	student, err := uc.repo.CreateStudent(ctx, op.Item)
+	if err != nil {
+		return entity.Student{}, fmt.Errorf("uc.repo.CreateStudent: %w", err)
+	}
+
+	return student, nil
+}
+
+// resolveGroups sets the group of every row from its reference to an active group by ID or name,
+// rows referring to a missing or ambiguous group get an error instead.
+func (uc *UseCase) resolveGroups(ctx context.Context, rows []entity.StudentImport) error {
+	ids, names := entity.ImportGroupRefs(rows)
+
+	groups, err := uc.groups.FindGroups(ctx, ids, names)
+	if err != nil {
+		return fmt.Errorf("uc.groups.FindGroups: %w", err)
+	}
+
+	entity.ResolveImportGroups(rows, groups)
+
+	return nil
+}
+
+// importErrors describes why the database rejected a row.
+func importErrors(err error) []entity.FieldError {
+	var domainErr *entity.Error
+	if errors.As(err, &domainErr) && len(domainErr.Fields) > 0 {
+		return domainErr.Fields
+	}
+
+	return []entity.FieldError{{Message: err.Error()}}
+}
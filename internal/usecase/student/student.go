@@ -2,39 +2,100 @@ package student
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/evrone/go-clean-template/internal/entity"
 	"github.com/evrone/go-clean-template/internal/repo"
+	"github.com/evrone/go-clean-template/internal/usecase/batch"
+	"github.com/evrone/go-clean-template/pkg/translit"
 )
 
 // UseCase implements the student use case interface.
 type UseCase struct {
-	repo repo.StudentRepo
+	repo   repo.StudentRepo
+	groups repo.GroupRepo
+	tx     repo.Transactor
 }
 
 // New creates a new student use case.
-func New(r repo.StudentRepo) *UseCase {
+func New(r repo.StudentRepo, g repo.GroupRepo, tx repo.Transactor) *UseCase {
 	return &UseCase{
-		repo: r,
+		repo:   r,
+		groups: g,
+		tx:     tx,
 	}
 }
 
-// CreateStudent creates a new student.
+// checkGroup makes sure students are only assigned to active groups,
+// deleted groups still satisfy the foreign key.
+func (uc *UseCase) checkGroup(ctx context.Context, groupID int) error {
+	_, err := uc.groups.GetGroupByID(ctx, groupID)
+	if errors.Is(err, entity.ErrNotFound) {
+		return entity.ErrGroupNotFound
+	}
+
+	if err != nil {
+		return fmt.Errorf("uc.groups.GetGroupByID: %w", err)
+	}
+
+	return nil
+}
+
+// CreateStudent creates a new student, checking the group in the same transaction.
 func (uc *UseCase) CreateStudent(ctx context.Context, student entity.Student) (entity.Student, error) {
-	s, err := uc.repo.CreateStudent(ctx, student)
+	var created entity.Student
+
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.checkGroup(ctx, student.GroupID); err != nil {
+			return fmt.Errorf("uc.checkGroup: %w", err)
+		}
+
+		s, err := uc.repo.CreateStudent(ctx, student)
+		if err != nil {
+			return fmt.Errorf("uc.repo.CreateStudent: %w", err)
+		}
+
+		created = s
+
+		return nil
+	})
 	if err != nil {
-		return entity.Student{}, fmt.Errorf("StudentUseCase - CreateStudent - uc.repo.CreateStudent: %w", err)
+		return entity.Student{}, fmt.Errorf("StudentUseCase - CreateStudent - uc.tx.WithinTx: %w", err)
 	}
 
-	return s, nil
+	return created, nil
 }
 
-// GetStudents retrieves all students.
-func (uc *UseCase) GetStudents(ctx context.Context) ([]entity.Student, error) {
-	students, err := uc.repo.GetStudents(ctx)
+// GetStudents retrieves one page of students matching the filter.
+func (uc *UseCase) GetStudents(ctx context.Context, filter entity.StudentFilter, page entity.PageRequest) (entity.Page[entity.Student], error) {
+	students, err := uc.repo.GetStudents(ctx, filter, page)
 	if err != nil {
-		return nil, fmt.Errorf("StudentUseCase - GetStudents - uc.repo.GetStudents: %w", err)
+		return entity.Page[entity.Student]{}, fmt.Errorf("StudentUseCase - GetStudents - uc.repo.GetStudents: %w", err)
+	}
+
+	return students, nil
+}
+
+// GetGroupStudents retrieves one page of the active students of an existing group,
+// with recursive also those of its subgroups at any depth. With asOf it retrieves the roster
+// of the group at that time instead, the students enrolled in it then.
+func (uc *UseCase) GetGroupStudents(ctx context.Context, groupID int, recursive bool, asOf *time.Time, page entity.PageRequest) (entity.Page[entity.Student], error) {
+	if _, err := uc.groups.GetGroupByID(ctx, groupID); err != nil {
+		return entity.Page[entity.Student]{}, fmt.Errorf("StudentUseCase - GetGroupStudents - uc.groups.GetGroupByID: %w", err)
+	}
+
+	filter := entity.StudentFilter{
+		GroupID:   &groupID,
+		Recursive: recursive,
+		AsOf:      asOf,
+	}
+
+	students, err := uc.repo.GetStudents(ctx, filter, page)
+	if err != nil {
+		return entity.Page[entity.Student]{}, fmt.Errorf("StudentUseCase - GetGroupStudents - uc.repo.GetStudents: %w", err)
 	}
 
 	return students, nil
@@ -50,19 +111,135 @@ func (uc *UseCase) GetStudentByID(ctx context.Context, id int) (entity.Student,
 	return student, nil
 }
 
-// UpdateStudent updates an existing student.
-func (uc *UseCase) UpdateStudent(ctx context.Context, student entity.Student) error {
-	err := uc.repo.UpdateStudent(ctx, student)
+// GetStudentEnrollments retrieves the group memberships of an active student, the earliest first.
+func (uc *UseCase) GetStudentEnrollments(ctx context.Context, id int) ([]entity.Enrollment, error) {
+	if _, err := uc.repo.GetStudentByID(ctx, id); err != nil {
+		return nil, fmt.Errorf("StudentUseCase - GetStudentEnrollments - uc.repo.GetStudentByID: %w", err)
+	}
+
+	enrollments, err := uc.repo.GetEnrollments(ctx, id)
 	if err != nil {
-		return fmt.Errorf("StudentUseCase - UpdateStudent - uc.repo.UpdateStudent: %w", err)
+		return nil, fmt.Errorf("StudentUseCase - GetStudentEnrollments - uc.repo.GetEnrollments: %w", err)
 	}
 
-	return nil
+	return enrollments, nil
+}
+
+// GetStudentWithPath retrieves a student by ID together with the path from the root group down to its group.
+func (uc *UseCase) GetStudentWithPath(ctx context.Context, id int) (entity.Student, error) {
+	student, err := uc.repo.GetStudentByID(ctx, id)
+	if err != nil {
+		return entity.Student{}, fmt.Errorf("StudentUseCase - GetStudentWithPath - uc.repo.GetStudentByID: %w", err)
+	}
+
+	ancestors, err := uc.groups.GetAncestors(ctx, student.GroupID)
+	if err != nil {
+		return entity.Student{}, fmt.Errorf("StudentUseCase - GetStudentWithPath - uc.groups.GetAncestors: %w", err)
+	}
+
+	student.Path = entity.NewPath(ancestors)
+
+	return student, nil
+}
+
+// UpdateStudent updates an existing student and returns the stored student, checking the group in the same transaction.
+func (uc *UseCase) UpdateStudent(ctx context.Context, student entity.Student) (entity.Student, error) {
+	var updated entity.Student
+
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.checkGroup(ctx, student.GroupID); err != nil {
+			return fmt.Errorf("uc.checkGroup: %w", err)
+		}
+
+		s, err := uc.repo.UpdateStudent(ctx, student)
+		if err != nil {
+			return fmt.Errorf("uc.repo.UpdateStudent: %w", err)
+		}
+
+		updated = s
+
+		return nil
+	})
+	if err != nil {
+		return entity.Student{}, fmt.Errorf("StudentUseCase - UpdateStudent - uc.tx.WithinTx: %w", err)
+	}
+
+	return updated, nil
 }
 
-// DeleteStudent deletes a student by ID.
-func (uc *UseCase) DeleteStudent(ctx context.Context, id int) error {
-	err := uc.repo.DeleteStudent(ctx, id)
+// PatchStudent applies a partial update to a student, checking a new group in the same transaction.
+func (uc *UseCase) PatchStudent(ctx context.Context, id int, patch entity.StudentPatch) (entity.Student, error) {
+	var student entity.Student
+
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if patch.GroupID != nil {
+			if err := uc.checkGroup(ctx, *patch.GroupID); err != nil {
+				return fmt.Errorf("uc.checkGroup: %w", err)
+			}
+		}
+
+		s, err := uc.repo.PatchStudent(ctx, id, patch)
+		if err != nil {
+			return fmt.Errorf("uc.repo.PatchStudent: %w", err)
+		}
+
+		student = s
+
+		return nil
+	})
+	if err != nil {
+		return entity.Student{}, fmt.Errorf("StudentUseCase - PatchStudent - uc.tx.WithinTx: %w", err)
+	}
+
+	return student, nil
+}
+
+// ChangeStudentStatus changes the status of a student. Transitions the life cycle of a student does not allow,
+// such as from graduated back to active, are rejected unless the change overrides the check.
+// Changing to the current status changes nothing.
+func (uc *UseCase) ChangeStudentStatus(ctx context.Context, id int, change entity.StatusChange) (entity.Student, error) {
+	var student entity.Student
+
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		current, err := uc.repo.GetStudentByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("uc.repo.GetStudentByID: %w", err)
+		}
+
+		if change.Version != 0 && change.Version != current.Version {
+			return entity.ErrVersionMismatch
+		}
+
+		if change.Status == current.Status {
+			student = current
+
+			return nil
+		}
+
+		if !change.Override && !entity.CanTransition(current.Status, change.Status) {
+			return entity.ErrIllegalTransition
+		}
+
+		// The transition was checked against this version
+		s, err := uc.repo.PatchStudent(ctx, id, entity.StudentPatch{Status: &change.Status, Version: current.Version})
+		if err != nil {
+			return fmt.Errorf("uc.repo.PatchStudent: %w", err)
+		}
+
+		student = s
+
+		return nil
+	})
+	if err != nil {
+		return entity.Student{}, fmt.Errorf("StudentUseCase - ChangeStudentStatus - uc.tx.WithinTx: %w", err)
+	}
+
+	return student, nil
+}
+
+// DeleteStudent soft-deletes a student by ID, a non-zero version must match the stored one.
+func (uc *UseCase) DeleteStudent(ctx context.Context, id, version int) error {
+	err := uc.repo.DeleteStudent(ctx, id, version)
 	if err != nil {
 		return fmt.Errorf("StudentUseCase - DeleteStudent - uc.repo.DeleteStudent: %w", err)
 	}
@@ -70,8 +247,72 @@ func (uc *UseCase) DeleteStudent(ctx context.Context, id int) error {
 	return nil
 }
 
-// SearchStudents searches for students by name or group name.
-func (uc *UseCase) SearchStudents(ctx context.Context, query string) ([]entity.Student, error) {
+// RestoreStudent restores a soft-deleted student.
+func (uc *UseCase) RestoreStudent(ctx context.Context, id int) (entity.Student, error) {
+	student, err := uc.repo.RestoreStudent(ctx, id)
+	if err != nil {
+		return entity.Student{}, fmt.Errorf("StudentUseCase - RestoreStudent - uc.repo.RestoreStudent: %w", err)
+	}
+
+	return student, nil
+}
+
+// BatchStudents applies the operations in a single transaction and reports the outcome of each.
+// Every operation goes through the same checks as a single create, update or delete.
+// Atomic batches are committed only when every operation succeeds, otherwise the failed operations are skipped.
+func (uc *UseCase) BatchStudents(ctx context.Context, ops []entity.BatchOp[entity.Student], atomic bool) (entity.BatchReport[entity.Student], error) {
+	report, err := batch.Run(ctx, uc.tx, ops, atomic, uc.applyBatchOp)
+	if err != nil {
+		return entity.BatchReport[entity.Student]{}, fmt.Errorf("StudentUseCase - BatchStudents - batch.Run: %w", err)
+	}
+
+	return report, nil
+}
+
+// applyBatchOp applies a single batch operation and returns the stored student of creates and updates.
+func (uc *UseCase) applyBatchOp(ctx context.Context, op entity.BatchOp[entity.Student]) (entity.Student, error) {
+	switch op.Op {
+	case entity.BatchCreate:
+		student, err := uc.CreateStudent(ctx, op.Item)
+		if err != nil {
+			return entity.Student{}, fmt.Errorf("uc.CreateStudent: %w", err)
+		}
+
+		return student, nil
+	case entity.BatchUpdate:
+		student, err := uc.UpdateStudent(ctx, op.Item)
+		if err != nil {
+			return entity.Student{}, fmt.Errorf("uc.UpdateStudent: %w", err)
+		}
+
+		return student, nil
+	case entity.BatchDelete:
+		if err := uc.DeleteStudent(ctx, op.Item.ID, op.Item.Version); err != nil {
+			return entity.Student{}, fmt.Errorf("uc.DeleteStudent: %w", err)
+		}
+
+		return entity.Student{}, nil
+	default:
+		return entity.Student{}, entity.ErrUnknownOperation
+	}
+}
+
+// ExportStudents returns the export of the students matching the filter.
+func (uc *UseCase) ExportStudents(ctx context.Context, filter entity.StudentFilter) (entity.Export[entity.Student], error) {
+	return func(yield func(entity.Student) error) error {
+		if err := uc.repo.ExportStudents(ctx, filter, yield); err != nil {
+			return fmt.Errorf("StudentUseCase - ExportStudents - uc.repo.ExportStudents: %w", err)
+		}
+
+		return nil
+	}, nil
+}
+
+// SearchStudents searches for students by name or group name,
+// matching the query in both Cyrillic and Latin spelling.
+func (uc *UseCase) SearchStudents(ctx context.Context, query entity.SearchQuery) ([]entity.StudentHit, error) {
+	query.Terms = translit.Variants(strings.TrimSpace(query.Text))
+
 	students, err := uc.repo.SearchStudents(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("StudentUseCase - SearchStudents - uc.repo.SearchStudents: %w", err)
@@ -0,0 +1,550 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/usecase/student"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func studentUseCase(t *testing.T) (*student.UseCase, *MockStudentRepo, *MockGroupRepo) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	repo := NewMockStudentRepo(mockCtl)
+	groups := NewMockGroupRepo(mockCtl)
+
+	useCase := student.New(repo, groups, transactor(mockCtl))
+
+	return useCase, repo, groups
+}
+
+func TestCreateStudent(t *testing.T) { //nolint:tparallel // data races here
+	t.Parallel()
+
+	students, repo, groups := studentUseCase(t)
+
+	tests := []struct {
+		name    string
+		student entity.Student
+		mock    func()
+		res     entity.Student
+		err     error
+	}{
+		{
+			name:    "active group",
+			student: entity.Student{Name: "John", Email: "john@example.com", GroupID: 1},
+			mock: func() {
+				groups.EXPECT().GetGroupByID(context.Background(), 1).Return(entity.Group{ID: 1}, nil)
+				repo.EXPECT().CreateStudent(context.Background(), entity.Student{Name: "John", Email: "john@example.com", GroupID: 1}).
+					Return(entity.Student{ID: 7, Name: "John", Email: "john@example.com", GroupID: 1, Version: 1}, nil)
+			},
+			res: entity.Student{ID: 7, Name: "John", Email: "john@example.com", GroupID: 1, Version: 1},
+			err: nil,
+		},
+		{
+			name:    "deleted or missing group",
+			student: entity.Student{Name: "John", Email: "john@example.com", GroupID: 2},
+			mock: func() {
+				groups.EXPECT().GetGroupByID(context.Background(), 2).
+					Return(entity.Group{}, entity.NewError(entity.ErrNotFound, "group_not_found", "group not found"))
+			},
+			res: entity.Student{},
+			err: entity.ErrGroupNotFound,
+		},
+		{
+			name:    "repo error",
+			student: entity.Student{Name: "John", Email: "john@example.com", GroupID: 1},
+			mock: func() {
+				groups.EXPECT().GetGroupByID(context.Background(), 1).Return(entity.Group{}, errInternalServErr)
+			},
+			res: entity.Student{},
+			err: errInternalServErr,
+		},
+	}
+
+	for _, tc := range tests { //nolint:paralleltest // data races here
+		localTc := tc
+
+		t.Run(localTc.name, func(t *testing.T) {
+			localTc.mock()
+
+			res, err := students.CreateStudent(context.Background(), localTc.student)
+
+			require.Equal(t, localTc.res, res)
+			require.ErrorIs(t, err, localTc.err)
+		})
+	}
+}
+
+func TestChangeStudentStatus(t *testing.T) { //nolint:tparallel // data races here
+	t.Parallel()
+
+	students, repo, _ := studentUseCase(t)
+
+	ctx := context.Background()
+	active := entity.Student{ID: 1, Name: "John", GroupID: 1, Status: entity.StudentActive, Version: 3}
+	graduated := entity.Student{ID: 1, Name: "John", GroupID: 1, Status: entity.StudentGraduated, Version: 3}
+
+	tests := []struct {
+		name   string
+		change entity.StatusChange
+		mock   func()
+		res    entity.Student
+		err    error
+	}{
+		{
+			name:   "allowed transition",
+			change: entity.StatusChange{Status: entity.StudentGraduated, Version: 3},
+			mock: func() {
+				repo.EXPECT().GetStudentByID(ctx, 1).Return(active, nil)
+				repo.EXPECT().PatchStudent(ctx, 1, entity.StudentPatch{Status: &graduated.Status, Version: 3}).
+					Return(entity.Student{ID: 1, Name: "John", GroupID: 1, Status: entity.StudentGraduated, Version: 4}, nil)
+			},
+			res: entity.Student{ID: 1, Name: "John", GroupID: 1, Status: entity.StudentGraduated, Version: 4},
+			err: nil,
+		},
+		{
+			name:   "graduated back to active",
+			change: entity.StatusChange{Status: entity.StudentActive},
+			mock: func() {
+				repo.EXPECT().GetStudentByID(ctx, 1).Return(graduated, nil)
+			},
+			res: entity.Student{},
+			err: entity.ErrIllegalTransition,
+		},
+		{
+			name:   "graduated back to active with an override",
+			change: entity.StatusChange{Status: entity.StudentActive, Override: true},
+			mock: func() {
+				repo.EXPECT().GetStudentByID(ctx, 1).Return(graduated, nil)
+				repo.EXPECT().PatchStudent(ctx, 1, entity.StudentPatch{Status: &active.Status, Version: 3}).
+					Return(entity.Student{ID: 1, Name: "John", GroupID: 1, Status: entity.StudentActive, Version: 4}, nil)
+			},
+			res: entity.Student{ID: 1, Name: "John", GroupID: 1, Status: entity.StudentActive, Version: 4},
+			err: nil,
+		},
+		{
+			name:   "same status",
+			change: entity.StatusChange{Status: entity.StudentActive},
+			mock: func() {
+				repo.EXPECT().GetStudentByID(ctx, 1).Return(active, nil)
+			},
+			res: active,
+			err: nil,
+		},
+		{
+			name:   "stale version",
+			change: entity.StatusChange{Status: entity.StudentOnLeave, Version: 2},
+			mock: func() {
+				repo.EXPECT().GetStudentByID(ctx, 1).Return(active, nil)
+			},
+			res: entity.Student{},
+			err: entity.ErrVersionMismatch,
+		},
+	}
+
+	for _, tc := range tests { //nolint:paralleltest // data races here
+		localTc := tc
+
+		t.Run(localTc.name, func(t *testing.T) {
+			localTc.mock()
+
+			res, err := students.ChangeStudentStatus(ctx, 1, localTc.change)
+
+			require.Equal(t, localTc.res, res)
+			require.ErrorIs(t, err, localTc.err)
+		})
+	}
+}
+
+func TestGetGroupStudents(t *testing.T) { //nolint:tparallel // data races here
+	t.Parallel()
+
+	students, repo, groups := studentUseCase(t)
+
+	page := entity.PageRequest{Limit: 2, Sort: entity.SortByID}
+	asOf := time.Date(2024, time.September, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		recursive bool
+		asOf      *time.Time
+		mock      func()
+		res       entity.Page[entity.Student]
+		err       error
+	}{
+		{
+			name:      "with subgroups",
+			recursive: true,
+			asOf:      nil,
+			mock: func() {
+				groups.EXPECT().GetGroupByID(context.Background(), 1).Return(entity.Group{ID: 1}, nil)
+				repo.EXPECT().GetStudents(context.Background(), entity.StudentFilter{GroupID: intPtr(1), Recursive: true}, page).
+					Return(entity.Page[entity.Student]{Items: []entity.Student{{ID: 1, GroupID: 1}, {ID: 2, GroupID: 4}}, Total: 2}, nil)
+			},
+			res: entity.Page[entity.Student]{Items: []entity.Student{{ID: 1, GroupID: 1}, {ID: 2, GroupID: 4}}, Total: 2},
+			err: nil,
+		},
+		{
+			name:      "roster at a past time",
+			recursive: false,
+			asOf:      &asOf,
+			mock: func() {
+				groups.EXPECT().GetGroupByID(context.Background(), 1).Return(entity.Group{ID: 1}, nil)
+				repo.EXPECT().GetStudents(context.Background(), entity.StudentFilter{GroupID: intPtr(1), AsOf: &asOf}, page).
+					Return(entity.Page[entity.Student]{Items: []entity.Student{{ID: 3, GroupID: 4}}, Total: 1}, nil)
+			},
+			res: entity.Page[entity.Student]{Items: []entity.Student{{ID: 3, GroupID: 4}}, Total: 1},
+			err: nil,
+		},
+		{
+			name:      "missing group",
+			recursive: false,
+			asOf:      nil,
+			mock: func() {
+				groups.EXPECT().GetGroupByID(context.Background(), 1).Return(entity.Group{}, entity.ErrNotFound)
+			},
+			res: entity.Page[entity.Student]{},
+			err: entity.ErrNotFound,
+		},
+	}
+
+	for _, tc := range tests { //nolint:paralleltest // data races here
+		localTc := tc
+
+		t.Run(localTc.name, func(t *testing.T) {
+			localTc.mock()
+
+			res, err := students.GetGroupStudents(context.Background(), 1, localTc.recursive, localTc.asOf, page)
+
+			require.Equal(t, localTc.res, res)
+			require.ErrorIs(t, err, localTc.err)
+		})
+	}
+}
+
+func TestGetStudentEnrollments(t *testing.T) { //nolint:tparallel // data races here
+	t.Parallel()
+
+	students, repo, _ := studentUseCase(t)
+
+	started := time.Date(2024, time.September, 1, 0, 0, 0, 0, time.UTC)
+	transferred := started.AddDate(0, 3, 0)
+	history := []entity.Enrollment{
+		{ID: 1, StudentID: 1, GroupID: 3, StartedAt: started, EndedAt: &transferred, Reason: entity.EnrollmentEnrolled,
+			EndReason: entity.EnrollmentTransferred},
+		{ID: 2, StudentID: 1, GroupID: 7, StartedAt: transferred, Reason: entity.EnrollmentTransferred},
+	}
+
+	tests := []struct {
+		name string
+		mock func()
+		res  []entity.Enrollment
+		err  error
+	}{
+		{
+			name: "transferred student",
+			mock: func() {
+				repo.EXPECT().GetStudentByID(context.Background(), 1).Return(entity.Student{ID: 1, GroupID: 7}, nil)
+				repo.EXPECT().GetEnrollments(context.Background(), 1).Return(history, nil)
+			},
+			res: history,
+			err: nil,
+		},
+		{
+			name: "missing student",
+			mock: func() {
+				repo.EXPECT().GetStudentByID(context.Background(), 1).Return(entity.Student{}, entity.ErrNotFound)
+			},
+			res: nil,
+			err: entity.ErrNotFound,
+		},
+	}
+
+	for _, tc := range tests { //nolint:paralleltest // data races here
+		localTc := tc
+
+		t.Run(localTc.name, func(t *testing.T) {
+			localTc.mock()
+
+			res, err := students.GetStudentEnrollments(context.Background(), 1)
+
+			require.Equal(t, localTc.res, res)
+			require.ErrorIs(t, err, localTc.err)
+		})
+	}
+}
+
+func TestSearchStudents(t *testing.T) { //nolint:tparallel // data races here
+	t.Parallel()
+
+	students, repo, _ := studentUseCase(t)
+
+	tests := []struct {
+		name  string
+		query entity.SearchQuery
+		mock  func()
+		res   []entity.StudentHit
+		err   error
+	}{
+		{
+			name:  "cyrillic query",
+			query: entity.SearchQuery{Text: " Иван ", Mode: entity.SearchPrefix, Limit: 10},
+			mock: func() {
+				repo.EXPECT().SearchStudents(context.Background(), entity.SearchQuery{
+					Text: " Иван ", Terms: []string{"иван", "ivan"}, Mode: entity.SearchPrefix, Limit: 10,
+				}).Return([]entity.StudentHit{{Student: entity.Student{ID: 1, Name: "Ivan"}, Rank: 0.5}}, nil)
+			},
+			res: []entity.StudentHit{{Student: entity.Student{ID: 1, Name: "Ivan"}, Rank: 0.5}},
+			err: nil,
+		},
+		{
+			name:  "latin query",
+			query: entity.SearchQuery{Text: "Shchukin", Mode: entity.SearchFuzzy, Limit: 10},
+			mock: func() {
+				repo.EXPECT().SearchStudents(context.Background(), entity.SearchQuery{
+					Text: "Shchukin", Terms: []string{"shchukin", "щукин"}, Mode: entity.SearchFuzzy, Limit: 10,
+				}).Return(nil, nil)
+			},
+			res: nil,
+			err: nil,
+		},
+		{
+			name:  "repo error",
+			query: entity.SearchQuery{Text: "123", Mode: entity.SearchExact, Limit: 10},
+			mock: func() {
+				repo.EXPECT().SearchStudents(context.Background(), entity.SearchQuery{
+					Text: "123", Terms: []string{"123"}, Mode: entity.SearchExact, Limit: 10,
+				}).Return(nil, errInternalServErr)
+			},
+			res: nil,
+			err: errInternalServErr,
+		},
+	}
+
+	for _, tc := range tests { //nolint:paralleltest // data races here
+		localTc := tc
+
+		t.Run(localTc.name, func(t *testing.T) {
+			localTc.mock()
+
+			res, err := students.SearchStudents(context.Background(), localTc.query)
+
+			require.Equal(t, localTc.res, res)
+			require.ErrorIs(t, err, localTc.err)
+		})
+	}
+}
+
+func TestImportStudents(t *testing.T) { //nolint:tparallel // data races here
+	t.Parallel()
+
+	students, repo, groups := studentUseCase(t)
+
+	ann := entity.Student{Name: "Ann", Email: "ann@example.com"}
+	bob := entity.Student{Name: "Bob", Email: "bob@example.com"}
+	taken := entity.NewError(entity.ErrConflict, "student_already_exists", "student already exists",
+		entity.FieldError{Field: "email", Message: "is already taken"})
+
+	tests := []struct {
+		name   string
+		rows   []entity.StudentImport
+		dryRun bool
+		mock   func()
+		res    entity.ImportReport
+		err    error
+	}{
+		{
+			name: "groups by id and name",
+			rows: []entity.StudentImport{
+				{Row: 2, Student: ann, Group: "1"},
+				{Row: 3, Student: bob, Group: "cs-1"},
+			},
+			mock: func() {
+				groups.EXPECT().FindGroups(context.Background(), []int{1}, []string{"cs-1"}).
+					Return([]entity.Group{{ID: 1, Name: "Math"}, {ID: 2, Name: "CS-1"}}, nil)
+				repo.EXPECT().CreateStudent(context.Background(), entity.Student{Name: "Ann", Email: "ann@example.com", GroupID: 1}).
+					Return(entity.Student{ID: 10}, nil)
+				repo.EXPECT().CreateStudent(context.Background(), entity.Student{Name: "Bob", Email: "bob@example.com", GroupID: 2}).
+					Return(entity.Student{ID: 11}, nil)
+			},
+			res: entity.ImportReport{Total: 2, Created: 2, Rows: []entity.ImportRow{
+				{Row: 2, Status: entity.ImportCreated, StudentID: 10},
+				{Row: 3, Status: entity.ImportCreated, StudentID: 11},
+			}},
+			err: nil,
+		},
+		{
+			name:   "dry run",
+			rows:   []entity.StudentImport{{Row: 2, Student: ann, Group: "1"}},
+			dryRun: true,
+			mock: func() {
+				groups.EXPECT().FindGroups(context.Background(), []int{1}, []string{}).
+					Return([]entity.Group{{ID: 1, Name: "Math"}}, nil)
+				repo.EXPECT().CreateStudent(context.Background(), entity.Student{Name: "Ann", Email: "ann@example.com", GroupID: 1}).
+					Return(entity.Student{ID: 10}, nil)
+			},
+			res: entity.ImportReport{DryRun: true, Total: 1, Rows: []entity.ImportRow{
+				{Row: 2, Status: entity.ImportValid},
+			}},
+			err: nil,
+		},
+		{
+			name: "failed rows",
+			rows: []entity.StudentImport{
+				{Row: 2, Student: ann, Group: "7"},
+				{Row: 3, Student: bob, Group: "1"},
+				{Row: 4, Student: ann, Group: "math"},
+			},
+			mock: func() {
+				groups.EXPECT().FindGroups(context.Background(), []int{7, 1}, []string{"math"}).
+					Return([]entity.Group{{ID: 1, Name: "Math"}, {ID: 3, Name: "math"}}, nil)
+				repo.EXPECT().CreateStudent(context.Background(), entity.Student{Name: "Bob", Email: "bob@example.com", GroupID: 1}).
+					Return(entity.Student{}, taken)
+			},
+			res: entity.ImportReport{Total: 3, Failed: 3, Rows: []entity.ImportRow{
+				{Row: 2, Status: entity.ImportFailed, Errors: []entity.FieldError{{Field: "group", Message: "group does not exist"}}},
+				{Row: 3, Status: entity.ImportFailed, Errors: []entity.FieldError{{Field: "email", Message: "is already taken"}}},
+				{Row: 4, Status: entity.ImportFailed, Errors: []entity.FieldError{{Field: "group", Message: "several groups have this name, use the group ID"}}},
+			}},
+			err: nil,
+		},
+		{
+			name: "repo error",
+			rows: []entity.StudentImport{{Row: 2, Student: ann, Group: "1"}},
+			mock: func() {
+				groups.EXPECT().FindGroups(context.Background(), []int{1}, []string{}).Return(nil, errInternalServErr)
+			},
+			res: entity.ImportReport{},
+			err: errInternalServErr,
+		},
+		{
+			name: "database error",
+			rows: []entity.StudentImport{{Row: 2, Student: ann, Group: "1"}},
+			mock: func() {
+				groups.EXPECT().FindGroups(context.Background(), []int{1}, []string{}).
+					Return([]entity.Group{{ID: 1, Name: "Math"}}, nil)
+				repo.EXPECT().CreateStudent(context.Background(), entity.Student{Name: "Ann", Email: "ann@example.com", GroupID: 1}).
+					Return(entity.Student{}, errInternalServErr)
+			},
+			res: entity.ImportReport{},
+			err: errInternalServErr,
+		},
+	}
+
+	for _, tc := range tests { //nolint:paralleltest // data races here
+		localTc := tc
+
+		t.Run(localTc.name, func(t *testing.T) {
+			localTc.mock()
+
+			res, err := students.ImportStudents(context.Background(), localTc.rows, localTc.dryRun)
+
+			require.Equal(t, localTc.res, res)
+			require.ErrorIs(t, err, localTc.err)
+		})
+	}
+}
+
+func TestBatchStudents(t *testing.T) { //nolint:tparallel // data races here
+	t.Parallel()
+
+	students, repo, groups := studentUseCase(t)
+
+	ctx := context.Background()
+	ops := []entity.BatchOp[entity.Student]{
+		{Op: entity.BatchUpdate, Item: entity.Student{ID: 1, Name: "Ann", Email: "ann@example.com", GroupID: 2, Version: 1}},
+		{Op: entity.BatchDelete, Item: entity.Student{ID: 2, Version: 4}},
+		{Op: entity.BatchUpdate, Item: entity.Student{ID: 3, Name: "Bob", Email: "bob@example.com", GroupID: 2, Version: 1}},
+	}
+	ann := entity.Student{ID: 1, Name: "Ann", GroupID: 2, Version: 2}
+	bob := entity.Student{ID: 3, Name: "Bob", GroupID: 2, Version: 2}
+	notFound := entity.NewError(entity.ErrNotFound, "group_not_found", "group not found")
+
+	tests := []struct {
+		name   string
+		atomic bool
+		mock   func()
+		res    entity.BatchReport[entity.Student]
+		err    error
+	}{
+		{
+			name:   "all applied",
+			atomic: true,
+			mock: func() {
+				groups.EXPECT().GetGroupByID(ctx, 2).Return(entity.Group{ID: 2}, nil).Times(2)
+				repo.EXPECT().UpdateStudent(ctx, ops[0].Item).Return(ann, nil)
+				repo.EXPECT().DeleteStudent(ctx, 2, 4).Return(nil)
+				repo.EXPECT().UpdateStudent(ctx, ops[2].Item).Return(bob, nil)
+			},
+			res: entity.BatchReport[entity.Student]{Atomic: true, Total: 3, Applied: 3, Results: []entity.BatchResult[entity.Student]{
+				{Op: entity.BatchUpdate, Status: entity.BatchApplied, Item: &ann},
+				{Op: entity.BatchDelete, Status: entity.BatchApplied},
+				{Op: entity.BatchUpdate, Status: entity.BatchApplied, Item: &bob},
+			}},
+			err: nil,
+		},
+		{
+			name:   "atomic rollback",
+			atomic: true,
+			mock: func() {
+				groups.EXPECT().GetGroupByID(ctx, 2).Return(entity.Group{ID: 2}, nil).Times(2)
+				repo.EXPECT().UpdateStudent(ctx, ops[0].Item).Return(ann, nil)
+				repo.EXPECT().DeleteStudent(ctx, 2, 4).Return(entity.ErrVersionMismatch)
+				repo.EXPECT().UpdateStudent(ctx, ops[2].Item).Return(bob, nil)
+			},
+			res: entity.BatchReport[entity.Student]{Atomic: true, Total: 3, Failed: 1, Results: []entity.BatchResult[entity.Student]{
+				{Op: entity.BatchUpdate, Status: entity.BatchRolledBack},
+				{Op: entity.BatchDelete, Status: entity.BatchFailed, Err: entity.ErrVersionMismatch},
+				{Op: entity.BatchUpdate, Status: entity.BatchRolledBack},
+			}},
+			err: nil,
+		},
+		{
+			name:   "best effort",
+			atomic: false,
+			mock: func() {
+				groups.EXPECT().GetGroupByID(ctx, 2).Return(entity.Group{}, notFound)
+				repo.EXPECT().DeleteStudent(ctx, 2, 4).Return(nil)
+				groups.EXPECT().GetGroupByID(ctx, 2).Return(entity.Group{ID: 2}, nil)
+				repo.EXPECT().UpdateStudent(ctx, ops[2].Item).Return(bob, nil)
+			},
+			res: entity.BatchReport[entity.Student]{Total: 3, Applied: 2, Failed: 1, Results: []entity.BatchResult[entity.Student]{
+				{Op: entity.BatchUpdate, Status: entity.BatchFailed, Err: entity.ErrGroupNotFound},
+				{Op: entity.BatchDelete, Status: entity.BatchApplied},
+				{Op: entity.BatchUpdate, Status: entity.BatchApplied, Item: &bob},
+			}},
+			err: nil,
+		},
+		{
+			name:   "repo error",
+			atomic: true,
+			mock: func() {
+				groups.EXPECT().GetGroupByID(ctx, 2).Return(entity.Group{ID: 2}, nil)
+				repo.EXPECT().UpdateStudent(ctx, ops[0].Item).Return(entity.Student{}, errInternalServErr)
+			},
+			res: entity.BatchReport[entity.Student]{},
+			err: errInternalServErr,
+		},
+	}
+
+	for _, tc := range tests { //nolint:paralleltest // data races here
+		localTc := tc
+
+		t.Run(localTc.name, func(t *testing.T) {
+			localTc.mock()
+
+			res, err := students.BatchStudents(context.Background(), ops, localTc.atomic)
+
+			require.Equal(t, localTc.res, res)
+			require.ErrorIs(t, err, localTc.err)
+		})
+	}
+}
@@ -0,0 +1,2 @@
+ALTER TABLE students DROP COLUMN IF EXISTS version;
+ALTER TABLE groups DROP COLUMN IF EXISTS version;
@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS idx_groups_active_parent_id;
+DROP INDEX IF EXISTS idx_students_active_group_id;
+
+ALTER TABLE students
+    DROP COLUMN IF EXISTS deleted_at,
+    DROP COLUMN IF EXISTS updated_at,
+    DROP COLUMN IF EXISTS created_at;
+
+ALTER TABLE groups
+    DROP COLUMN IF EXISTS deleted_at,
+    DROP COLUMN IF EXISTS updated_at,
+    DROP COLUMN IF EXISTS created_at;
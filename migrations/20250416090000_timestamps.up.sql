@@ -0,0 +1,14 @@
+-- Creation and modification times, soft-deleted rows keep the time of deletion
+ALTER TABLE groups
+    ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ NULL;
+
+ALTER TABLE students
+    ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ NULL;
+
+-- Lists only ever look at active rows unless asked otherwise
+CREATE INDEX IF NOT EXISTS idx_students_active_group_id ON students(group_id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_groups_active_parent_id ON groups(parent_id) WHERE deleted_at IS NULL;
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_groups_name_trgm;
+DROP INDEX IF EXISTS idx_students_name_trgm;
+DROP INDEX IF EXISTS idx_groups_search;
+DROP INDEX IF EXISTS idx_students_search;
+
+ALTER TABLE groups DROP COLUMN IF EXISTS search;
+ALTER TABLE students DROP COLUMN IF EXISTS search;
+
+DROP EXTENSION IF EXISTS pg_trgm;
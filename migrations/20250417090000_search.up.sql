@@ -0,0 +1,12 @@
+-- Full-text and trigram search over student and group names
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE students ADD COLUMN IF NOT EXISTS search tsvector
+    GENERATED ALWAYS AS (to_tsvector('simple', name)) STORED;
+ALTER TABLE groups ADD COLUMN IF NOT EXISTS search tsvector
+    GENERATED ALWAYS AS (to_tsvector('simple', name)) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_students_search ON students USING GIN (search);
+CREATE INDEX IF NOT EXISTS idx_groups_search ON groups USING GIN (search);
+CREATE INDEX IF NOT EXISTS idx_students_name_trgm ON students USING GIN (name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_groups_name_trgm ON groups USING GIN (name gin_trgm_ops);
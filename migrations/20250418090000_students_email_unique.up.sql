@@ -0,0 +1,2 @@
+-- Emails identify active students regardless of case, deleted students free their email
+CREATE UNIQUE INDEX IF NOT EXISTS idx_students_email_unique ON students (LOWER(email)) WHERE deleted_at IS NULL;
@@ -0,0 +1,12 @@
+-- Roles of subjects on groups, a grant covers the group and all its descendants,
+-- grants without a group cover every group
+CREATE TABLE IF NOT EXISTS access_grants (
+    id SERIAL PRIMARY KEY,
+    subject VARCHAR(255) NOT NULL,
+    group_id INTEGER REFERENCES groups(id) ON DELETE CASCADE,
+    role VARCHAR(16) NOT NULL CHECK (role IN ('read', 'write', 'admin')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT access_grants_subject_group_key UNIQUE NULLS NOT DISTINCT (subject, group_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_access_grants_group_id ON access_grants (group_id);
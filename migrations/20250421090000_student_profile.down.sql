@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_students_enrollment_number_unique;
+
+ALTER TABLE students
+    DROP COLUMN IF EXISTS guardians,
+    DROP COLUMN IF EXISTS status,
+    DROP COLUMN IF EXISTS enrollment_number,
+    DROP COLUMN IF EXISTS phone,
+    DROP COLUMN IF EXISTS date_of_birth;
@@ -0,0 +1,12 @@
+-- Personal data of students, the status follows the life cycle of a student
+ALTER TABLE students
+    ADD COLUMN IF NOT EXISTS date_of_birth DATE NULL,
+    ADD COLUMN IF NOT EXISTS phone VARCHAR(32) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS enrollment_number VARCHAR(32) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS status VARCHAR(16) NOT NULL DEFAULT 'active'
+        CHECK (status IN ('active', 'on_leave', 'graduated', 'expelled')),
+    ADD COLUMN IF NOT EXISTS guardians JSONB NOT NULL DEFAULT '[]';
+
+-- Enrollment numbers identify active students, deleted students free their number
+CREATE UNIQUE INDEX IF NOT EXISTS idx_students_enrollment_number_unique ON students (enrollment_number)
+    WHERE enrollment_number <> '' AND deleted_at IS NULL;
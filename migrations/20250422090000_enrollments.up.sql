@@ -0,0 +1,21 @@
+-- Group memberships of students over time, a student has at most one open enrollment,
+-- the one in its current group, while the student is active
+CREATE TABLE IF NOT EXISTS enrollments (
+    id SERIAL PRIMARY KEY,
+    student_id INTEGER NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    group_id INTEGER NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
+    started_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    ended_at TIMESTAMPTZ NULL,
+    reason VARCHAR(32) NOT NULL,
+    end_reason VARCHAR(32) NULL,
+    CHECK (ended_at IS NULL OR ended_at >= started_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_enrollments_student_id ON enrollments (student_id, started_at);
+CREATE INDEX IF NOT EXISTS idx_enrollments_group_id ON enrollments (group_id, started_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_enrollments_open_unique ON enrollments (student_id) WHERE ended_at IS NULL;
+
+-- Earlier group changes are unknown, existing students are enrolled in their current group since their creation
+INSERT INTO enrollments (student_id, group_id, started_at, ended_at, reason, end_reason)
+SELECT id, group_id, created_at, deleted_at, 'enrolled', CASE WHEN deleted_at IS NOT NULL THEN 'withdrawn' END
+FROM students;
@@ -0,0 +1,85 @@
+// Package translit converts text between the Cyrillic and Latin alphabets,
+// so a name typed in one script can be found when it is stored in the other.
+package translit
+
+import (
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+//nolint:gochecknoglobals // transliteration tables
+var (
+	_toLatin = map[rune]string{
+		'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh",
+		'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+		'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+		'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+		'я': "ya",
+	}
+
+	// _toCyrillic is ordered so that longer letter combinations win over their prefixes.
+	_toCyrillic = []struct{ latin, cyrillic string }{
+		{"shch", "щ"},
+		{"zh", "ж"}, {"kh", "х"}, {"ts", "ц"}, {"ch", "ч"}, {"sh", "ш"}, {"yu", "ю"}, {"ya", "я"}, {"yo", "ё"},
+		{"a", "а"}, {"b", "б"}, {"c", "к"}, {"d", "д"}, {"e", "е"}, {"f", "ф"}, {"g", "г"}, {"h", "х"},
+		{"i", "и"}, {"j", "й"}, {"k", "к"}, {"l", "л"}, {"m", "м"}, {"n", "н"}, {"o", "о"}, {"p", "п"},
+		{"q", "к"}, {"r", "р"}, {"s", "с"}, {"t", "т"}, {"u", "у"}, {"v", "в"}, {"w", "в"}, {"x", "кс"},
+		{"y", "й"}, {"z", "з"},
+	}
+)
+
+// ToLatin lowercases s and replaces Cyrillic letters with their Latin spelling.
+func ToLatin(s string) string {
+	var b strings.Builder
+
+	for _, r := range strings.ToLower(s) {
+		if latin, ok := _toLatin[r]; ok {
+			b.WriteString(latin)
+			continue
+		}
+
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// ToCyrillic lowercases s and replaces Latin letters with their Cyrillic spelling.
+func ToCyrillic(s string) string {
+	s = strings.ToLower(s)
+
+	var b strings.Builder
+
+next:
+	for len(s) > 0 {
+		for _, t := range _toCyrillic {
+			if strings.HasPrefix(s, t.latin) {
+				b.WriteString(t.cyrillic)
+				s = s[len(t.latin):]
+
+				continue next
+			}
+		}
+
+		// Not a Latin letter, copy the whole rune
+		r, size := utf8.DecodeRuneInString(s)
+		b.WriteRune(r)
+		s = s[size:]
+	}
+
+	return b.String()
+}
+
+// Variants returns the lowercased s followed by its distinct Latin and Cyrillic spellings.
+func Variants(s string) []string {
+	variants := []string{strings.ToLower(s)}
+
+	for _, v := range []string{ToLatin(s), ToCyrillic(s)} {
+		if !slices.Contains(variants, v) {
+			variants = append(variants, v)
+		}
+	}
+
+	return variants
+}